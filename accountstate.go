@@ -0,0 +1,259 @@
+package nanogo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// InFlightBlock is a published block of an account that is not confirmed yet,
+// Height: the height of the block in the account chain,
+// Hash: the hash of the block,
+// Subtype: the subtype of the block,
+// Amount: the amount of the block in raw,
+// BalanceAfter: the balance of the account after the block in raw,
+// PublishedAt: the time the block was published.
+type InFlightBlock struct {
+	Height       uint64
+	Hash         string
+	Subtype      string
+	Amount       string
+	BalanceAfter string
+	PublishedAt  time.Time
+}
+
+// AccountState models the pipeline of the unconfirmed blocks published by an account,
+// confirmations advance the state in height order and every block is reported to
+// OnConfirmed exactly once, for exact accounting of in-flight blocks,
+// Account: the wallet address,
+// OnConfirmed: called once per confirmed block, in height order (optional).
+type AccountState struct {
+	Account     string
+	OnConfirmed func(block InFlightBlock) // optional
+
+	mu        sync.Mutex
+	confirmed FrontierState
+	height    uint64
+	untracked uint64
+	base      FrontierState
+	head      FrontierState
+	pending   []InFlightBlock
+}
+
+// NewAccountState creates the state of an account from its account info,
+// blocks already published but not confirmed are part of the head but not tracked,
+// the confirmed state staying at the confirmation height frontier of the node until
+// a tracked block above them is confirmed,
+// address: the wallet address,
+// info: the account info (GetAccountInfo),
+// returns the account state or an error.
+func NewAccountState(address string, info AccountInfo) (*AccountState, error) {
+	height, err := strconv.ParseUint(info.ConfirmationHeight, 10, 64)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse confirmation height (%s)", info.ConfirmationHeight)
+	}
+
+	blocks, err := strconv.ParseUint(info.BlockCount, 10, 64)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse block count (%s)", info.BlockCount)
+	}
+
+	s := &AccountState{
+		Account: address,
+		confirmed: FrontierState{
+			Frontier:       info.ConfirmationHeightFrontier,
+			Balance:        info.ConfirmedBalance,
+			Representative: info.ConfirmedRepresentative,
+		},
+		height: height,
+		head: FrontierState{
+			Frontier:       info.Frontier,
+			Balance:        info.Balance,
+			Representative: info.Representative,
+		},
+	}
+
+	s.base = s.confirmed
+
+	if blocks > height {
+		// untracked unconfirmed blocks: start the tracked pipeline above them
+		s.untracked = blocks - height
+		s.base = s.head
+	}
+
+	return s, nil
+}
+
+// Head returns the state after the last published block, confirmed or not.
+func (s *AccountState) Head() FrontierState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.head
+}
+
+// Confirmed returns the state after the last confirmed block and its height.
+func (s *AccountState) Confirmed() (FrontierState, uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.confirmed, s.height
+}
+
+// InFlight returns the published blocks that are not confirmed, in height order.
+func (s *AccountState) InFlight() []InFlightBlock {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]InFlightBlock{}, s.pending...)
+}
+
+// Published records a block published on top of the head,
+// block: the block, its height is set to the next height,
+// representative: the representative of the block,
+// returns the recorded block.
+func (s *AccountState) Published(block InFlightBlock, representative string) InFlightBlock {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	block.Height = s.height + s.untracked + uint64(len(s.pending)) + 1
+
+	if block.PublishedAt.IsZero() {
+		block.PublishedAt = time.Now()
+	}
+
+	s.pending = append(s.pending, block)
+	s.head = FrontierState{Frontier: block.Hash, Balance: block.BalanceAfter, Representative: representative}
+
+	return block
+}
+
+// Confirm confirms a block and every block below it, since blocks are cemented in
+// height order, and calls OnConfirmed for the newly confirmed blocks,
+// hash: the hash of the confirmed block,
+// returns the newly confirmed blocks (none for an unknown or already confirmed hash).
+func (s *AccountState) Confirm(hash string) []InFlightBlock {
+	s.mu.Lock()
+
+	index := -1
+
+	for i, b := range s.pending {
+		if strings.EqualFold(b.Hash, hash) {
+			index = i
+			break
+		}
+	}
+
+	if index < 0 {
+		s.mu.Unlock()
+		return nil
+	}
+
+	confirmed := append([]InFlightBlock{}, s.pending[:index+1]...)
+	last := confirmed[len(confirmed)-1]
+	s.pending = append([]InFlightBlock{}, s.pending[index+1:]...)
+	s.height = last.Height
+	s.untracked = 0
+	s.confirmed = FrontierState{Frontier: last.Hash, Balance: last.BalanceAfter, Representative: s.confirmed.Representative}
+	s.base = s.confirmed
+
+	if len(s.pending) == 0 {
+		s.confirmed.Representative = s.head.Representative
+	}
+
+	s.mu.Unlock()
+
+	if s.OnConfirmed != nil {
+		for _, b := range confirmed {
+			s.OnConfirmed(b)
+		}
+	}
+
+	return confirmed
+}
+
+// Rollback drops a block that was not accepted by the network and every block above it,
+// the head returns to the block below,
+// hash: the hash of the dropped block,
+// returns the dropped blocks.
+func (s *AccountState) Rollback(hash string) []InFlightBlock {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, b := range s.pending {
+		if !strings.EqualFold(b.Hash, hash) {
+			continue
+		}
+
+		dropped := append([]InFlightBlock{}, s.pending[i:]...)
+		s.pending = s.pending[:i]
+		s.head = s.base
+
+		if i > 0 {
+			prev := s.pending[i-1]
+			s.head = FrontierState{Frontier: prev.Hash, Balance: prev.BalanceAfter, Representative: s.head.Representative}
+		}
+
+		return dropped
+	}
+
+	return nil
+}
+
+// Observe confirms the blocks of the account delivered by a confirmation stream,
+// m: the confirmation.
+func (s *AccountState) Observe(m ConfirmationMessage) {
+	if canonicalAddress(m.Account) == canonicalAddress(s.Account) {
+		s.Confirm(m.Hash)
+	}
+}
+
+// Send sends from the account on top of its head without querying the node and
+// records the block in flight,
+// c: the client publishing the block,
+// privateKey: the private key of the account,
+// toAddress: the destination wallet address,
+// raw: the amount to send in raw,
+// returns the in-flight block or an error.
+func (s *AccountState) Send(c *Client, privateKey [32]byte, toAddress, raw string) (InFlightBlock, error) {
+	addr, err := privateKeyToAddress(privateKey)
+
+	if err != nil {
+		return InFlightBlock{}, err
+	}
+
+	if canonicalAddress(addr) != canonicalAddress(s.Account) {
+		return InFlightBlock{}, fmt.Errorf("private key is not the key of %s", s.Account)
+	}
+
+	toAddress, err = c.resolveDestination(toAddress)
+
+	if err != nil {
+		return InFlightBlock{}, err
+	}
+
+	if err := c.checkCounterparty(toAddress, Outgoing); err != nil {
+		return InFlightBlock{}, err
+	}
+
+	defer c.lockAccount(addr)()
+
+	head := s.Head()
+	balance, err := BalanceAfterSend(head.Balance, raw)
+
+	if err != nil {
+		return InFlightBlock{}, err
+	}
+
+	hash, err := c.send(privateKey, addr, head, toAddress, raw)
+
+	if err != nil {
+		return InFlightBlock{}, err
+	}
+
+	return s.Published(InFlightBlock{Hash: hash, Subtype: "send", Amount: raw, BalanceAfter: balance}, head.Representative), nil
+}
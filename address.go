@@ -0,0 +1,82 @@
+package nanogo
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Address is a wallet address, validated when it is parsed, decoded or scanned.
+type Address string
+
+// ParseAddress parses a wallet address,
+// address: the wallet address (nano_ or xrb_ prefix),
+// returns the address with the nano_ prefix or an error if it is invalid.
+func ParseAddress(address string) (Address, error) {
+	if !AddressIsValid(address) {
+		return "", fmt.Errorf("invalid address (%s)", address)
+	}
+
+	return Address(canonicalAddress(address)), nil
+}
+
+// String returns the wallet address.
+func (a Address) String() string {
+	return string(a)
+}
+
+// PublicKey returns the public key of the address,
+// returns the public key or an error.
+func (a Address) PublicKey() ([32]byte, error) {
+	return AddressToPublicKey(string(a))
+}
+
+// MarshalText encodes the address, it is also used by encoding/json.
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a), nil
+}
+
+// UnmarshalText decodes and validates an address, it is also used by encoding/json,
+// text: the wallet address (empty for no address),
+// returns an error if the address is invalid.
+func (a *Address) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*a = ""
+		return nil
+	}
+
+	v, err := ParseAddress(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*a = v
+
+	return nil
+}
+
+// Value stores the address as a string, an empty address is stored as NULL.
+func (a Address) Value() (driver.Value, error) {
+	if a == "" {
+		return nil, nil
+	}
+
+	return string(a), nil
+}
+
+// Scan reads and validates an address from a database column,
+// src: the value of the column (string, []byte or NULL),
+// returns an error if the address is invalid.
+func (a *Address) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = ""
+		return nil
+	case string:
+		return a.UnmarshalText([]byte(v))
+	case []byte:
+		return a.UnmarshalText(v)
+	default:
+		return fmt.Errorf("could not scan address from %T", src)
+	}
+}
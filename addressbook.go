@@ -0,0 +1,192 @@
+package nanogo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AddressBook maps wallet addresses to human labels, for history exports and summaries,
+// safe for concurrent use, the zero value is an empty address book.
+type AddressBook struct {
+	mu     sync.RWMutex
+	labels map[string]string
+}
+
+// Set labels a wallet,
+// address: the wallet address,
+// label: the label of the wallet.
+func (b *AddressBook) Set(address, label string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.labels == nil {
+		b.labels = map[string]string{}
+	}
+
+	b.labels[canonicalAddress(address)] = label
+}
+
+// Delete removes the label of a wallet,
+// address: the wallet address.
+func (b *AddressBook) Delete(address string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.labels, canonicalAddress(address))
+}
+
+// Label gets the label of a wallet,
+// address: the wallet address,
+// returns the label and true, or false if the wallet is not labeled.
+func (b *AddressBook) Label(address string) (string, bool) {
+	if b == nil {
+		return "", false
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	label, ok := b.labels[canonicalAddress(address)]
+
+	return label, ok
+}
+
+// Len returns the count of labeled wallets.
+func (b *AddressBook) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.labels)
+}
+
+// MarshalJSON encodes the address book as an object of labels by wallet address.
+func (b *AddressBook) MarshalJSON() ([]byte, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.labels == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(b.labels)
+}
+
+// UnmarshalJSON decodes an object of labels by wallet address,
+// data: the JSON object,
+// returns an error.
+func (b *AddressBook) UnmarshalJSON(data []byte) error {
+	var labels map[string]string
+
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return err
+	}
+
+	for address, label := range labels {
+		b.Set(address, label)
+	}
+
+	return nil
+}
+
+// LabeledBlock is a block of the history of a wallet with the label of its counterparty,
+// Hash: the hash of the block,
+// Height: the height of the block,
+// Type: the type of the block (send or receive),
+// Account: the counterparty wallet address,
+// Label: the label of the counterparty (empty if unlabeled),
+// Amount: the amount of the block in raw,
+// LocalTimestamp: the local timestamp of the block,
+// Confirmed: whether the block is confirmed,
+// Review: true if the counterparty is not in the address book.
+type LabeledBlock struct {
+	Hash           string `json:"hash"`
+	Height         string `json:"height"`
+	Type           string `json:"type"`
+	Account        string `json:"account"`
+	Label          string `json:"label"`
+	Amount         string `json:"amount"`
+	LocalTimestamp string `json:"local_timestamp"`
+	Confirmed      string `json:"confirmed"`
+	Review         bool   `json:"review"`
+}
+
+// LabelHistory labels the counterparties of a history,
+// history: the history of a wallet,
+// book: the address book (optional, nothing is flagged for review without it),
+// returns the labeled blocks in the order of the history.
+func LabelHistory(history AccountHistory, book *AddressBook) []LabeledBlock {
+	blocks := make([]LabeledBlock, 0, len(history.History))
+
+	for _, h := range history.History {
+		label, ok := book.Label(h.Account)
+
+		blocks = append(blocks, LabeledBlock{
+			Hash:           h.Hash,
+			Height:         h.Height,
+			Type:           h.Type,
+			Account:        h.Account,
+			Label:          label,
+			Amount:         h.Amount,
+			LocalTimestamp: h.LocalTimestamp,
+			Confirmed:      h.Confirmed,
+			Review:         book != nil && h.Account != "" && !ok,
+		})
+	}
+
+	return blocks
+}
+
+// ExportHistory exports the whole history of a wallet with the labels of its counterparties,
+// newest first like GetAccountHistory,
+// address: the wallet address,
+// format: the format of the export (csv or json),
+// book: the address book (optional, nothing is flagged for review without it),
+// returns the export or an error.
+func (c *Client) ExportHistory(address, format string, book *AddressBook) ([]byte, error) {
+	history, err := c.HistorySinceHeight(address, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := LabelHistory(history, book)
+
+	switch strings.ToLower(format) {
+	case "json":
+		return json.MarshalIndent(blocks, "", "  ")
+	case "csv":
+		var buf bytes.Buffer
+		cw := csv.NewWriter(&buf)
+		cw.Write([]string{"hash", "height", "type", "account", "label", "amount", "local_timestamp", "confirmed", "review"})
+
+		for _, b := range blocks {
+			cw.Write([]string{b.Hash, b.Height, b.Type, b.Account, b.Label, b.Amount, b.LocalTimestamp, b.Confirmed, fmt.Sprint(b.Review)})
+		}
+
+		cw.Flush()
+
+		return buf.Bytes(), cw.Error()
+	default:
+		return nil, fmt.Errorf("unknown export format (%s)", format)
+	}
+}
+
+// unlabeled returns the sorted addresses of a set missing from the address book.
+func (b *AddressBook) unlabeled(addresses map[string]bool) []string {
+	var missing []string
+
+	for a := range addresses {
+		if _, ok := b.Label(a); !ok {
+			missing = append(missing, a)
+		}
+	}
+
+	sort.Strings(missing)
+
+	return missing
+}
@@ -0,0 +1,169 @@
+package nanogo
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StaleReceivable is a receivable block that remains unreceived,
+// Account: the receiving wallet address,
+// Hash: the hash of the send block,
+// Source: the source wallet address,
+// Amount: the amount of the block,
+// SentAt: the local timestamp of the send block (zero if unknown),
+// Age: the age of the block (zero if unknown),
+// Old: whether the block is older than the maximum age,
+// Large: whether the amount is at least the threshold.
+type StaleReceivable struct {
+	Account string
+	Hash    string
+	Source  string
+	Amount  Raw
+	SentAt  time.Time
+	Age     time.Duration
+	Old     bool
+	Large   bool
+}
+
+// ReceivableAging reports receivable blocks that are older than a maximum age or
+// above an amount threshold, so stuck auto-receive pipelines are noticed,
+// Client: the client of the node,
+// Accounts: the watched wallet addresses,
+// MaxAge: the age after which a receivable block is reported (optional),
+// Threshold: the amount from which a receivable block is reported (optional),
+// Interval: the interval between checks of Run (default 1 minute),
+// OnAlert: called once per reported block by Run,
+// OnError: called with errors of the checks of Run (optional).
+type ReceivableAging struct {
+	Client    *Client
+	Accounts  []string
+	MaxAge    time.Duration // optional
+	Threshold Raw           // optional
+	Interval  time.Duration
+	OnAlert   func(r StaleReceivable)
+	OnError   func(err error) // optional
+
+	mu      sync.Mutex
+	alerted map[string]bool
+}
+
+// Check checks the watched wallets once,
+// returns the reported receivable blocks or an error.
+func (a *ReceivableAging) Check() ([]StaleReceivable, error) {
+	var stale []StaleReceivable
+	now := time.Now()
+
+	for _, addr := range a.Accounts {
+		receivable, err := a.Client.GetReceivable(addr)
+
+		if err != nil {
+			return stale, err
+		}
+
+		if len(receivable.Blocks) == 0 {
+			continue
+		}
+
+		hashes := make([]string, 0, len(receivable.Blocks))
+
+		for h := range receivable.Blocks {
+			hashes = append(hashes, h)
+		}
+
+		var infos map[string]BlockInfo
+
+		if a.MaxAge > 0 {
+			infos, err = a.Client.GetBlocksInfo(hashes)
+
+			if err != nil {
+				return stale, err
+			}
+		}
+
+		for _, h := range hashes {
+			b := receivable.Blocks[h]
+			amount, err := ParseAmount(b.Amount)
+
+			if err != nil {
+				return stale, err
+			}
+
+			r := StaleReceivable{
+				Account: addr,
+				Hash:    h,
+				Source:  b.Source,
+				Amount:  amount,
+				Large:   !a.Threshold.IsZero() && amount.Cmp(a.Threshold) >= 0,
+			}
+
+			if ts, err := strconv.ParseInt(infos[strings.ToUpper(h)].LocalTimestamp, 10, 64); err == nil && ts > 0 {
+				r.SentAt = time.Unix(ts, 0)
+				r.Age = now.Sub(r.SentAt)
+				r.Old = r.Age >= a.MaxAge
+			}
+
+			if r.Old || r.Large {
+				stale = append(stale, r)
+			}
+		}
+	}
+
+	return stale, nil
+}
+
+// Run checks the watched wallets every interval until the context is done and calls
+// OnAlert once for every reported block,
+// ctx: the context stopping the checks,
+// returns the error of the context.
+func (a *ReceivableAging) Run(ctx context.Context) error {
+	interval := a.Interval
+
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		stale, err := a.Check()
+
+		if err != nil && a.OnError != nil {
+			a.OnError(err)
+		}
+
+		for _, r := range stale {
+			if a.OnAlert != nil && a.markAlerted(r.Hash) {
+				a.OnAlert(r)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *ReceivableAging) markAlerted(hash string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.alerted == nil {
+		a.alerted = map[string]bool{}
+	}
+
+	hash = strings.ToUpper(hash)
+
+	if a.alerted[hash] {
+		return false
+	}
+
+	a.alerted[hash] = true
+
+	return true
+}
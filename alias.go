@@ -0,0 +1,119 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AliasResolver resolves human-readable handles (e.g. @name or name@domain.com)
+// to wallet addresses.
+type AliasResolver interface {
+	Resolve(alias string) (string, error)
+}
+
+// StaticAliases is an AliasResolver backed by a map of handles to wallet addresses.
+type StaticAliases map[string]string
+
+// Resolve resolves a handle,
+// alias: the handle to resolve,
+// returns the wallet address or an error.
+func (s StaticAliases) Resolve(alias string) (string, error) {
+	addr, ok := s[alias]
+
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrAliasNotResolved, alias)
+	}
+
+	return addr, nil
+}
+
+// WellKnownResolver is an AliasResolver using the .well-known/nano-currency.json
+// naming standard (as served by nano.to), resolving name@domain.com against
+// domain.com and @name or name against DefaultDomain,
+// DefaultDomain: the domain of handles without one (default nano.to),
+// HTTPClient: the HTTP client of the requests (optional).
+type WellKnownResolver struct {
+	DefaultDomain string
+	HTTPClient    *http.Client // optional
+}
+
+// Resolve resolves a handle,
+// alias: the handle to resolve,
+// returns the wallet address or an error.
+func (r WellKnownResolver) Resolve(alias string) (string, error) {
+	name := strings.TrimPrefix(alias, "@")
+	domain := r.DefaultDomain
+
+	if domain == "" {
+		domain = "nano.to"
+	}
+
+	if i := strings.LastIndex(name, "@"); i != -1 {
+		name, domain = name[:i], name[i+1:]
+	}
+
+	if name == "" || domain == "" {
+		return "", fmt.Errorf("%w: %s", ErrAliasNotResolved, alias)
+	}
+
+	httpClient := r.HTTPClient
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	u := fmt.Sprintf("https://%s/.well-known/nano-currency.json?names=%s", domain, url.QueryEscape(name))
+	res, err := httpClient.Get(u)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+
+	var body struct {
+		Names []struct {
+			Name    string `json:"name"`
+			Address string `json:"address"`
+		} `json:"names"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("could not decode alias response: %v", err)
+	}
+
+	for _, n := range body.Names {
+		if strings.EqualFold(n.Name, name) {
+			return n.Address, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrAliasNotResolved, alias)
+}
+
+// resolveDestination returns destinations that look like wallet addresses as they are
+// and resolves everything else with the AliasResolver of the client.
+func (c *Client) resolveDestination(destination string) (string, error) {
+	if strings.HasPrefix(destination, "nano_") || strings.HasPrefix(destination, "xrb_") {
+		return destination, nil
+	}
+
+	if c.Resolver == nil {
+		return "", fmt.Errorf("%w: %s (no resolver)", ErrAliasNotResolved, destination)
+	}
+
+	addr, err := c.Resolver.Resolve(destination)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !AddressIsValid(addr) {
+		return "", fmt.Errorf("alias %s resolved to invalid address (%s)", destination, addr)
+	}
+
+	return addr, nil
+}
@@ -0,0 +1,276 @@
+package nanogo
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Amount is an amount of Nano in raw,
+// the zero value is an amount of 0 raw.
+type Amount struct {
+	raw *big.Int
+}
+
+// ParseAmount parses an amount in raw,
+// raw: the amount in raw, a plain integer of at most 128 bits,
+// returns the amount or an error wrapping ErrInvalidAmount, ErrScientificNotation,
+// ErrNegativeAmount or ErrAmountOverflow.
+func ParseAmount(raw string) (Amount, error) {
+	if err := checkAmount(raw, false); err != nil {
+		return Amount{}, err
+	}
+
+	i, ok := new(big.Int).SetString(raw, 10)
+
+	if !ok {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, raw)
+	}
+
+	return newAmount(i, raw)
+}
+
+var scientificNotation = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)[eE][+-]?\d+$`)
+
+// checkAmount rejects what the decimal and big int parsers would accept in surprising ways:
+// scientific notation, signs, whitespace, separators and fractions of integers.
+func checkAmount(value string, fraction bool) error {
+	if scientificNotation.MatchString(value) {
+		return fmt.Errorf("%w: %q", ErrScientificNotation, value)
+	}
+
+	if strings.HasPrefix(value, "-") {
+		return fmt.Errorf("%w: %q", ErrNegativeAmount, value)
+	}
+
+	digits, dots := 0, 0
+
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '.' && fraction:
+			dots++
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+		}
+	}
+
+	if digits == 0 || dots > 1 || strings.HasSuffix(value, ".") {
+		return fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	return nil
+}
+
+// newAmount creates an amount from a parsed raw checking it fits in 128 bits.
+func newAmount(raw *big.Int, value string) (Amount, error) {
+	if raw.BitLen() > 128 {
+		return Amount{}, fmt.Errorf("%w: %q", ErrAmountOverflow, value)
+	}
+
+	return Amount{raw: raw}, nil
+}
+
+// AmountFromBigInt creates an amount from a big int in raw,
+// raw: the amount in raw (copied),
+// returns the amount.
+func AmountFromBigInt(raw *big.Int) Amount {
+	return Amount{raw: new(big.Int).Set(raw)}
+}
+
+// BigInt returns a copy of the amount in raw as a big int.
+func (a Amount) BigInt() *big.Int {
+	if a.raw == nil {
+		return big.NewInt(0)
+	}
+
+	return new(big.Int).Set(a.raw)
+}
+
+// String returns the amount in raw.
+func (a Amount) String() string {
+	return a.BigInt().String()
+}
+
+// Raw is an amount of Nano in raw, it is the same type as Amount.
+type Raw = Amount
+
+// Unit is a denomination of Nano expressed as a power of ten of raw.
+type Unit int32
+
+const (
+	UnitRaw   Unit = 0
+	UnitNyano Unit = 24
+	UnitNano  Unit = 30
+	UnitKnano Unit = 33
+)
+
+// ParseUnit parses an amount in a denomination,
+// value: the decimal amount (e.g. "1.5"),
+// unit: the denomination of the amount,
+// returns the amount or an error wrapping ErrInvalidAmount (also if the amount is finer than a raw),
+// ErrScientificNotation, ErrNegativeAmount or ErrAmountOverflow.
+func ParseUnit(value string, unit Unit) (Amount, error) {
+	if err := checkAmount(value, true); err != nil {
+		return Amount{}, err
+	}
+
+	d, err := decimal.NewFromString(value)
+
+	if err != nil {
+		return Amount{}, fmt.Errorf("%w: %q: %v", ErrInvalidAmount, value, err)
+	}
+
+	d = d.Shift(int32(unit))
+
+	if !d.IsInteger() {
+		return Amount{}, fmt.Errorf("%w: %q is finer than a raw", ErrInvalidAmount, value)
+	}
+
+	return newAmount(d.BigInt(), value)
+}
+
+// ParseNano parses an amount in Nano,
+// nano: the decimal amount in Nano,
+// returns the amount or an error.
+func ParseNano(nano string) (Amount, error) {
+	return ParseUnit(nano, UnitNano)
+}
+
+// Add returns the sum of two amounts.
+func (a Amount) Add(b Amount) Amount {
+	return Amount{raw: new(big.Int).Add(a.BigInt(), b.BigInt())}
+}
+
+// Sub returns the difference of two amounts,
+// b: the amount to subtract,
+// returns the difference or an error if b is greater than the amount.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if a.Cmp(b) < 0 {
+		return Amount{}, fmt.Errorf("insufficient amount (%s < %s)", a, b)
+	}
+
+	return Amount{raw: new(big.Int).Sub(a.BigInt(), b.BigInt())}, nil
+}
+
+// Cmp compares two amounts,
+// returns -1, 0 or +1 if the amount is less than, equal to or greater than b.
+func (a Amount) Cmp(b Amount) int {
+	return a.BigInt().Cmp(b.BigInt())
+}
+
+// IsZero reports whether the amount is 0 raw.
+func (a Amount) IsZero() bool {
+	return a.raw == nil || a.raw.Sign() == 0
+}
+
+// Format returns the exact decimal amount in a denomination.
+func (a Amount) Format(unit Unit) string {
+	return decimal.NewFromBigInt(a.BigInt(), -int32(unit)).String()
+}
+
+// Nano returns the exact decimal amount in Nano.
+func (a Amount) Nano() string {
+	return a.Format(UnitNano)
+}
+
+// MarshalJSON encodes the amount as a string in raw like the node does.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON decodes an amount in raw from a string or a number,
+// data: the JSON value,
+// returns an error.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+
+	if s == "" || s == "null" {
+		*a = Amount{}
+		return nil
+	}
+
+	v, err := ParseAmount(s)
+
+	if err != nil {
+		return err
+	}
+
+	*a = v
+
+	return nil
+}
+
+// MarshalText encodes the amount in raw.
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an amount in raw,
+// text: the amount in raw,
+// returns an error.
+func (a *Amount) UnmarshalText(text []byte) error {
+	v, err := ParseAmount(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*a = v
+
+	return nil
+}
+
+// Value stores the amount in raw as a string, for NUMERIC or text columns.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan reads an amount in raw from a database column,
+// src: the value of the column (string, []byte or int64, NULL is 0 raw),
+// returns an error.
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = Amount{}
+		return nil
+	case string:
+		return a.UnmarshalText([]byte(v))
+	case []byte:
+		return a.UnmarshalText(v)
+	case int64:
+		if v < 0 {
+			return fmt.Errorf("could not scan negative amount (%d)", v)
+		}
+
+		*a = Amount{raw: big.NewInt(v)}
+		return nil
+	default:
+		return fmt.Errorf("could not scan amount from %T", src)
+	}
+}
+
+// BalanceRaw returns the balance of the account info as an amount.
+func (i AccountInfo) BalanceRaw() (Raw, error) {
+	return ParseAmount(i.Balance)
+}
+
+// BalanceRaw returns the balance as an amount.
+func (b AccountBalance) BalanceRaw() (Raw, error) {
+	return ParseAmount(b.Balance)
+}
+
+// ReceivableRaw returns the receivable balance as an amount.
+func (b AccountBalance) ReceivableRaw() (Raw, error) {
+	return ParseAmount(b.Receivable)
+}
+
+// BalanceRaw returns the balance of the block as an amount.
+func (b Block) BalanceRaw() (Raw, error) {
+	return ParseAmount(b.Balance)
+}
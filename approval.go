@@ -0,0 +1,190 @@
+package nanogo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PendingSend is a prepared send waiting for approval,
+// ID: the id of the send, the hash of its block,
+// Send: the signed send block,
+// Account: the sending wallet address,
+// To: the destination wallet address,
+// Amount: the amount in raw,
+// Created: the time the send was prepared.
+type PendingSend struct {
+	ID      string      `json:"id"`
+	Send    DelayedSend `json:"send"`
+	Account string      `json:"account"`
+	To      string      `json:"to"`
+	Amount  string      `json:"amount"`
+	Created time.Time   `json:"created"`
+}
+
+// ApprovalGate holds the sends of a client for approval, for treasury controls: when set on
+// the client, every send it publishes (Send, FastSend, WalletAccount.Send, PresignedBatch.Publish,
+// Release) is signed and returns ErrApprovalPending, and the block is only broadcast once
+// Approve is called,
+// Verify: the second signature or 2FA hook checked by Approve before broadcasting (optional),
+// OnPending: called with every new pending send, e.g. to notify approvers (optional).
+type ApprovalGate struct {
+	Verify    func(send PendingSend) error // optional
+	OnPending func(send PendingSend)       // optional
+
+	mu      sync.Mutex
+	pending map[string]pendingApproval
+}
+
+type pendingApproval struct {
+	send   PendingSend
+	client *Client
+}
+
+// hold records a signed send for approval and returns ErrApprovalPending with its id.
+func (g *ApprovalGate) hold(c *Client, d DelayedSend) error {
+	id, err := d.Block.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	toAddress, raw, err := c.sendDetails(d.Block)
+
+	if err != nil {
+		return err
+	}
+
+	send := PendingSend{ID: id, Send: d, Account: d.Block.Account, To: toAddress, Amount: raw, Created: time.Now()}
+
+	g.mu.Lock()
+
+	if g.pending == nil {
+		g.pending = map[string]pendingApproval{}
+	}
+
+	g.pending[id] = pendingApproval{send: send, client: c}
+	g.mu.Unlock()
+
+	if g.OnPending != nil {
+		g.OnPending(send)
+	}
+
+	return fmt.Errorf("%w: %s", ErrApprovalPending, id)
+}
+
+// Pending returns the sends waiting for approval, oldest first.
+func (g *ApprovalGate) Pending() []PendingSend {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	sends := make([]PendingSend, 0, len(g.pending))
+
+	for _, p := range g.pending {
+		sends = append(sends, p.send)
+	}
+
+	sort.Slice(sends, func(i, j int) bool { return sends[i].Created.Before(sends[j].Created) })
+
+	return sends
+}
+
+// Approve checks a pending send with the Verify hook and broadcasts it, a send whose
+// account moved on since it was prepared (e.g. by approving another send of the account
+// prepared on the same frontier) is dropped and has to be sent again,
+// id: the id of the pending send,
+// returns the block hash, ErrFrontierMismatch if the account moved on, or an error.
+func (g *ApprovalGate) Approve(id string) (string, error) {
+	g.mu.Lock()
+	p, ok := g.pending[id]
+	g.mu.Unlock()
+
+	if !ok {
+		return "", fmt.Errorf("unknown pending send %s", id)
+	}
+
+	if g.Verify != nil {
+		if err := g.Verify(p.send); err != nil {
+			return "", err
+		}
+	}
+
+	hash, err := p.client.release(p.send.Send, false)
+
+	if err == nil || errors.Is(err, ErrFrontierMismatch) {
+		g.mu.Lock()
+		delete(g.pending, id)
+		g.mu.Unlock()
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return hash, nil
+}
+
+// Reject drops a pending send without broadcasting it,
+// id: the id of the pending send,
+// returns an error if there is no pending send with the id.
+func (g *ApprovalGate) Reject(id string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.pending[id]; !ok {
+		return fmt.Errorf("unknown pending send %s", id)
+	}
+
+	delete(g.pending, id)
+
+	return nil
+}
+
+// sendDetails returns the destination and the amount in raw of a send block, the amount
+// from the balance of its previous block.
+func (c *Client) sendDetails(block Block) (string, string, error) {
+	toAddress := block.LinkAsAccount
+
+	if toAddress == "" {
+		link, err := decodeHash(block.Link)
+
+		if err != nil {
+			return "", "", err
+		}
+
+		var pubKey [32]byte
+		copy(pubKey[:], link)
+
+		if toAddress, err = PublicKeyToAddress(pubKey); err != nil {
+			return "", "", err
+		}
+	}
+
+	previous, err := c.GetBlockInfo(block.Previous)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	before, err := ParseAmount(previous.Balance)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	after, err := ParseAmount(block.Balance)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	amount, err := before.Sub(after)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return toAddress, amount.String(), nil
+}
@@ -0,0 +1,165 @@
+package nanogo
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/zenitria/nanogo/ed25519"
+	"golang.org/x/crypto/blake2b"
+	"time"
+)
+
+// AttestedAddress is an address of a DerivationReport with its proof of control,
+// Index: the index of the account,
+// Address: the wallet address of the account,
+// Signature: the signature of the digest of the report by the account in hex.
+type AttestedAddress struct {
+	Index     int    `json:"index"`
+	Address   string `json:"address"`
+	Signature string `json:"signature"`
+}
+
+// DerivationReport is a signed report of the addresses derived from a seed, proving to
+// auditors which accounts a custodian controls without revealing the seed,
+// Scheme: the identifier of the derivation scheme,
+// Start: the index of the first account,
+// Count: the count of accounts,
+// Created: the time the report was created,
+// Accounts: the derived accounts, each signing the digest of the report.
+type DerivationReport struct {
+	Scheme   string            `json:"scheme"`
+	Start    int               `json:"start"`
+	Count    int               `json:"count"`
+	Created  time.Time         `json:"created"`
+	Accounts []AttestedAddress `json:"accounts"`
+}
+
+// Digest returns the blake2b hash of the report without its signatures, the message
+// signed by every account,
+// returns the digest or an error.
+func (r DerivationReport) Digest() ([32]byte, error) {
+	addrs := make([]DerivedAddress, len(r.Accounts))
+
+	for i, a := range r.Accounts {
+		addrs[i] = DerivedAddress{Index: a.Index, Address: a.Address}
+	}
+
+	data, err := json.Marshal(struct {
+		Scheme    string           `json:"scheme"`
+		Start     int              `json:"start"`
+		Count     int              `json:"count"`
+		Created   time.Time        `json:"created"`
+		Addresses []DerivedAddress `json:"addresses"`
+	}{r.Scheme, r.Start, r.Count, r.Created.UTC(), addrs})
+
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return blake2b.Sum256(data), nil
+}
+
+// Verify checks the report covers its range of indexes and every account signed its digest,
+// returns an error naming the first invalid account.
+func (r DerivationReport) Verify() error {
+	if len(r.Accounts) != r.Count {
+		return fmt.Errorf("report has %d accounts instead of %d", len(r.Accounts), r.Count)
+	}
+
+	digest, err := r.Digest()
+
+	if err != nil {
+		return err
+	}
+
+	for i, a := range r.Accounts {
+		if a.Index != r.Start+i {
+			return fmt.Errorf("account %d has index %d instead of %d", i, a.Index, r.Start+i)
+		}
+
+		pubKey, err := AddressToPublicKey(a.Address)
+
+		if err != nil {
+			return fmt.Errorf("account %d: %v", a.Index, err)
+		}
+
+		sig, err := hex.DecodeString(a.Signature)
+
+		if err != nil || !ed25519.Verify(pubKey, digest[:], sig) {
+			return fmt.Errorf("account %d (%s): invalid signature", a.Index, a.Address)
+		}
+	}
+
+	return nil
+}
+
+// AttestAddresses creates a DerivationReport of a range of accounts of the wallet,
+// signed by every account,
+// start: the index of the first account,
+// count: the count of accounts,
+// returns the report or an error.
+func (w *Wallet) AttestAddresses(start, count int) (DerivationReport, error) {
+	addrs, err := w.DeriveAddresses(start, count)
+
+	if err != nil {
+		return DerivationReport{}, err
+	}
+
+	r := DerivationReport{
+		Scheme:   w.derivation.String(),
+		Start:    start,
+		Count:    count,
+		Created:  time.Now().UTC().Truncate(time.Second),
+		Accounts: make([]AttestedAddress, count),
+	}
+
+	for i, a := range addrs {
+		r.Accounts[i] = AttestedAddress{Index: a.Index, Address: a.Address}
+	}
+
+	digest, err := r.Digest()
+
+	if err != nil {
+		return DerivationReport{}, err
+	}
+
+	for i := range r.Accounts {
+		privKey, err := w.derive(r.Accounts[i].Index)
+
+		if err != nil {
+			return DerivationReport{}, err
+		}
+
+		pubKey, err := PrivateKeyToPublicKey(privKey)
+
+		if err != nil {
+			return DerivationReport{}, err
+		}
+
+		sig, err := ed25519.Sign(pubKey, privKey, digest[:])
+		wipe(privKey[:])
+
+		if err != nil {
+			return DerivationReport{}, err
+		}
+
+		r.Accounts[i].Signature = hex.EncodeToString(sig)
+	}
+
+	return r, nil
+}
+
+// AttestAddresses creates a DerivationReport of a range of accounts of a seed (Nano derivation),
+// seed: the seed in hex,
+// start: the index of the first account,
+// count: the count of accounts,
+// returns the report or an error.
+func AttestAddresses(seed string, start, count int) (DerivationReport, error) {
+	w, err := NewWalletFromSeed(seed, nil)
+
+	if err != nil {
+		return DerivationReport{}, err
+	}
+
+	return w.AttestAddresses(start, count)
+}
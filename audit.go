@@ -0,0 +1,91 @@
+package nanogo
+
+import (
+	"strings"
+)
+
+// Discrepancy reasons of a history audit.
+const (
+	DiscrepancyMissing     = "missing"     // the verifier does not know the block (rolled back or forked)
+	DiscrepancyUnconfirmed = "unconfirmed" // confirmed on the client but not on the verifier
+	DiscrepancyConfirmed   = "confirmed"   // confirmed on the verifier but not on the client
+	DiscrepancyMismatch    = "mismatch"    // the block differs between the nodes
+)
+
+// HistoryDiscrepancy is a history entry the verifier node does not agree with,
+// Hash: the hash of the block,
+// Height: the height of the block,
+// Type: the type of the history entry (send or receive),
+// Amount: the amount of the history entry in raw,
+// Reason: the discrepancy (DiscrepancyMissing, DiscrepancyUnconfirmed, DiscrepancyConfirmed or DiscrepancyMismatch),
+// Detail: the differing field for a mismatch.
+type HistoryDiscrepancy struct {
+	Hash   string
+	Height string
+	Type   string
+	Amount string
+	Reason string
+	Detail string
+}
+
+// AuditHistory cross-checks the confirmed flag of each history entry of a wallet against
+// blocks_info from a second node, so unconfirmed or rolled-back deposits are not credited,
+// address: the wallet address,
+// verifier: the client of the second node,
+// returns the discrepancies (none if the nodes agree) or an error.
+func (c *Client) AuditHistory(address string, verifier *Client) ([]HistoryDiscrepancy, error) {
+	history, err := c.GetAccountHistory(address, -1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var discrepancies []HistoryDiscrepancy
+
+	for start := 0; start < len(history.History); start += 100 {
+		end := start + 100
+
+		if end > len(history.History) {
+			end = len(history.History)
+		}
+
+		entries := history.History[start:end]
+		hashes := make([]string, len(entries))
+
+		for i, h := range entries {
+			hashes[i] = h.Hash
+		}
+
+		blocks, _, err := verifier.lookupBlocks(hashes)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, h := range entries {
+			d := HistoryDiscrepancy{Hash: h.Hash, Height: h.Height, Type: h.Type, Amount: h.Amount}
+			info, ok := blocks[strings.ToUpper(h.Hash)]
+
+			switch {
+			case !ok:
+				d.Reason = DiscrepancyMissing
+			case canonicalAddress(info.BlockAccount) != canonicalAddress(address):
+				d.Reason, d.Detail = DiscrepancyMismatch, "account "+info.BlockAccount
+			case info.Height != h.Height:
+				d.Reason, d.Detail = DiscrepancyMismatch, "height "+info.Height
+			case info.Amount != h.Amount:
+				d.Reason, d.Detail = DiscrepancyMismatch, "amount "+info.Amount
+			case strings.EqualFold(h.Confirmed, "true") && !strings.EqualFold(info.Confirmed, "true"):
+				d.Reason = DiscrepancyUnconfirmed
+			case !strings.EqualFold(h.Confirmed, "true") && strings.EqualFold(info.Confirmed, "true"):
+				d.Reason = DiscrepancyConfirmed
+			default:
+				continue
+			}
+
+			discrepancies = append(discrepancies, d)
+		}
+	}
+
+	return discrepancies, nil
+}
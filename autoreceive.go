@@ -0,0 +1,213 @@
+package nanogo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ReceiveWindow is a daily time window of an AutoReceiver,
+// Start: the start of the window as the time since midnight,
+// End: the end of the window as the time since midnight, before Start for windows crossing midnight,
+// Days: the days of the window, by the day it starts on (optional, every day by default).
+type ReceiveWindow struct {
+	Start time.Duration
+	End   time.Duration
+	Days  []time.Weekday // optional
+}
+
+// Contains checks if a time is in the window,
+// t: the time to check, in the time zone of the window,
+// returns true if the time is in the window, false otherwise.
+func (w ReceiveWindow) Contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	since := t.Sub(midnight)
+	day := t.Weekday()
+
+	if w.End <= w.Start && since < w.End {
+		// the part after midnight belongs to the window of the previous day
+		day = (day + 6) % 7
+	} else if since < w.Start || (w.End > w.Start && since >= w.End) {
+		return false
+	}
+
+	if len(w.Days) == 0 {
+		return true
+	}
+
+	for _, d := range w.Days {
+		if d == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AutoReceiveRun is the result of an AutoReceiver pass over an account,
+// Index: the index of the account in the wallet,
+// Address: the wallet address of the account,
+// Result: the result of the receives,
+// Err: the error of the receives.
+type AutoReceiveRun struct {
+	Index   int
+	Address string
+	Result  ReceiveResult
+	Err     error
+}
+
+// AutoReceiver receives the receivable blocks of wallet accounts in the background,
+// publishing only in its time windows and within its work budget, the other blocks
+// stay receivable until the next pass,
+// Wallet: the wallet of the accounts,
+// Indexes: the indexes of the received accounts,
+// Options: the options of the receives, the Limit is capped by the work budget (optional),
+// Windows: the time windows receives are published in (optional, any time by default),
+// Location: the time zone of the windows (optional, local by default),
+// WorkPerHour: the maximum count of receive blocks (each needing work) per rolling hour (optional),
+// Interval: the interval between passes of Run (default 1 minute),
+// OnRun: called with the result of every account of every pass (optional).
+type AutoReceiver struct {
+	Wallet      *Wallet
+	Indexes     []int
+	Options     ReceiveOptions  // optional
+	Windows     []ReceiveWindow // optional
+	Location    *time.Location  // optional
+	WorkPerHour int             // optional
+	Interval    time.Duration
+	OnRun       func(run AutoReceiveRun) // optional
+
+	mu   sync.Mutex
+	work []time.Time
+}
+
+// Open checks if a time is in one of the windows of the receiver,
+// now: the time to check,
+// returns true if receives can be published, false otherwise.
+func (r *AutoReceiver) Open(now time.Time) bool {
+	if len(r.Windows) == 0 {
+		return true
+	}
+
+	if r.Location != nil {
+		now = now.In(r.Location)
+	}
+
+	for _, w := range r.Windows {
+		if w.Contains(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Budget returns the count of receive blocks the receiver can still publish in the
+// rolling hour,
+// now: the current time,
+// returns the remaining budget, or -1 if the budget is unlimited.
+func (r *AutoReceiver) Budget(now time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.budget(now)
+}
+
+func (r *AutoReceiver) budget(now time.Time) int {
+	if r.WorkPerHour <= 0 {
+		return -1
+	}
+
+	cutoff := now.Add(-time.Hour)
+	i := 0
+
+	for i < len(r.work) && !r.work[i].After(cutoff) {
+		i++
+	}
+
+	r.work = r.work[i:]
+
+	if len(r.work) >= r.WorkPerHour {
+		return 0
+	}
+
+	return r.WorkPerHour - len(r.work)
+}
+
+// ReceiveDue receives the receivable blocks of the accounts if the time is in a
+// window, spending at most the remaining work budget,
+// now: the current time,
+// returns the results of the accounts (none outside the windows) or an error if
+// an account could not be derived.
+func (r *AutoReceiver) ReceiveDue(now time.Time) ([]AutoReceiveRun, error) {
+	if !r.Open(now) {
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var runs []AutoReceiveRun
+
+	for _, i := range r.Indexes {
+		budget := r.budget(now)
+
+		if budget == 0 {
+			break
+		}
+
+		account, err := r.Wallet.Account(i)
+
+		if err != nil {
+			return runs, err
+		}
+
+		options := r.Options
+
+		if budget > 0 && (options.Limit <= 0 || options.Limit > budget) {
+			options.Limit = budget
+		}
+
+		run := AutoReceiveRun{Index: i, Address: account.Address}
+		run.Result, run.Err = account.ReceiveAllWithOptions(options)
+
+		for n := len(run.Result.Hashes) + len(run.Result.Failed); n > 0; n-- {
+			r.work = append(r.work, now)
+		}
+
+		runs = append(runs, run)
+
+		if r.OnRun != nil {
+			r.OnRun(run)
+		}
+	}
+
+	return runs, nil
+}
+
+// Run receives the receivable blocks of the accounts every interval until the
+// context is done,
+// ctx: the context stopping the receiver,
+// returns the error of the context or an error if an account could not be derived.
+func (r *AutoReceiver) Run(ctx context.Context) error {
+	interval := r.Interval
+
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.ReceiveDue(time.Now()); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package nanogo
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// BalanceAfterSend computes the balance of an account after a send,
+// balance: the balance before the send in raw,
+// amount: the amount sent in raw,
+// returns the balance after the send in raw, ErrInsufficientBalance if the amount is
+// bigger than the balance, or an error.
+func BalanceAfterSend(balance, amount string) (string, error) {
+	bal, raw, err := parseBalanceMath(balance, amount)
+
+	if err != nil {
+		return "", err
+	}
+
+	if bal.Cmp(raw) < 0 {
+		return "", fmt.Errorf("%w: %s < %s", ErrInsufficientBalance, balance, amount)
+	}
+
+	return bal.Sub(bal, raw).String(), nil
+}
+
+// BalanceAfterReceive computes the balance of an account after a receive,
+// balance: the balance before the receive in raw,
+// amount: the amount received in raw,
+// returns the balance after the receive in raw, ErrBalanceOverflow if it does not fit
+// in 128 bits, or an error.
+func BalanceAfterReceive(balance, amount string) (string, error) {
+	bal, raw, err := parseBalanceMath(balance, amount)
+
+	if err != nil {
+		return "", err
+	}
+
+	if bal.Add(bal, raw).BitLen() > 128 {
+		return "", fmt.Errorf("%w: %s + %s", ErrBalanceOverflow, balance, amount)
+	}
+
+	return bal.String(), nil
+}
+
+func parseBalanceMath(balance, amount string) (*big.Int, *big.Int, error) {
+	bal, ok := new(big.Int).SetString(balance, 10)
+
+	if !ok || bal.Sign() < 0 || bal.BitLen() > 128 {
+		return nil, nil, fmt.Errorf("could not parse balance (%s)", balance)
+	}
+
+	raw, ok := new(big.Int).SetString(amount, 10)
+
+	if !ok || raw.Sign() < 0 || raw.BitLen() > 128 {
+		return nil, nil, fmt.Errorf("could not parse amount (%s)", amount)
+	}
+
+	return bal, raw, nil
+}
@@ -0,0 +1,181 @@
+package nanogo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxRaw is the largest amount in raw, 2^128-1.
+const maxRaw = "340282366920938463463374607431768211455"
+
+// base32Alphabet is the alphabet of the base32 encoding of addresses.
+const base32Alphabet = "13456789abcdefghijkmnopqrstuwxyz"
+
+// base32Index maps the characters of base32Alphabet to their values, -1 for the others.
+var base32Index = func() [256]int8 {
+	var index [256]int8
+
+	for i := range index {
+		index[i] = -1
+	}
+
+	for i := 0; i < len(base32Alphabet); i++ {
+		index[base32Alphabet[i]] = int8(i)
+	}
+
+	return index
+}()
+
+// RawToNanoBatch converts raw amounts to nano amounts like RawToNano, without big
+// numbers and with the results sharing a single allocation, for reporting jobs
+// converting millions of rows,
+// raws: the raws to convert, plain integers of at most 128 bits,
+// returns the nano amounts in the order of the raws or an error naming the index of
+// the first invalid raw and wrapping ErrInvalidAmount, ErrScientificNotation,
+// ErrNegativeAmount or ErrAmountOverflow.
+func RawToNanoBatch(raws []string) ([]string, error) {
+	var tmp [48]byte
+	size := 0
+
+	for i, raw := range raws {
+		digits, err := rawDigits(raw)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not parse raw %d: %w", i, err)
+		}
+
+		size += len(appendNano(tmp[:0], digits))
+	}
+
+	var sb strings.Builder
+	sb.Grow(size)
+	ends := make([]int, len(raws))
+
+	for i, raw := range raws {
+		digits, _ := rawDigits(raw)
+		sb.Write(appendNano(tmp[:0], digits))
+		ends[i] = sb.Len()
+	}
+
+	all := sb.String()
+	nanos := make([]string, len(raws))
+	start := 0
+
+	for i, end := range ends {
+		nanos[i] = all[start:end]
+		start = end
+	}
+
+	return nanos, nil
+}
+
+// rawDigits checks a raw and returns its digits without leading zeros.
+func rawDigits(raw string) (string, error) {
+	if err := checkAmount(raw, false); err != nil {
+		return "", err
+	}
+
+	digits := strings.TrimLeft(raw, "0")
+
+	if len(digits) > len(maxRaw) || (len(digits) == len(maxRaw) && digits > maxRaw) {
+		return "", fmt.Errorf("%w: %q", ErrAmountOverflow, raw)
+	}
+
+	return digits, nil
+}
+
+// appendNano appends the digits of a raw without leading zeros formatted as Nano
+// like Amount.Nano.
+func appendNano(dst []byte, digits string) []byte {
+	const scale = int(UnitNano)
+
+	if digits == "" {
+		return append(dst, '0')
+	}
+
+	whole, frac := "0", digits
+
+	if len(digits) > scale {
+		whole, frac = digits[:len(digits)-scale], digits[len(digits)-scale:]
+	}
+
+	dst = append(dst, whole...)
+	pad := scale - len(frac)
+
+	if frac = strings.TrimRight(frac, "0"); frac == "" {
+		return dst
+	}
+
+	dst = append(dst, '.')
+
+	for ; pad > 0; pad-- {
+		dst = append(dst, '0')
+	}
+
+	return append(dst, frac...)
+}
+
+// AddressesToPublicKeys converts wallet addresses to public keys like AddressToPublicKey,
+// decoding the base32 without big numbers into a single slice, for reporting jobs
+// converting millions of rows,
+// addresses: the addresses to get the public keys from,
+// returns the public keys in the order of the addresses or an error naming the index
+// of the first invalid address.
+func AddressesToPublicKeys(addresses []string) ([][32]byte, error) {
+	keys := make([][32]byte, len(addresses))
+
+	for i, a := range addresses {
+		if err := decodeAddress(&keys[i], a); err != nil {
+			return nil, fmt.Errorf("address %d: %w", i, err)
+		}
+	}
+
+	return keys, nil
+}
+
+// decodeAddress decodes the public key of an address, the 52 base32 characters of
+// the key encoding 4 zero bits followed by the 256 bits of the key.
+func decodeAddress(key *[32]byte, address string) error {
+	var encoded string
+
+	switch len(address) {
+	case 64:
+		encoded = address[4:56]
+	case 65:
+		encoded = address[5:57]
+	default:
+		return fmt.Errorf("could not parse address (%s)", address)
+	}
+
+	var acc uint
+	bits, k := 0, 0
+
+	for i := 0; i < len(encoded); i++ {
+		v := base32Index[encoded[i]]
+
+		if v < 0 {
+			return fmt.Errorf("'%c' is no legal base32 character", encoded[i])
+		}
+
+		if i == 0 {
+			if v > 1 {
+				return fmt.Errorf("could not parse address (%s)", address)
+			}
+
+			acc, bits = uint(v), 1
+			continue
+		}
+
+		acc = acc<<5 | uint(v)
+		bits += 5
+
+		if bits >= 8 {
+			bits -= 8
+			key[k] = byte(acc >> bits)
+			acc &= 1<<bits - 1
+			k++
+		}
+	}
+
+	return nil
+}
@@ -1,11 +1,15 @@
 package nanogo
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"github.com/zenitria/nanogo/constants"
 	"github.com/zenitria/nanogo/ed25519"
 	"golang.org/x/crypto/blake2b"
 	"math/big"
+	"strings"
 )
 
 // Block is a block of the Nano blockchain,
@@ -19,15 +23,15 @@ import (
 // Signature: the signature of the block,
 // Work: the work of the block.
 type Block struct {
-	Type           string `json:"type"`
-	Account        string `json:"account"`
-	Previous       string `json:"previous"`
-	Representative string `json:"representative"`
-	Balance        string `json:"balance"`
-	Link           string `json:"link"`
-	LinkAsAccount  string `json:"link_as_account"`
-	Signature      string `json:"signature"`
-	Work           string `json:"work"`
+	Type           string `json:"type" protobuf:"1"`
+	Account        string `json:"account" protobuf:"2"`
+	Previous       string `json:"previous" protobuf:"3"`
+	Representative string `json:"representative" protobuf:"4"`
+	Balance        string `json:"balance" protobuf:"5"`
+	Link           string `json:"link" protobuf:"6"`
+	LinkAsAccount  string `json:"link_as_account" protobuf:"7"`
+	Signature      string `json:"signature" protobuf:"8"`
+	Work           string `json:"work" protobuf:"9"`
 }
 
 // Sign signs block,
@@ -63,6 +67,262 @@ func (b *Block) AddWork(work string) {
 	b.Work = work
 }
 
+// Hash returns the hash of the block,
+// returns the hash in uppercase hex or an error.
+func (b *Block) Hash() (string, error) {
+	hash, err := b.hashBytes()
+
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%064X", hash), nil
+}
+
+// VerifySignature checks if the signature of the block was made by its account,
+// epoch blocks are checked with VerifyEpochSignature,
+// returns true if the signature is valid, false otherwise, or an error.
+func (b *Block) VerifySignature() (bool, error) {
+	pubKey, err := AddressToPublicKey(b.Account)
+
+	if err != nil {
+		return false, err
+	}
+
+	return b.verifySignedBy(pubKey)
+}
+
+// VerifyEpochSignature checks if the block is an epoch block of a network: its link
+// is an epoch link, it is signed by the epoch signer of the network for the link and
+// it keeps the balance and representative of the account,
+// network: the network of the block,
+// previous: the previous block of the account, nil for an epoch block opening the
+// account, which has a zero balance and the burn address as representative,
+// returns true if the block is a valid epoch block, false otherwise, or an error.
+func (b *Block) VerifyEpochSignature(network constants.Network, previous *Block) (bool, error) {
+	signer, ok := network.EpochSigner(b.Link)
+
+	if !ok {
+		return false, nil
+	}
+
+	if previous == nil {
+		if b.Previous != "0" && b.Previous != strings.Repeat("0", 64) {
+			return false, nil
+		}
+
+		if b.Balance != "0" || canonicalAddress(b.Representative) != constants.BurnAddress {
+			return false, nil
+		}
+	} else {
+		hash, err := previous.Hash()
+
+		if err != nil {
+			return false, err
+		}
+
+		if !strings.EqualFold(hash, b.Previous) || canonicalAddress(previous.Account) != canonicalAddress(b.Account) {
+			return false, nil
+		}
+
+		if previous.Balance != b.Balance || canonicalAddress(previous.Representative) != canonicalAddress(b.Representative) {
+			return false, nil
+		}
+	}
+
+	key, err := hex.DecodeString(signer)
+
+	if err != nil || len(key) != 32 {
+		return false, fmt.Errorf("could not decode epoch signer (%s)", signer)
+	}
+
+	return b.verifySignedBy([32]byte(key))
+}
+
+// verifySignedBy checks if the signature of the block was made by a public key.
+func (b *Block) verifySignedBy(pubKey [32]byte) (bool, error) {
+	hash, err := b.hashBytes()
+
+	if err != nil {
+		return false, err
+	}
+
+	sig, err := hex.DecodeString(b.Signature)
+
+	if err != nil {
+		return false, fmt.Errorf("could not decode signature: %v", err)
+	}
+
+	return ed25519.Verify(pubKey, hash, sig), nil
+}
+
+// Validate checks the block locally before it is submitted or trusted like
+// ValidateForNetwork on the live network,
+// subtype: the subtype of the block selecting the work threshold (empty accepts the lowest epoch 2 threshold),
+// returns an error wrapping ErrInvalidBlock if the block is invalid.
+func (b *Block) Validate(subtype string) error {
+	return b.ValidateForNetwork(constants.Live, subtype)
+}
+
+// ValidateForNetwork checks the block locally before it is submitted or trusted: the
+// field formats, address checksums, balance range, signature and work. The signature
+// is the one of the account, or of the epoch signer of the network for a block with an
+// epoch link, whose unchanged balance and representative are checked with
+// Client.VerifyEpochBlock,
+// network: the network of the block,
+// subtype: the subtype of the block selecting the work threshold (empty accepts the lowest epoch 2 threshold),
+// returns an error wrapping ErrInvalidBlock if the block is invalid.
+func (b *Block) ValidateForNetwork(network constants.Network, subtype string) error {
+	if b.Type != "state" {
+		return fmt.Errorf("%w: type is not state (%s)", ErrInvalidBlock, b.Type)
+	}
+
+	if !AddressIsValid(b.Account) {
+		return fmt.Errorf("%w: invalid account (%s)", ErrInvalidBlock, b.Account)
+	}
+
+	if !AddressIsValid(b.Representative) {
+		return fmt.Errorf("%w: invalid representative (%s)", ErrInvalidBlock, b.Representative)
+	}
+
+	if _, err := decodeHash(b.Previous); err != nil {
+		return fmt.Errorf("%w: invalid previous: %v", ErrInvalidBlock, err)
+	}
+
+	link, err := decodeHash(b.Link)
+
+	if err != nil {
+		return fmt.Errorf("%w: invalid link: %v", ErrInvalidBlock, err)
+	}
+
+	if b.LinkAsAccount != "" {
+		pubKey, err := AddressToPublicKey(b.LinkAsAccount)
+
+		if err != nil || !AddressIsValid(b.LinkAsAccount) || !bytes.Equal(pubKey[:], link) {
+			return fmt.Errorf("%w: link_as_account does not match link (%s)", ErrInvalidBlock, b.LinkAsAccount)
+		}
+	}
+
+	bal, ok := new(big.Int).SetString(b.Balance, 10)
+
+	if !ok || bal.Sign() < 0 || bal.BitLen() > 128 {
+		return fmt.Errorf("%w: invalid balance (%s)", ErrInvalidBlock, b.Balance)
+	}
+
+	if len(b.Signature) != 128 {
+		return fmt.Errorf("%w: signature length is not 64 bytes", ErrInvalidBlock)
+	}
+
+	// epoch blocks are signed by the epoch signer and sends to the epoch link account by the account
+	valid, err := b.VerifySignature()
+
+	if signer, ok := network.EpochSigner(b.Link); ok && err == nil && !valid {
+		key, decodeErr := hex.DecodeString(signer)
+
+		if decodeErr != nil || len(key) != 32 {
+			return fmt.Errorf("%w: could not decode epoch signer (%s)", ErrInvalidBlock, signer)
+		}
+
+		valid, err = b.verifySignedBy([32]byte(key))
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBlock, err)
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: invalid signature", ErrInvalidBlock)
+	}
+
+	if len(b.Work) != 16 {
+		return fmt.Errorf("%w: work length is not 8 bytes", ErrInvalidBlock)
+	}
+
+	root, err := WorkRoot(*b)
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBlock, err)
+	}
+
+	threshold := WorkThresholdReceive
+
+	if subtype != "" {
+		threshold = WorkThreshold(subtype)
+	}
+
+	valid, err = ValidateWork(root, b.Work, threshold)
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBlock, err)
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: work is below the threshold (%016x)", ErrInvalidBlock, threshold)
+	}
+
+	return nil
+}
+
+// MarshalCanonical marshals the block to byte-stable JSON for hashing, caching and audits,
+// keys are in a fixed order, hashes and signatures are uppercase hex, work is lowercase hex,
+// addresses use the nano_ prefix and the balance is a plain decimal,
+// returns the JSON or an error.
+func (b *Block) MarshalCanonical() ([]byte, error) {
+	bal, ok := new(big.Int).SetString(b.Balance, 10)
+
+	if !ok {
+		return nil, fmt.Errorf("could not convert string to big int")
+	}
+
+	previous := b.Previous
+
+	if previous == "0" {
+		previous = strings.Repeat("0", 64)
+	}
+
+	fields := [][2]string{
+		{"type", b.Type},
+		{"account", canonicalAddress(b.Account)},
+		{"previous", strings.ToUpper(previous)},
+		{"representative", canonicalAddress(b.Representative)},
+		{"balance", bal.String()},
+		{"link", strings.ToUpper(b.Link)},
+		{"link_as_account", canonicalAddress(b.LinkAsAccount)},
+		{"signature", strings.ToUpper(b.Signature)},
+		{"work", strings.ToLower(b.Work)},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(f[0])
+		value, _ := json.Marshal(f[1])
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func canonicalAddress(address string) string {
+	address = strings.ToLower(address)
+
+	if strings.HasPrefix(address, "xrb_") {
+		return "nano_" + address[4:]
+	}
+
+	return address
+}
+
 func (b *Block) hashBytes() ([]byte, error) {
 	msg := make([]byte, 176)
 	msg[31] = 0x6
@@ -74,7 +334,7 @@ func (b *Block) hashBytes() ([]byte, error) {
 
 	copy(msg[32:64], pubKey[:])
 
-	prev, err := hex.DecodeString(b.Previous)
+	prev, err := decodeHash(b.Previous)
 
 	if err != nil {
 		return nil, err
@@ -96,9 +356,13 @@ func (b *Block) hashBytes() ([]byte, error) {
 		return nil, fmt.Errorf("could not convert string to big int")
 	}
 
+	if bal.Sign() < 0 || bal.BitLen() > 128 {
+		return nil, fmt.Errorf("balance out of range (%s)", b.Balance)
+	}
+
 	copy(msg[128:144], bal.FillBytes(make([]byte, 16)))
 
-	link, err := hex.DecodeString(b.Link)
+	link, err := decodeHash(b.Link)
 
 	if err != nil {
 		return nil, err
@@ -110,3 +374,22 @@ func (b *Block) hashBytes() ([]byte, error) {
 
 	return hash[:], nil
 }
+
+// decodeHash decodes a 32 bytes hex value, "0" is accepted for an empty previous block.
+func decodeHash(h string) ([]byte, error) {
+	if h == "0" {
+		return make([]byte, 32), nil
+	}
+
+	b, err := hex.DecodeString(h)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) != 32 {
+		return nil, fmt.Errorf("hash length is not 32 bytes (%s)", h)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,50 @@
+package nanogo_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/zenitria/nanogo"
+	"github.com/zenitria/nanogo/constants"
+)
+
+// devEpochBlock is an epoch v2 block of the dev network opening an account, signed by
+// the epoch signer of the dev network.
+const devEpochBlock = `{
+	"type": "state",
+	"account": "nano_3i1aq1cchnmbn9x5rsbap8b15akfh7wj7pwskuzi7ahz8oq6cobd99d4r3b7",
+	"previous": "0000000000000000000000000000000000000000000000000000000000000000",
+	"representative": "nano_1111111111111111111111111111111111111111111111111111hifc8npp",
+	"balance": "0",
+	"link": "65706F636820763220626C6F636B000000000000000000000000000000000000",
+	"link_as_account": "nano_1sdifxjpia5p8ai86u5hefoi1111111111111111111111111111ngspq7ps",
+	"signature": "F3D5208A17D9CEE5CCBEDB9A3FE8CDF9717A5F3374B897C5AA4AE6D17BBFECC03BE4CC0DCACD5562B38C16ABDC0FF9C6F7CF21E35EC974FE9DD6BD4589404108",
+	"work": "2cdcf7a59b4b60a2"
+}`
+
+func TestValidateEpochBlock(t *testing.T) {
+	var block nanogo.Block
+
+	if err := json.Unmarshal([]byte(devEpochBlock), &block); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := block.ValidateForNetwork(constants.Dev, "epoch"); err != nil {
+		t.Fatal(err)
+	}
+
+	if valid, err := block.VerifyEpochSignature(constants.Dev, nil); err != nil || !valid {
+		t.Fatalf("epoch signature not verified (%v)", err)
+	}
+
+	if err := block.Validate("epoch"); !errors.Is(err, nanogo.ErrInvalidBlock) {
+		t.Fatalf("dev epoch block validated on the live network (%v)", err)
+	}
+
+	block.Balance = "1"
+
+	if err := block.ValidateForNetwork(constants.Dev, "epoch"); !errors.Is(err, nanogo.ErrInvalidBlock) {
+		t.Fatalf("tampered epoch block validated (%v)", err)
+	}
+}
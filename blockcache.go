@@ -0,0 +1,158 @@
+package nanogo
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BlockCache is a bounded LRU cache of block_info results keyed by hash, used by
+// GetBlockInfo and GetBlocksInfo when set on the client,
+// only confirmed blocks are cached, so the confirmation status is never stale,
+// and unknown hashes are cached for NegativeTTL,
+// Size: the maximum count of cached blocks (default 1024, nothing is cached with LowMemory),
+// NegativeTTL: the time an unknown hash is cached (default 1 minute, negative disables it).
+type BlockCache struct {
+	Size        int
+	NegativeTTL time.Duration
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+	hits    int64
+	misses  int64
+}
+
+type blockCacheEntry struct {
+	hash     string
+	info     BlockInfo
+	notFound time.Time
+}
+
+// Len returns the count of cached entries.
+func (c *BlockCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+// Stats returns the count of hits and misses of the cache.
+func (c *BlockCache) Stats() (hits, misses int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.hits, c.misses
+}
+
+// Purge removes every entry of the cache.
+func (c *BlockCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order = nil
+	c.entries = nil
+}
+
+// get returns a cached block info, found is false for a cached unknown hash,
+// ok is false if the hash is not cached.
+func (c *BlockCache) get(hash string) (info BlockInfo, found, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[strings.ToUpper(hash)]
+
+	if !ok {
+		c.misses++
+		return BlockInfo{}, false, false
+	}
+
+	entry := e.Value.(*blockCacheEntry)
+
+	if !entry.notFound.IsZero() {
+		if time.Now().After(entry.notFound) {
+			c.order.Remove(e)
+			delete(c.entries, entry.hash)
+			c.misses++
+
+			return BlockInfo{}, false, false
+		}
+
+		c.hits++
+
+		return BlockInfo{}, false, true
+	}
+
+	c.order.MoveToFront(e)
+	c.hits++
+
+	return entry.info, true, true
+}
+
+func (c *BlockCache) put(hash string, info BlockInfo) {
+	if info.Confirmed != "true" {
+		return
+	}
+
+	c.add(&blockCacheEntry{hash: strings.ToUpper(hash), info: info})
+}
+
+func (c *BlockCache) putNotFound(hash string) {
+	ttl := c.NegativeTTL
+
+	if ttl < 0 {
+		return
+	}
+
+	if ttl == 0 {
+		ttl = time.Minute
+	}
+
+	c.add(&blockCacheEntry{hash: strings.ToUpper(hash), notFound: time.Now().Add(ttl)})
+}
+
+// forget removes the entry of a hash, e.g. a hash cached as unknown before its block was published.
+func (c *BlockCache) forget(hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[strings.ToUpper(hash)]; ok {
+		c.order.Remove(e)
+		delete(c.entries, strings.ToUpper(hash))
+	}
+}
+
+func (c *BlockCache) add(entry *blockCacheEntry) {
+	if !caching {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.order = list.New()
+		c.entries = map[string]*list.Element{}
+	}
+
+	if e, ok := c.entries[entry.hash]; ok {
+		e.Value = entry
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.entries[entry.hash] = c.order.PushFront(entry)
+
+	size := c.Size
+
+	if size <= 0 {
+		size = defaultBlockCacheSize
+	}
+
+	for c.order.Len() > size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*blockCacheEntry).hash)
+	}
+}
@@ -0,0 +1,136 @@
+package nanogo
+
+import (
+	"strconv"
+	"sync"
+)
+
+// cachedAccount returns the account state known after the last block published by the
+// client, the cache being keyed by canonical address like the account locks.
+func (c *Client) cachedAccount(address string) (FrontierState, bool) {
+	s := c.state()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.accounts[canonicalAddress(address)]
+
+	if ok {
+		c.counters().cacheHits.Add(1)
+	} else {
+		c.counters().cacheMisses.Add(1)
+	}
+
+	return state, ok
+}
+
+func (c *Client) cacheAccount(address string, state FrontierState) {
+	if !caching {
+		return
+	}
+
+	s := c.state()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accounts == nil {
+		s.accounts = map[string]FrontierState{}
+	}
+
+	s.accounts[canonicalAddress(address)] = state
+}
+
+func (c *Client) forgetAccount(address string) {
+	s := c.state()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.accounts, canonicalAddress(address))
+}
+
+// lockAccount serializes reading the state of an account and publishing its blocks
+// across goroutines, so concurrent operations never build on the same frontier,
+// address: the wallet address,
+// returns the function unlocking the account.
+func (c *Client) lockAccount(address string) func() {
+	s := c.state()
+	key := canonicalAddress(address)
+
+	s.mu.Lock()
+
+	if s.locks == nil {
+		s.locks = map[string]*sync.Mutex{}
+	}
+
+	l, ok := s.locks[key]
+
+	if !ok {
+		l = &sync.Mutex{}
+		s.locks[key] = l
+	}
+
+	s.mu.Unlock()
+
+	l.Lock()
+
+	return l.Unlock
+}
+
+// versionUnsure marks an account before epoch 2 that may have been upgraded by its last block.
+const versionUnsure = -1
+
+// rememberVersion caches the epoch version of an account read from account_info.
+func (c *Client) rememberVersion(address, version string) {
+	v, err := strconv.Atoi(version)
+
+	if err != nil {
+		return
+	}
+
+	s := c.state()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.versions == nil {
+		s.versions = map[string]int{}
+	}
+
+	s.versions[canonicalAddress(address)] = v
+}
+
+// upgradedVersion marks an account before epoch 2 as possibly upgraded, after a receive
+// (upgrading when its source is an epoch 2 block) or an epoch block.
+func (c *Client) upgradedVersion(address string) {
+	s := c.state()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := canonicalAddress(address)
+
+	if v, ok := s.versions[key]; ok && v >= 0 && v < 2 {
+		s.versions[key] = versionUnsure
+	}
+}
+
+// workThreshold selects the work threshold of a block from the subtype and the cached epoch
+// version of the account: epoch 2 thresholds for unknown accounts, the epoch 1 threshold
+// before epoch 2 and the highest of both when the account may have been upgraded.
+func (c *Client) workThreshold(address, subtype string) uint64 {
+	s := c.state()
+	s.mu.Lock()
+	v, ok := s.versions[canonicalAddress(address)]
+	s.mu.Unlock()
+
+	v2 := WorkThreshold(subtype)
+
+	if !ok || v >= 2 {
+		return v2
+	}
+
+	v1 := EpochStatus{Version: v}.WorkThreshold(subtype)
+
+	if v == versionUnsure && v2 > v1 {
+		return v2
+	}
+
+	return v1
+}
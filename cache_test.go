@@ -0,0 +1,225 @@
+package nanogo_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/zenitria/nanogo"
+	"github.com/zenitria/nanogo/sandbox"
+)
+
+const testSeed = "6D5B7A3B6F8E0C4A2D19F5E3C7B1A9D8E2F4C6B8A0D3E5F7C9B1A3D5E7F9C1B3"
+
+// newTestLedger returns a sandbox ledger, a client of it and the first addresses of the
+// test seed, the first one funded with an opened balance.
+func newTestLedger(t testing.TB, count int, raw string) (*sandbox.Ledger, *nanogo.Client, []string) {
+	t.Helper()
+
+	l, err := sandbox.New()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := l.Client()
+	addresses := make([]string, count)
+
+	for i := range addresses {
+		key, err := nanogo.SeedToPrivateKey(testSeed, i)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pubKey, err := nanogo.PrivateKeyToPublicKey(key)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if addresses[i], err = nanogo.PublicKeyToAddress(pubKey); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := l.Fund(addresses[0], raw); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.ReceiveAll(testSeed, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	return l, c, addresses
+}
+
+func expectBalance(t *testing.T, c *nanogo.Client, address, raw string) {
+	t.Helper()
+
+	balance, err := c.GetAccountBalance(address)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if balance.Balance != raw {
+		t.Fatalf("balance of %s is %s instead of %s", address, balance.Balance, raw)
+	}
+}
+
+func TestConcurrentSend(t *testing.T) {
+	_, c, addresses := newTestLedger(t, 2, "1000")
+
+	var wg sync.WaitGroup
+	hashes := make([]string, 16)
+	errs := make([]error, len(hashes))
+
+	for i := range hashes {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			if i%2 == 0 {
+				hashes[i], errs[i] = c.Send(addresses[1], "1", testSeed, 0)
+			} else {
+				hashes[i], errs[i] = c.FastSend(addresses[1], "1", testSeed, 0)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	seen := map[string]bool{}
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+
+		if seen[hashes[i]] {
+			t.Fatalf("send %d published %s twice", i, hashes[i])
+		}
+
+		seen[hashes[i]] = true
+	}
+
+	expectBalance(t, c, addresses[0], "984")
+}
+
+func TestConcurrentReceiveAll(t *testing.T) {
+	l, c, addresses := newTestLedger(t, 2, "1")
+
+	for i := 0; i < 20; i++ {
+		if _, err := l.Fund(addresses[1], "10"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	received := map[string]bool{}
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			hashes, err := c.ReceiveAll(testSeed, 1)
+
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, h := range hashes {
+				if received[h] {
+					t.Errorf("%s received twice", h)
+				}
+
+				received[h] = true
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(received) != 20 {
+		t.Fatalf("received %d blocks instead of 20", len(received))
+	}
+
+	expectBalance(t, c, addresses[1], "200")
+}
+
+func TestConcurrentSplitSend(t *testing.T) {
+	_, c, addresses := newTestLedger(t, 3, "1000")
+	total, err := nanogo.ParseAmount("10")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recipients := []nanogo.Weighted{{Address: addresses[1], Weight: 1}, {Address: addresses[2], Weight: 1}}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := c.SplitSend(total, recipients, nanogo.RemainderFirst, testSeed, 0); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	expectBalance(t, c, addresses[0], "920")
+
+	for _, a := range addresses[1:] {
+		balance, err := c.GetAccountBalance(a)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if balance.Receivable != "40" {
+			t.Fatalf("receivable of %s is %s instead of 40", a, balance.Receivable)
+		}
+	}
+}
+
+func TestConcurrentTenantSend(t *testing.T) {
+	_, c, addresses := newTestLedger(t, 2, "1000")
+	tenancy := &nanogo.Tenancy{Client: c, RequestsPerMinute: 60000}
+
+	var wg sync.WaitGroup
+
+	for _, id := range []string{"a", "b", "c", "d"} {
+		tenant, err := tenancy.Add(id, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				if _, err := tenant.Client.Send(addresses[1], "1", testSeed, 0); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+	}
+
+	wg.Wait()
+	expectBalance(t, c, addresses[0], "984")
+}
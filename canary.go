@@ -0,0 +1,155 @@
+package nanogo
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TakeoverAlert is a confirmed block of a watched account that was not published
+// through the process, a sign the seed of the account is compromised,
+// Account: the wallet address of the account,
+// Hash: the hash of the block,
+// Subtype: the subtype of the block,
+// Block: the block,
+// Time: the time the block was delivered.
+type TakeoverAlert struct {
+	Account string    `protobuf:"1"`
+	Hash    string    `protobuf:"2"`
+	Subtype string    `protobuf:"3"`
+	Block   WSBlock   `protobuf:"4"`
+	Time    time.Time `protobuf:"5"`
+}
+
+// TakeoverCanary alerts when a block appears on the chain of a watched account
+// without being published through the clients it is a middleware of, every block
+// of an account but epoch blocks needing its private key,
+// Client: the client polled by the watcher,
+// WS: the WebSocket client of the watcher (optional, polling only without it),
+// Accounts: the watched wallet addresses,
+// OnAlert: called with every unknown block,
+// OnError: called with errors of the watcher (optional),
+// Events: the sink persisting the alerts (optional).
+type TakeoverCanary struct {
+	Client   *Client
+	WS       *WSClient // optional
+	Accounts []string
+	OnAlert  func(alert TakeoverAlert)
+	OnError  func(err error) // optional
+	Events   EventSink       // optional
+
+	mu    sync.Mutex
+	known map[string]bool
+}
+
+// Middleware returns a Middleware recording the blocks published through a client,
+// to add to the middlewares of every client publishing blocks of the watched accounts
+// before any middleware answering requests itself,
+// returns the middleware.
+func (t *TakeoverCanary) Middleware() Middleware {
+	return func(next RPCHandler) RPCHandler {
+		return func(ctx context.Context, data map[string]any) ([]byte, error) {
+			if data["action"] == "process" {
+				// recorded before publishing, the confirmation may arrive before the response
+				if b, ok := data["block"].(Block); ok {
+					if hash, err := b.Hash(); err == nil {
+						t.Expect(hash)
+					}
+				}
+			}
+
+			return next(ctx, data)
+		}
+	}
+}
+
+// Expect records a block published outside of the clients of the canary, e.g. by
+// another trusted process,
+// hash: the hash of the block.
+func (t *TakeoverCanary) Expect(hash string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.known == nil {
+		t.known = map[string]bool{}
+	}
+
+	t.known[strings.ToUpper(hash)] = true
+}
+
+// Check checks a confirmed block, blocks of accounts not in Accounts are ignored,
+// m: the confirmed block,
+// returns the alert and true if the block was not published through the process.
+func (t *TakeoverCanary) Check(m ConfirmationMessage) (TakeoverAlert, bool) {
+	if m.Block.Subtype == "epoch" {
+		return TakeoverAlert{}, false
+	}
+
+	account := m.Account
+
+	if account == "" {
+		account = m.Block.Account
+	}
+
+	if !t.watches(account) {
+		return TakeoverAlert{}, false
+	}
+
+	hash := strings.ToUpper(m.Hash)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.known[hash] {
+		// every block is confirmed once
+		delete(t.known, hash)
+		return TakeoverAlert{}, false
+	}
+
+	return TakeoverAlert{Account: account, Hash: hash, Subtype: m.Block.Subtype, Block: m.Block, Time: m.Time}, true
+}
+
+// watches reports whether an account is one of the watched accounts.
+func (t *TakeoverCanary) watches(account string) bool {
+	for _, a := range t.Accounts {
+		if canonicalAddress(a) == canonicalAddress(account) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Run watches the accounts until the context is done and calls OnAlert with every
+// block not published through the process, blocks confirmed before Run are not checked,
+// ctx: the context stopping the canary,
+// returns the error of the context, or an error if the frontiers of the accounts could
+// not be read to start from, as the canary would miss blocks otherwise.
+func (t *TakeoverCanary) Run(ctx context.Context) error {
+	w := &AccountWatcher{Client: t.Client, WS: t.WS, Accounts: t.Accounts, OnError: t.OnError}
+
+	if err := w.initCheckpoints(); err != nil {
+		return err
+	}
+
+	for m := range w.watch(ctx) {
+		alert, ok := t.Check(m)
+
+		if !ok {
+			continue
+		}
+
+		if t.Events != nil {
+			if err := t.Events.Append(alert); err != nil && t.OnError != nil {
+				t.OnError(err)
+			}
+		}
+
+		if t.OnAlert != nil {
+			t.OnAlert(alert)
+		}
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,161 @@
+package nanogo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ShareSource collects the seed share of an operator, e.g. from a terminal prompt,
+// a hardware token or an authenticated API.
+type ShareSource interface {
+	Share(ctx context.Context, operator string) (string, error)
+}
+
+// CeremonyEvent is an entry of the audit trail of a key share ceremony,
+// Time: the time of the event,
+// Operator: the operator of the event (empty for ceremony events),
+// Action: the action (started, share_collected, share_failed, reconstructed, signed, wiped or failed),
+// Detail: the detail of the action (optional).
+type CeremonyEvent struct {
+	Time     time.Time `json:"time"`
+	Operator string    `json:"operator,omitempty"`
+	Action   string    `json:"action"`
+	Detail   string    `json:"detail,omitempty"`
+}
+
+// Ceremony coordinates the operators of a wallet whose seed is split into shares
+// (see SplitSeed): it collects the shares, reconstructs the seed in locked memory,
+// signs a batch of blocks and wipes the seed,
+// Operators: the operators holding a share,
+// Threshold: the count of shares needed to reconstruct the seed,
+// Source: the source of the shares,
+// Audit: called with every event of the ceremony (optional).
+type Ceremony struct {
+	Operators []string
+	Threshold int
+	Source    ShareSource
+	Audit     func(event CeremonyEvent) // optional
+}
+
+// Sign runs the ceremony and signs a batch of blocks of one account,
+// operators are asked in order until Threshold shares are collected,
+// ctx: the context of the collection,
+// index: the index of the account signing the blocks, from 0 to 2^32-1,
+// blocks: the blocks to sign, they must all belong to the account,
+// returns the signed blocks or an error.
+func (c *Ceremony) Sign(ctx context.Context, index int, blocks []Block) ([]Block, error) {
+	c.audit("", "started", fmt.Sprintf("%d blocks, %d of %d shares", len(blocks), c.Threshold, len(c.Operators)))
+
+	signed, err := c.sign(ctx, index, blocks)
+
+	if err != nil {
+		c.audit("", "failed", err.Error())
+		return nil, err
+	}
+
+	return signed, nil
+}
+
+func (c *Ceremony) sign(ctx context.Context, index int, blocks []Block) ([]Block, error) {
+	if c.Threshold < 2 || c.Threshold > len(c.Operators) {
+		return nil, fmt.Errorf("invalid threshold (%d of %d)", c.Threshold, len(c.Operators))
+	}
+
+	// checked before the operators are asked for their shares
+	i, err := nanoIndex(index)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var shares []Share
+
+	defer func() {
+		for _, s := range shares {
+			wipe(s.Value)
+		}
+	}()
+
+	for _, op := range c.Operators {
+		if len(shares) == c.Threshold {
+			break
+		}
+
+		encoded, err := c.Source.Share(ctx, op)
+
+		if err == nil {
+			var share Share
+			share, err = ParseShare(encoded)
+
+			if err == nil {
+				shares = append(shares, share)
+				c.audit(op, "share_collected", fmt.Sprintf("share %d", share.Index))
+				continue
+			}
+		}
+
+		c.audit(op, "share_failed", err.Error())
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	if len(shares) < c.Threshold {
+		return nil, fmt.Errorf("collected %d of %d shares", len(shares), c.Threshold)
+	}
+
+	seed, err := CombineShares(shares)
+
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := lockMemory(seed)
+
+	defer func() {
+		wipe(seed)
+		unlock()
+		c.audit("", "wiped", "")
+	}()
+
+	c.audit("", "reconstructed", "")
+
+	if len(seed) != 32 {
+		return nil, fmt.Errorf("seed length is not 32 bytes")
+	}
+
+	privKey := deriveNanoKey(seed, i)
+	defer wipe(privKey[:])
+
+	addr, err := privateKeyToAddress(privKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signed := make([]Block, len(blocks))
+
+	for i, b := range blocks {
+		if canonicalAddress(b.Account) != canonicalAddress(addr) {
+			return nil, fmt.Errorf("block %d is not a block of %s", i, addr)
+		}
+
+		if err := b.Sign(privKey); err != nil {
+			return nil, err
+		}
+
+		hash, _ := b.Hash()
+		signed[i] = b
+		c.audit("", "signed", hash)
+	}
+
+	return signed, nil
+}
+
+func (c *Ceremony) audit(operator, action, detail string) {
+	if c.Audit != nil {
+		c.Audit(CeremonyEvent{Time: time.Now(), Operator: operator, Action: action, Detail: detail})
+	}
+}
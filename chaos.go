@@ -0,0 +1,161 @@
+package nanogo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ChaosTransport is an http.RoundTripper injecting faults into requests to a node,
+// for testing the resilience of payment flows against flaky nodes,
+// Base: the wrapped transport (default http.DefaultTransport),
+// Latency: the latency added to every request,
+// Jitter: the maximum random latency added on top of Latency,
+// DropRate: the probability a response is dropped after the node handled the request,
+// CorruptRate: the probability a response body is corrupted,
+// DuplicateRate: the probability a request is delivered twice,
+// Seed: the seed of the fault generator (0 for a time based seed).
+type ChaosTransport struct {
+	Base          http.RoundTripper
+	Latency       time.Duration
+	Jitter        time.Duration
+	DropRate      float64
+	CorruptRate   float64
+	DuplicateRate float64
+	Seed          int64
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// RoundTrip sends a request through the wrapped transport with injected faults,
+// req: the request,
+// returns the response or an error.
+func (t *ChaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	delay := t.Latency
+
+	if t.Jitter > 0 {
+		delay += time.Duration(t.int63n(int64(t.Jitter)))
+	}
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	var body []byte
+
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if t.chance(t.DuplicateRate) {
+		dup := req.Clone(req.Context())
+		dup.Body = io.NopCloser(bytes.NewReader(body))
+		res, err := base.RoundTrip(dup)
+
+		if err == nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	res, err := base.RoundTrip(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if t.chance(t.DropRate) {
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+
+		return nil, fmt.Errorf("chaos: response dropped")
+	}
+
+	if t.chance(t.CorruptRate) {
+		data, err := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		res.Body = io.NopCloser(bytes.NewReader(t.corrupt(data)))
+		res.ContentLength = -1
+		res.Header.Del("Content-Length")
+	}
+
+	return res, nil
+}
+
+// corrupt truncates the body or flips one of its bytes.
+func (t *ChaosTransport) corrupt(data []byte) []byte {
+	if len(data) == 0 {
+		return []byte("{")
+	}
+
+	if t.chance(0.5) {
+		return data[:t.int63n(int64(len(data)))]
+	}
+
+	out := append([]byte{}, data...)
+	out[t.int63n(int64(len(out)))] ^= 0xff
+
+	return out
+}
+
+func (t *ChaosTransport) chance(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.rand().Float64() < p
+}
+
+func (t *ChaosTransport) int63n(n int64) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.rand().Int63n(n)
+}
+
+func (t *ChaosTransport) rand() *rand.Rand {
+	if t.rnd == nil {
+		seed := t.Seed
+
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+
+		t.rnd = rand.New(rand.NewSource(seed))
+	}
+
+	return t.rnd
+}
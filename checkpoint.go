@@ -0,0 +1,219 @@
+package nanogo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LedgerCheckpoint is the manifest published with a ledger snapshot, describing
+// the snapshot file and the ledger it contains. The nodes publish no manifest of
+// their snapshots, so this is the format of the library: a JSON object with the
+// fields below, the checksum covering the file as downloaded and the counts and
+// sample frontiers covering its ledger, which can only be read by a node, so they
+// are checked against a trusted node with VerifyManifest before the snapshot is
+// used and against the node fed with it with VerifyCheckpoint,
+// Network: the network of the ledger (live, beta or test),
+// File: the file name of the snapshot,
+// Size: the size of the snapshot file in bytes,
+// SHA256: the SHA-256 checksum of the snapshot file in hex,
+// BlockCount: the count of blocks in the ledger,
+// CementedCount: the count of cemented blocks in the ledger,
+// AccountCount: the count of accounts in the ledger (optional),
+// Frontiers: frontier hashes of sample accounts by wallet address (optional).
+type LedgerCheckpoint struct {
+	Network       string            `json:"network"`
+	File          string            `json:"file"`
+	Size          int64             `json:"size"`
+	SHA256        string            `json:"sha256"`
+	BlockCount    uint64            `json:"block_count"`
+	CementedCount uint64            `json:"cemented_count"`
+	AccountCount  uint64            `json:"account_count"`
+	Frontiers     map[string]string `json:"frontiers"`
+}
+
+// ParseCheckpoint parses and validates a checkpoint manifest,
+// r: the JSON manifest,
+// returns the checkpoint or an error wrapping ErrInvalidCheckpoint.
+func ParseCheckpoint(r io.Reader) (LedgerCheckpoint, error) {
+	var cp LedgerCheckpoint
+
+	if err := json.NewDecoder(r).Decode(&cp); err != nil {
+		return LedgerCheckpoint{}, fmt.Errorf("%w: %v", ErrInvalidCheckpoint, err)
+	}
+
+	return cp, cp.Validate()
+}
+
+// Validate checks the fields of the checkpoint,
+// returns an error wrapping ErrInvalidCheckpoint if a field is invalid.
+func (cp LedgerCheckpoint) Validate() error {
+	switch cp.Network {
+	case "live", "beta", "test":
+	default:
+		return fmt.Errorf("%w: unknown network (%s)", ErrInvalidCheckpoint, cp.Network)
+	}
+
+	if sum, err := hex.DecodeString(cp.SHA256); err != nil || len(sum) != sha256.Size {
+		return fmt.Errorf("%w: invalid sha256 (%s)", ErrInvalidCheckpoint, cp.SHA256)
+	}
+
+	if cp.Size <= 0 {
+		return fmt.Errorf("%w: invalid size (%d)", ErrInvalidCheckpoint, cp.Size)
+	}
+
+	if cp.BlockCount == 0 || cp.CementedCount > cp.BlockCount {
+		return fmt.Errorf("%w: invalid block counts (%d cemented of %d)", ErrInvalidCheckpoint, cp.CementedCount, cp.BlockCount)
+	}
+
+	if cp.AccountCount > cp.BlockCount {
+		return fmt.Errorf("%w: more accounts than blocks (%d)", ErrInvalidCheckpoint, cp.AccountCount)
+	}
+
+	for account, frontier := range cp.Frontiers {
+		if !AddressIsValid(account) {
+			return fmt.Errorf("%w: invalid account (%s)", ErrInvalidCheckpoint, account)
+		}
+
+		if h, err := hex.DecodeString(frontier); err != nil || len(h) != 32 {
+			return fmt.Errorf("%w: invalid frontier of %s (%s)", ErrInvalidCheckpoint, account, frontier)
+		}
+	}
+
+	return nil
+}
+
+// VerifyFile checks the size and the checksum of a downloaded snapshot file,
+// path: the path of the snapshot file,
+// returns an error wrapping ErrInvalidCheckpoint if the file does not match the checkpoint, or an error.
+func (cp LedgerCheckpoint) VerifyFile(path string) error {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+
+	if err != nil {
+		return err
+	}
+
+	if n != cp.Size {
+		return fmt.Errorf("%w: size is %d, expected %d", ErrInvalidCheckpoint, n, cp.Size)
+	}
+
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, cp.SHA256) {
+		return fmt.Errorf("%w: sha256 is %s, expected %s", ErrInvalidCheckpoint, sum, cp.SHA256)
+	}
+
+	return nil
+}
+
+// VerifyManifest checks a checkpoint against a trusted node, e.g. of a public
+// RPC, before its snapshot is fed to a node: the checkpoint must be of the network
+// of the client, the trusted node must have at least its cemented blocks, as a
+// snapshot can not have cemented more blocks than the network, and the frontiers
+// of its sample accounts must be confirmed blocks of these accounts,
+// cp: the checkpoint,
+// returns an error wrapping ErrInvalidCheckpoint if the checkpoint does not match the network, or an error.
+func (c *Client) VerifyManifest(cp LedgerCheckpoint) error {
+	if c.Network != nil && c.Network.Name != cp.Network {
+		return fmt.Errorf("%w: checkpoint is of the %s network, client of the %s network", ErrInvalidCheckpoint, cp.Network, c.Network.Name)
+	}
+
+	_, cemented, err := c.blockCounts()
+
+	if err != nil {
+		return err
+	}
+
+	if cemented < cp.CementedCount {
+		return fmt.Errorf("%w: network has %d cemented blocks, checkpoint has %d", ErrInvalidCheckpoint, cemented, cp.CementedCount)
+	}
+
+	return c.verifyFrontiers(cp, true)
+}
+
+// VerifyCheckpoint checks a checkpoint against a node fed with its snapshot: the node
+// must have at least the blocks of the checkpoint and know the frontiers of its sample accounts,
+// cp: the checkpoint,
+// returns an error wrapping ErrInvalidCheckpoint if the node does not match the checkpoint, or an error.
+func (c *Client) VerifyCheckpoint(cp LedgerCheckpoint) error {
+	blocks, cemented, err := c.blockCounts()
+
+	if err != nil {
+		return err
+	}
+
+	if blocks < cp.BlockCount || cemented < cp.CementedCount {
+		return fmt.Errorf("%w: node has %d blocks (%d cemented), checkpoint has %d (%d cemented)", ErrInvalidCheckpoint, blocks, cemented, cp.BlockCount, cp.CementedCount)
+	}
+
+	return c.verifyFrontiers(cp, false)
+}
+
+// blockCounts returns the counts of blocks and cemented blocks of the node.
+func (c *Client) blockCounts() (uint64, uint64, error) {
+	count, err := c.GetBlockCount()
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	blocks, err := strconv.ParseUint(count.Count, 10, 64)
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not parse block count (%s)", count.Count)
+	}
+
+	cemented, err := strconv.ParseUint(count.Cemented, 10, 64)
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not parse cemented count (%s)", count.Cemented)
+	}
+
+	return blocks, cemented, nil
+}
+
+// verifyFrontiers checks the frontiers of the sample accounts of a checkpoint are
+// blocks of these accounts known by the node, and confirmed if required.
+func (c *Client) verifyFrontiers(cp LedgerCheckpoint, confirmed bool) error {
+	if len(cp.Frontiers) == 0 {
+		return nil
+	}
+
+	hashes := make([]string, 0, len(cp.Frontiers))
+
+	for _, h := range cp.Frontiers {
+		hashes = append(hashes, h)
+	}
+
+	infos, err := c.GetBlocksInfo(hashes)
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCheckpoint, err)
+	}
+
+	for account, frontier := range cp.Frontiers {
+		info, ok := infos[strings.ToUpper(frontier)]
+
+		if !ok || canonicalAddress(info.BlockAccount) != canonicalAddress(account) {
+			return fmt.Errorf("%w: frontier %s is not a block of %s", ErrInvalidCheckpoint, frontier, account)
+		}
+
+		if confirmed && info.Confirmed != "true" {
+			return fmt.Errorf("%w: frontier %s of %s is not confirmed", ErrInvalidCheckpoint, frontier, account)
+		}
+	}
+
+	return nil
+}
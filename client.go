@@ -2,23 +2,143 @@ package nanogo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/zenitria/nanogo/constants"
 	"io"
-	"math/big"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 )
 
-// Client is a client for the Nano RPC protocol,
+// Client is a client for the Nano RPC protocol, safe for concurrent use by multiple goroutines
+// once configured: its caches and stats are synchronized internally and the operations on the
+// same account are serialized, so concurrent sends never build on the same frontier,
 // Url: the url of the RPC server,
 // AuthHeader: the authentication header of the RPC server (optional).
 // AuthToken: the authorization token of the RPC server (optional),
+// Counterparties: the hook consulted before transacting with a wallet (optional),
+// Difficulty: the difficulty floor work is generated at (optional),
+// Actions: the policy of permitted RPC actions (optional),
+// Resolver: the resolver of destination handles that are not wallet addresses (optional),
+// Middlewares: the middlewares wrapping every RPC request, the first one is the outermost (optional),
+// Work: the provider of the work of published blocks (optional, work_generate RPC by default),
+// HTTPClient: the HTTP client of the requests (optional, http.DefaultClient by default),
+// Urls: the failover RPC servers tried after Url for read actions (optional),
+// Timeout: the timeout of every request attempt (optional),
+// Retries: the number of retries of read actions over all servers (optional),
+// Backoff: the initial delay between retries, doubled after each retry (optional, 200 milliseconds by default),
+// Limits: the per-destination limits of sends (optional),
+// Blocks: the cache of block_info results (optional),
+// ProcessHints: the options of the process requests of published blocks, but Async (optional),
+// ReceiveMinimum: the minimum amount of the blocks received by ReceiveAll, smaller blocks stay receivable (optional),
+// Recent: the recent confirmations consulted before polling block_info (optional),
+// Approvals: the gate holding sends until they are approved (optional),
+// Network: the network of the node, selecting the epoch signers (optional, live by default).
 type Client struct {
-	Url        string
-	AuthHeader string // optional
-	AuthToken  string // optional
+	Url            string
+	AuthHeader     string               // optional
+	AuthToken      string               // optional
+	Counterparties CounterpartyHook     // optional
+	Difficulty     *DifficultyFloor     // optional
+	Actions        *ActionPolicy        // optional
+	Resolver       AliasResolver        // optional
+	Middlewares    []Middleware         // optional
+	Work           WorkProvider         // optional
+	HTTPClient     *http.Client         // optional
+	Urls           []string             // optional
+	Timeout        time.Duration        // optional
+	Retries        int                  // optional
+	Backoff        time.Duration        // optional
+	Limits         *SendLimiter         // optional
+	Blocks         *BlockCache          // optional
+	ProcessHints   *ProcessOptions      // optional
+	ReceiveMinimum Raw                  // optional
+	Recent         *RecentConfirmations // optional
+	Approvals      *ApprovalGate        // optional
+	Network        *constants.Network   // optional
+
+	ctx    context.Context
+	mu     sync.Mutex
+	shared *clientShared
+	stats  *clientStats
+	tracer *tracer
+}
+
+// clientShared is the state shared by a client and the clients derived from it with WithContext.
+type clientShared struct {
+	mu       sync.Mutex
+	accounts map[string]FrontierState
+	locks    map[string]*sync.Mutex
+	versions map[string]int
+	stats    clientStats
+}
+
+// WithContext returns a client with the same configuration and state whose
+// requests use a context, for cancellation and deadlines of whole operations,
+// ctx: the context of the requests,
+// returns the derived client.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	return &Client{
+		Url:            c.Url,
+		AuthHeader:     c.AuthHeader,
+		AuthToken:      c.AuthToken,
+		Counterparties: c.Counterparties,
+		Difficulty:     c.Difficulty,
+		Actions:        c.Actions,
+		Resolver:       c.Resolver,
+		Middlewares:    c.Middlewares,
+		Work:           c.Work,
+		HTTPClient:     c.HTTPClient,
+		Urls:           c.Urls,
+		Timeout:        c.Timeout,
+		Retries:        c.Retries,
+		Backoff:        c.Backoff,
+		Limits:         c.Limits,
+		Blocks:         c.Blocks,
+		ProcessHints:   c.ProcessHints,
+		ReceiveMinimum: c.ReceiveMinimum,
+		Recent:         c.Recent,
+		Approvals:      c.Approvals,
+		Network:        c.Network,
+
+		ctx:    ctx,
+		shared: c.state(),
+		stats:  c.stats,
+	}
+}
+
+// Context returns the context of the requests of the client.
+func (c *Client) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+
+	return c.ctx
+}
+
+// counters returns the stats of the client, its own ones for a tenant client or the
+// shared ones otherwise.
+func (c *Client) counters() *clientStats {
+	if c.stats != nil {
+		return c.stats
+	}
+
+	return &c.state().stats
+}
+
+func (c *Client) state() *clientShared {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.shared == nil {
+		c.shared = &clientShared{}
+	}
+
+	return c.shared
 }
 
 // AccountInfo is the account info of a wallet,
@@ -71,22 +191,24 @@ type AccountBalance struct {
 
 // AccountHistory is the history of a wallet,
 // Account: the wallet address,
-// History: the history of the wallet,
+// History: the history of the wallet, the blocks of raw histories typed by their subtype,
 // Previous: the previous block of the wallet,
 // Error: the error of the request.
 type AccountHistory struct {
-	Account string `json:"account"`
+	Account string `json:"account" protobuf:"1"`
 	History []struct {
-		Type           string `json:"type"`
-		Account        string `json:"account"`
-		Amount         string `json:"amount"`
-		LocalTimestamp string `json:"local_timestamp"`
-		Hash           string `json:"hash"`
-		Confirmed      string `json:"confirmed"`
-	}
-	Previous string `json:"previous"`
-
-	Error any `json:"error"`
+		Type           string `json:"type" protobuf:"1"`
+		Account        string `json:"account" protobuf:"2"`
+		Amount         string `json:"amount" protobuf:"3"`
+		LocalTimestamp string `json:"local_timestamp" protobuf:"4"`
+		Hash           string `json:"hash" protobuf:"5"`
+		Height         string `json:"height" protobuf:"6"`
+		Confirmed      string `json:"confirmed" protobuf:"7"`
+		Subtype        string `json:"subtype,omitempty" protobuf:"8"`
+	} `protobuf:"2"`
+	Previous string `json:"previous" protobuf:"3"`
+
+	Error any `json:"error" protobuf:"-"`
 }
 
 // Receivable is the receivable blocks of a wallet,
@@ -111,16 +233,49 @@ type Representatives struct {
 }
 
 // RPC sends a JSON-RPC request to the RPC server,
-// body: the body of the request,
+// actions not permitted by the ActionPolicy fail with ErrActionDisabled before the request,
+// data: the body of the request,
 // returns the response or an error.
 func (c *Client) RPC(data map[string]any) ([]byte, error) {
+	return c.RPCContext(c.Context(), data)
+}
+
+// RPCContext sends a JSON-RPC request to the RPC server with a context,
+// ctx: the context of the request,
+// data: the body of the request,
+// returns the response or an error.
+func (c *Client) RPCContext(ctx context.Context, data map[string]any) ([]byte, error) {
+	if err := c.checkAction(data); err != nil {
+		return nil, err
+	}
+
+	stats := c.counters()
+	stats.rpcRequests.Add(1)
+
+	res, err := c.handler()(ctx, data)
+
+	if err != nil {
+		stats.rpcErrors.Add(1)
+	}
+
+	return res, err
+}
+
+// post sends the JSON-RPC request over HTTP to one server.
+func (c *Client) post(ctx context.Context, url string, data map[string]any) ([]byte, error) {
 	dataJson, err := json.Marshal(data)
 
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", c.Url, bytes.NewBuffer(dataJson))
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(dataJson))
 
 	if err != nil {
 		return nil, err
@@ -132,7 +287,13 @@ func (c *Client) RPC(data map[string]any) ([]byte, error) {
 		req.Header.Set(c.AuthHeader, c.AuthToken)
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	httpClient := c.HTTPClient
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	res, err := httpClient.Do(req)
 
 	if err != nil {
 		return nil, err
@@ -140,7 +301,37 @@ func (c *Client) RPC(data map[string]any) ([]byte, error) {
 
 	defer res.Body.Close()
 
-	return io.ReadAll(res.Body)
+	if res.StatusCode >= 500 {
+		return nil, fmt.Errorf("%w: %s", ErrServerUnavailable, res.Status)
+	}
+
+	return readResponse(res.Body)
+}
+
+// readResponse decodes the JSON value of a response body, without reading past it,
+// bounded to maxResponseSize with LowMemory.
+func readResponse(body io.Reader) ([]byte, error) {
+	limited := &io.LimitedReader{R: body, N: maxResponseSize + 1}
+
+	if maxResponseSize > 0 {
+		body = limited
+	}
+
+	var data json.RawMessage
+
+	if err := json.NewDecoder(body).Decode(&data); err != nil {
+		if maxResponseSize > 0 && limited.N <= 0 {
+			return nil, fmt.Errorf("response exceeds %d bytes", maxResponseSize)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return []byte{}, nil
+		}
+
+		return nil, fmt.Errorf("could not decode response: %v", err)
+	}
+
+	return data, nil
 }
 
 // GetAccountBalance gets the balance of a wallet,
@@ -162,7 +353,7 @@ func (c *Client) GetAccountBalance(address string) (AccountBalance, error) {
 	json.Unmarshal(res, &body)
 
 	if body.Error != nil {
-		return AccountBalance{}, fmt.Errorf("%v", body.Error)
+		return AccountBalance{}, rpcError(body.Error)
 	}
 
 	return body, nil
@@ -188,14 +379,12 @@ func (c *Client) GetAccountInfo(address string) (AccountInfo, error) {
 	var info AccountInfo
 	json.Unmarshal(res, &info)
 
-	if info.Error == "Account not found" {
-		return AccountInfo{}, ErrAccountNotFound
-	}
-
 	if info.Error != nil {
-		return AccountInfo{}, fmt.Errorf("%v", info.Error)
+		return AccountInfo{}, rpcError(info.Error)
 	}
 
+	c.rememberVersion(address, info.AccountVersion)
+
 	return info, nil
 }
 
@@ -220,7 +409,7 @@ func (c *Client) GetAccountHistory(address string, count int) (AccountHistory, e
 	json.Unmarshal(res, &history)
 
 	if history.Error != nil {
-		return AccountHistory{}, fmt.Errorf("%v", history.Error)
+		return AccountHistory{}, rpcError(history.Error)
 	}
 
 	return history, nil
@@ -230,23 +419,53 @@ func (c *Client) GetAccountHistory(address string, count int) (AccountHistory, e
 // address: the wallet address to get the receivable blocks of,
 // returns the receivable blocks or an error.
 func (c *Client) GetReceivable(address string) (Receivable, error) {
+	return c.GetReceivableWithOptions(address, ReceivableOptions{})
+}
+
+// ReceivableOptions is the options of a receivable request,
+// Threshold: the minimum amount of the blocks in raw (optional),
+// Count: the maximum count of blocks (optional),
+// Sorting: sort the blocks by amount, largest first (optional).
+type ReceivableOptions struct {
+	Threshold string
+	Count     int
+	Sorting   bool
+}
+
+// GetReceivableWithOptions gets the receivable blocks of a wallet,
+// address: the wallet address to get the receivable blocks of,
+// options: the threshold, count and sorting of the request,
+// returns the receivable blocks or an error.
+func (c *Client) GetReceivableWithOptions(address string, options ReceivableOptions) (Receivable, error) {
 	data := map[string]any{
 		"action":  "receivable",
 		"account": address,
 		"source":  "true",
 	}
 
+	if options.Threshold != "" {
+		data["threshold"] = options.Threshold
+	}
+
+	if options.Count > 0 {
+		data["count"] = options.Count
+	}
+
+	if options.Sorting {
+		data["sorting"] = "true"
+	}
+
 	res, err := c.RPC(data)
 
 	if err != nil {
-		return Receivable{}, nil
+		return Receivable{}, err
 	}
 
 	var receivable Receivable
 	json.Unmarshal(res, &receivable)
 
 	if receivable.Error != nil {
-		return Receivable{}, fmt.Errorf("%v", receivable.Error)
+		return Receivable{}, rpcError(receivable.Error)
 	}
 
 	return receivable, nil
@@ -269,89 +488,106 @@ func (c *Client) GetRepresentatives() (Representatives, error) {
 	json.Unmarshal(res, &reps)
 
 	if reps.Error != nil {
-		return Representatives{}, fmt.Errorf("%v", reps.Error)
+		return Representatives{}, rpcError(reps.Error)
 	}
 
 	return reps, nil
 }
 
-// Process processes a block,
-// subtype: the subtype of the block,
-// block: the block to process,
-// returns the block hash or an error.
-func (c *Client) Process(subtype string, block Block) (string, error) {
+// GetAccountsFrontiers gets the frontiers of multiple wallets,
+// addresses: the wallet addresses to get the frontiers of,
+// returns the frontiers by wallet address or an error.
+func (c *Client) GetAccountsFrontiers(addresses []string) (map[string]string, error) {
 	data := map[string]any{
-		"action":     "process",
-		"subtype":    subtype,
-		"json_block": "true",
-		"block":      block,
+		"action":   "accounts_frontiers",
+		"accounts": addresses,
 	}
 
 	res, err := c.RPC(data)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var body struct {
-		Hash string `json:"hash"`
+		Frontiers map[string]string `json:"frontiers"`
 
 		Error any `json:"error"`
 	}
 	json.Unmarshal(res, &body)
 
 	if body.Error != nil {
-		return "", fmt.Errorf("%v", body.Error)
+		return nil, rpcError(body.Error)
+	}
+
+	if body.Frontiers == nil {
+		body.Frontiers = map[string]string{}
 	}
 
-	return body.Hash, nil
+	return body.Frontiers, nil
 }
 
-// GenerateWork generates work for a block using RPC,
-// hash: the block hash to generate work for,
-// returns the work or an error.
-func (c *Client) GenerateWork(block Block) (string, error) {
-	var hash string
+// frontierOf returns the frontier of an account in the response of accounts_frontiers,
+// which may name the account with the other prefix,
+// returns the frontier or an empty string.
+func frontierOf(frontiers map[string]string, address string) string {
+	if frontier, ok := frontiers[address]; ok {
+		return frontier
+	}
 
-	if block.Previous == "0" || block.Previous == "0000000000000000000000000000000000000000000000000000000000000000" {
-		pubKey, err := AddressToPublicKey(block.Account)
+	key := canonicalAddress(address)
 
-		if err != nil {
-			return "", err
+	for a, frontier := range frontiers {
+		if canonicalAddress(a) == key {
+			return frontier
 		}
-
-		hash = fmt.Sprintf("%064X", pubKey)
-	} else {
-		hash = block.Previous
 	}
 
-	data := map[string]any{
-		"action": "work_generate",
-		"hash":   hash,
-	}
+	return ""
+}
 
-	res, err := c.RPC(data)
+// Process processes a block, with the ProcessHints of the client if set but Async, as
+// the published blocks update the account state cache, which only holds blocks the node
+// validated (ProcessWithOptions processes a single block asynchronously),
+// subtype: the subtype of the block,
+// block: the block to process,
+// returns the block hash or an error.
+func (c *Client) Process(subtype string, block Block) (string, error) {
+	if c.ProcessHints != nil {
+		options := *c.ProcessHints
+		options.Async = false
 
-	if err != nil {
-		return "", err
+		return c.ProcessWithOptions(subtype, block, options)
 	}
 
-	var body struct {
-		Work string `json:"work"`
+	return c.ProcessWithOptions(subtype, block, ProcessOptions{})
+}
 
-		Error any `json:"error"`
-	}
-	json.Unmarshal(res, &body)
+// GenerateWork generates work for a block with the WorkProvider of the client
+// (work_generate RPC by default), at the send threshold of the epoch of the account or the DifficultyFloor if it is higher,
+// block: the block to generate work for,
+// returns the work or an error.
+func (c *Client) GenerateWork(block Block) (string, error) {
+	return c.generateWork(block, "send")
+}
 
-	if body.Error != nil {
-		return "", fmt.Errorf("%v", body.Error)
+// GenerateBlockWork generates work for a block like GenerateWork, at the threshold selected
+// from the subtype of the block and the epoch of its account (epoch 2 rules), so no difficulty
+// has to be passed: the epoch 1 threshold for accounts not upgraded yet, the epoch 2 send or
+// receive threshold otherwise,
+// block: the block to generate work for,
+// subtype: the subtype of the block (send, receive, open, change or epoch),
+// returns the work or an error.
+func (c *Client) GenerateBlockWork(block Block, subtype string) (string, error) {
+	if _, err := c.GetEpochStatus(block.Account); err != nil {
+		return "", err
 	}
 
-	return body.Work, nil
+	return c.generateWork(block, subtype)
 }
 
 // Send sends a raw amount of Nano to a wallet,
-// toAddr: the destination wallet address,
+// toAddr: the destination wallet address (or a handle resolved with the AliasResolver),
 // raw: the amount to send in raw,
 // seed: the seed of the sending wallet,
 // index: the index of the sending wallet (usually 0),
@@ -363,41 +599,107 @@ func (c *Client) Send(toAddress, raw, seed string, index int) (string, error) {
 		return "", err
 	}
 
-	pubKey, err := PrivateKeyToPublicKey(privKey)
+	return c.sendKey(toAddress, raw, privKey)
+}
+
+// FastSend sends a raw amount of Nano to a wallet like Send, but skips the
+// account_info request when the account state cached by a previous block of
+// this client is still the frontier of the account,
+// toAddr: the destination wallet address (or a handle resolved with the AliasResolver),
+// raw: the amount to send in raw,
+// seed: the seed of the sending wallet,
+// index: the index of the sending wallet (usually 0),
+// returns the block hash or an error.
+func (c *Client) FastSend(toAddress, raw, seed string, index int) (string, error) {
+	privKey, err := SeedToPrivateKey(seed, index)
 
 	if err != nil {
 		return "", err
 	}
 
-	addr, err := PublicKeyToAddress(pubKey)
+	return c.fastSendKey(toAddress, raw, privKey)
+}
+
+func (c *Client) sendKey(toAddress, raw string, privKey [32]byte) (string, error) {
+	addr, err := privateKeyToAddress(privKey)
 
 	if err != nil {
 		return "", err
 	}
 
-	info, err := c.GetAccountInfo(addr)
+	defer c.lockAccount(addr)()
+
+	return c.sendAccount(privKey, addr, toAddress, raw)
+}
+
+// sendAccount sends from the state of the account on the node, the account must be locked.
+func (c *Client) sendAccount(privKey [32]byte, addr, toAddress, raw string) (string, error) {
+	toAddress, err := c.resolveDestination(toAddress)
 
 	if err != nil {
 		return "", err
 	}
 
-	bal, ok := new(big.Int).SetString(info.ConfirmedBalance, 10)
+	if err := c.checkCounterparty(toAddress, Outgoing); err != nil {
+		return "", err
+	}
 
-	if !ok {
-		return "", fmt.Errorf("could not convert string to big int")
+	state, err := c.sendState(addr)
+
+	if err != nil {
+		return "", err
 	}
 
-	rawBigInt, ok := new(big.Int).SetString(raw, 10)
+	return c.send(privKey, addr, state, toAddress, raw)
+}
+
+func (c *Client) fastSendKey(toAddress, raw string, privKey [32]byte) (string, error) {
+	addr, err := privateKeyToAddress(privKey)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer c.lockAccount(addr)()
+
+	state, ok := c.cachedAccount(addr)
 
 	if !ok {
-		return "", fmt.Errorf("could not convert string to big int")
+		return c.sendAccount(privKey, addr, toAddress, raw)
 	}
 
-	if bal.Cmp(rawBigInt) < 0 {
-		return "", fmt.Errorf("raw is bigger than wallet balance")
+	frontiers, err := c.GetAccountsFrontiers([]string{addr})
+
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.EqualFold(frontierOf(frontiers, addr), state.Frontier) {
+		c.forgetAccount(addr)
+
+		return c.sendAccount(privKey, addr, toAddress, raw)
+	}
+
+	toAddress, err = c.resolveDestination(toAddress)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.checkCounterparty(toAddress, Outgoing); err != nil {
+		return "", err
+	}
+
+	return c.send(privKey, addr, state, toAddress, raw)
+}
+
+func (c *Client) send(privKey [32]byte, addr string, state FrontierState, toAddress, raw string) (string, error) {
+	balAfter, err := BalanceAfterSend(state.Balance, raw)
+
+	if err != nil {
+		return "", err
 	}
 
-	balAfter := new(big.Int).Sub(bal, rawBigInt)
 	rcptPubKey, err := AddressToPublicKey(toAddress)
 
 	if err != nil {
@@ -407,28 +709,78 @@ func (c *Client) Send(toAddress, raw, seed string, index int) (string, error) {
 	block := Block{
 		Type:           "state",
 		Account:        addr,
-		Previous:       info.Frontier,
-		Representative: info.Representative,
-		Balance:        balAfter.String(),
+		Previous:       state.Frontier,
+		Representative: state.Representative,
+		Balance:        balAfter,
 		Link:           fmt.Sprintf("%064X", rcptPubKey),
 		LinkAsAccount:  toAddress,
 	}
 
-	err = block.Sign(privKey)
+	if c.Limits != nil {
+		if err := c.Limits.Allow(toAddress, raw); err != nil {
+			return "", err
+		}
+	}
 
-	if err != nil {
+	return c.publish("send", block, PrivateKeySigner{privKey})
+}
+
+// publish signs the block and publishes it with publishSigned.
+func (c *Client) publish(subtype string, block Block, signer Signer) (string, error) {
+	if err := signer.SignBlock(&block); err != nil {
 		return "", err
 	}
 
-	work, err := c.GenerateWork(block)
+	return c.publishSigned(subtype, block, true)
+}
+
+// publishSigned publishes a signed block, the path of every block the client publishes:
+// a send is held by the ApprovalGate of the client if set and gated is true, otherwise
+// the block gets work if it has none, is processed and the resulting account state is
+// cached for FastSend.
+func (c *Client) publishSigned(subtype string, block Block, gated bool) (string, error) {
+	if gated && subtype == "send" && c.Approvals != nil {
+		return "", c.Approvals.hold(c, DelayedSend{Block: block})
+	}
+
+	if block.Work == "" {
+		work, err := c.generateWork(block, subtype)
+
+		if err != nil {
+			return "", err
+		}
+
+		block.AddWork(work)
+	}
+
+	hash, err := c.Process(subtype, block)
 
 	if err != nil {
 		return "", err
 	}
 
-	block.AddWork(work)
+	if c.Blocks != nil {
+		c.Blocks.forget(hash)
+	}
+
+	stats := c.counters()
+	stats.blocksPublished.Add(1)
+
+	if subtype == "receive" {
+		stats.receivesProcessed.Add(1)
+	}
+
+	if subtype == "receive" || subtype == "epoch" {
+		c.upgradedVersion(block.Account)
+	}
 
-	return c.Process("send", block)
+	c.cacheAccount(block.Account, FrontierState{
+		Frontier:       hash,
+		Balance:        block.Balance,
+		Representative: block.Representative,
+	})
+
+	return hash, nil
 }
 
 // ChangeRepresentative changes the representative of a wallet,
@@ -443,18 +795,18 @@ func (c *Client) ChangeRepresentative(representative, seed string, index int) (s
 		return "", err
 	}
 
-	pubKey, err := PrivateKeyToPublicKey(privKey)
-
-	if err != nil {
-		return "", err
-	}
+	return c.changeRepresentativeKey(representative, privKey)
+}
 
-	addr, err := PublicKeyToAddress(pubKey)
+func (c *Client) changeRepresentativeKey(representative string, privKey [32]byte) (string, error) {
+	addr, err := privateKeyToAddress(privKey)
 
 	if err != nil {
 		return "", err
 	}
 
+	defer c.lockAccount(addr)()
+
 	info, err := c.GetAccountInfo(addr)
 
 	if err != nil {
@@ -466,26 +818,12 @@ func (c *Client) ChangeRepresentative(representative, seed string, index int) (s
 		Account:        addr,
 		Previous:       info.Frontier,
 		Representative: representative,
-		Balance:        info.ConfirmedBalance,
+		Balance:        info.Balance,
 		Link:           "0000000000000000000000000000000000000000000000000000000000000000",
 		LinkAsAccount:  "nano_1111111111111111111111111111111111111111111111111111hifc8npp",
 	}
 
-	err = block.Sign(privKey)
-
-	if err != nil {
-		return "", err
-	}
-
-	work, err := c.GenerateWork(block)
-
-	if err != nil {
-		return "", err
-	}
-
-	block.AddWork(work)
-
-	return c.Process("change", block)
+	return c.publish("change", block, PrivateKeySigner{privKey})
 }
 
 // Receive receives a block,
@@ -502,81 +840,100 @@ func (c *Client) Receive(hash, sourceAddress, raw, seed string, index int) (stri
 		return "", err
 	}
 
-	pubKey, err := PrivateKeyToPublicKey(privKey)
+	return c.receiveKey(hash, sourceAddress, raw, privKey)
+}
+
+func (c *Client) receiveKey(hash, sourceAddress, raw string, privKey [32]byte) (string, error) {
+	if err := c.checkCounterparty(sourceAddress, Incoming); err != nil {
+		return "", err
+	}
+
+	addr, err := privateKeyToAddress(privKey)
 
 	if err != nil {
 		return "", err
 	}
 
-	addr, err := PublicKeyToAddress(pubKey)
+	defer c.lockAccount(addr)()
+
+	state, err := c.receiveState(addr)
 
 	if err != nil {
 		return "", err
 	}
 
+	h, _, err := c.receive(privKey, addr, state, hash, sourceAddress, raw)
+
+	return h, err
+}
+
+// receiveState gets the account state receive blocks are built on,
+// unopened accounts start at a zero frontier with the first online representative.
+func (c *Client) receiveState(addr string) (FrontierState, error) {
 	info, err := c.GetAccountInfo(addr)
 
 	if errors.Is(err, ErrAccountNotFound) {
 		reps, err := c.GetRepresentatives()
 
 		if err != nil {
-			return "", err
+			return FrontierState{}, err
+		}
+
+		if len(reps.Representatives) == 0 {
+			return FrontierState{}, fmt.Errorf("no online representatives")
 		}
 
-		info.ConfirmedBalance = "0"
-		info.Frontier = "0000000000000000000000000000000000000000000000000000000000000000"
-		info.Representative = reps.Representatives[0]
+		return FrontierState{
+			Frontier:       "0000000000000000000000000000000000000000000000000000000000000000",
+			Balance:        "0",
+			Representative: reps.Representatives[0],
+		}, nil
 
 	} else if err != nil {
-		return "", err
+		return FrontierState{}, err
 	}
 
-	bal, ok := new(big.Int).SetString(info.ConfirmedBalance, 10)
-
-	if !ok {
-		return "", fmt.Errorf("could not convert string to big int")
-	}
+	return FrontierState{
+		Frontier:       info.Frontier,
+		Balance:        info.Balance,
+		Representative: info.Representative,
+	}, nil
+}
 
-	rawBigInt, ok := new(big.Int).SetString(raw, 10)
+// receive publishes a receive block on top of state,
+// returns the block hash and the resulting account state.
+func (c *Client) receive(privKey [32]byte, addr string, state FrontierState, hash, sourceAddress, raw string) (string, FrontierState, error) {
+	balAfter, err := BalanceAfterReceive(state.Balance, raw)
 
-	if !ok {
-		return "", fmt.Errorf("could not convert string to big int")
+	if err != nil {
+		return "", state, err
 	}
 
-	balAfter := new(big.Int).Add(bal, rawBigInt)
-
 	block := Block{
 		Type:           "state",
 		Account:        addr,
-		Previous:       info.Frontier,
-		Representative: info.Representative,
-		Balance:        balAfter.String(),
+		Previous:       state.Frontier,
+		Representative: state.Representative,
+		Balance:        balAfter,
 		Link:           hash,
 		LinkAsAccount:  sourceAddress,
 	}
 
-	err = block.Sign(privKey)
+	h, err := c.publish("receive", block, PrivateKeySigner{privKey})
 
 	if err != nil {
-		return "", err
+		return "", state, err
 	}
 
-	work, err := c.GenerateWork(block)
-
-	if err != nil {
-		return "", err
-	}
-
-	block.AddWork(work)
-
-	return c.Process("receive", block)
+	return h, FrontierState{Frontier: h, Balance: block.Balance, Representative: block.Representative}, nil
 }
 
-// ReceiveAll receives all receivable blocks of a wallet,
-// representative: the representative wallet address,
+// ReceiveAll receives all receivable blocks of a wallet, chaining the receive blocks
+// without waiting between them and continuing past failed blocks,
+// blocks from counterparties denied by the CounterpartyHook are left receivable,
 // seed: the seed of the receiving wallet,
 // index: the index of the receiving wallet (usually 0),
-// returns the block hashes or an error.
+// returns the block hashes and an error joining the errors of the failed blocks.
 func (c *Client) ReceiveAll(seed string, index int) ([]string, error) {
 	privKey, err := SeedToPrivateKey(seed, index)
 
@@ -584,37 +941,11 @@ func (c *Client) ReceiveAll(seed string, index int) ([]string, error) {
 		return []string{}, err
 	}
 
-	pubKey, err := PrivateKeyToPublicKey(privKey)
-
-	if err != nil {
-		return []string{}, err
-	}
-
-	addr, err := PublicKeyToAddress(pubKey)
-
-	if err != nil {
-		return []string{}, err
-	}
-
-	receivable, err := c.GetReceivable(addr)
+	result, err := c.receiveAllKey(privKey, ReceiveOptions{}, nil)
 
 	if err != nil {
-		return []string{}, err
-	}
-
-	var hashes []string
-
-	for h, b := range receivable.Blocks {
-		hash, err := c.Receive(h, b.Source, b.Amount, seed, index)
-
-		if err != nil {
-			return []string{}, err
-		}
-
-		hashes = append(hashes, hash)
-
-		time.Sleep(2 * time.Second)
+		return result.Hashes, err
 	}
 
-	return hashes, nil
+	return result.Hashes, result.Err()
 }
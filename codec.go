@@ -0,0 +1,201 @@
+package nanogo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"os"
+	"sync"
+)
+
+// Codec encodes the events persisted by watchers and queues, so embedding
+// applications can match their existing storage formats.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// Reference codecs.
+var (
+	JSONCodec     Codec = jsonCodec{}
+	MsgpackCodec  Codec = msgpackCodec{}
+	ProtobufCodec Codec = protobufCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// msgpackCodec encodes with the json tags of the events, so field names match JSONCodec.
+type msgpackCodec struct{}
+
+func (msgpackCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+
+	return dec.Decode(v)
+}
+
+// protobufCodec encodes proto.Message values as is and other values as the
+// protobuf messages described by the protobuf tags of their fields and ProtobufSchema.
+type protobufCodec struct{}
+
+func (protobufCodec) Marshal(v any) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Marshal(m)
+	}
+
+	data, err := marshalProto(v)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not encode %T as protobuf: %w", v, err)
+	}
+
+	return data, nil
+}
+
+func (protobufCodec) Unmarshal(data []byte, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Unmarshal(data, m)
+	}
+
+	return unmarshalProto(data, v)
+}
+
+// EventSink persists the events of a watcher or a queue.
+type EventSink interface {
+	Append(event any) error
+}
+
+// FileEventLog is an EventSink appending events to a file as length-prefixed records,
+// Path: the path of the file,
+// Codec: the codec of the events (optional, JSONCodec by default).
+type FileEventLog struct {
+	Path  string
+	Codec Codec // optional
+
+	mu sync.Mutex
+}
+
+func (l *FileEventLog) codec() Codec {
+	if l.Codec == nil {
+		return JSONCodec
+	}
+
+	return l.Codec
+}
+
+// Append appends an event to the file,
+// event: the event,
+// returns an error.
+func (l *FileEventLog) Append(event any) error {
+	data, err := l.codec().Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	record := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	copy(record[4:], data)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(record); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// ReplayEvents decodes the events of a log in order, a missing file has no events and
+// a truncated last record (interrupted write) is ignored,
+// log: the event log,
+// fn: called with every event, an error stops the replay,
+// returns an error.
+func ReplayEvents[T any](log *FileEventLog, fn func(event T) error) error {
+	log.mu.Lock()
+	f, err := os.Open(log.Path)
+	log.mu.Unlock()
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	header := make([]byte, 4)
+
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		size := binary.BigEndian.Uint32(header)
+
+		if maxResponseSize > 0 && size > maxResponseSize {
+			return fmt.Errorf("event record exceeds %d bytes", maxResponseSize)
+		}
+
+		data := make([]byte, size)
+
+		if _, err := io.ReadFull(r, data); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		var event T
+
+		if err := log.codec().Unmarshal(data, &event); err != nil {
+			return err
+		}
+
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+}
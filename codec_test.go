@@ -0,0 +1,76 @@
+package nanogo_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zenitria/nanogo"
+)
+
+func TestProtobufCodecRoundTrip(t *testing.T) {
+	amount, err := nanogo.ParseAmount("340282366920938463463374607431768211455")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := nanogo.ConfirmationMessage{Account: "nano_1abc", Amount: amount.String(), Hash: "AB"}
+	m.Block.Subtype = "send"
+	m.Block.Balance = amount.String()
+
+	events := []any{
+		m,
+		nanogo.JournalEvent{Seq: 3, Kind: "receive", Amount: amount.String(), Time: time.Unix(1700000000, 5).UTC()},
+		nanogo.Deposit{Hash: "AB", Fiat: &nanogo.FiatValue{Currency: "USD", Value: "1.5"}, Tags: []string{"A", "", "B"}},
+		[]nanogo.StandingOrder{{ID: "rent", Interval: time.Hour, NextRun: time.Unix(1700000000, 0).UTC()}},
+	}
+
+	for _, event := range events {
+		data, err := nanogo.ProtobufCodec.Marshal(event)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded := reflect.New(reflect.TypeOf(event))
+
+		if err := nanogo.ProtobufCodec.Unmarshal(data, decoded.Interface()); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(decoded.Elem().Interface(), event) {
+			t.Fatalf("decoded %+v instead of %+v", decoded.Elem().Interface(), event)
+		}
+	}
+}
+
+func TestProtobufSchema(t *testing.T) {
+	schema, err := nanogo.ProtobufSchema(nanogo.ConfirmationMessage{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{"string amount = 2;", "WSBlock block = 5;", "Block Block = 1;", "string subtype = 2;", "string work = 9;"} {
+		if !strings.Contains(schema, field) {
+			t.Fatalf("schema has no %q:\n%s", field, schema)
+		}
+	}
+}
+
+func TestProtobufCodecMissingTag(t *testing.T) {
+	event := struct {
+		Hash   string `protobuf:"1"`
+		Amount string
+	}{Hash: "AB", Amount: "1"}
+
+	if _, err := nanogo.ProtobufCodec.Marshal(event); err == nil {
+		t.Fatal("encoded a field without a protobuf tag")
+	}
+
+	if _, err := nanogo.ProtobufSchema(event); err == nil {
+		t.Fatal("described a field without a protobuf tag")
+	}
+}
@@ -0,0 +1,308 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration written as a string (e.g. "5s") in configuration files.
+type Duration time.Duration
+
+// UnmarshalText parses a duration string,
+// text: the duration string,
+// returns an error.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+// MarshalText returns the duration string.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Config is the declarative configuration of the components of the package,
+// RPC: the configuration of the Client,
+// Work: the configuration of the WorkProvider,
+// WebSocket: the configuration of the WSClient (optional),
+// Wallet: the configuration of the Wallet (optional).
+type Config struct {
+	RPC struct {
+		Preset     string   `json:"preset" yaml:"preset"`
+		Url        string   `json:"url" yaml:"url"`
+		Urls       []string `json:"urls" yaml:"urls"`
+		AuthHeader string   `json:"auth_header" yaml:"auth_header"`
+		AuthToken  string   `json:"auth_token" yaml:"auth_token"`
+		Timeout    Duration `json:"timeout" yaml:"timeout"`
+		Retries    int      `json:"retries" yaml:"retries"`
+		Backoff    Duration `json:"backoff" yaml:"backoff"`
+	} `json:"rpc" yaml:"rpc"`
+
+	Work struct {
+		Provider string `json:"provider" yaml:"provider"`
+		Threads  int    `json:"threads" yaml:"threads"`
+	} `json:"work" yaml:"work"`
+
+	WebSocket struct {
+		Url            string   `json:"url" yaml:"url"`
+		PingInterval   Duration `json:"ping_interval" yaml:"ping_interval"`
+		ReconnectDelay Duration `json:"reconnect_delay" yaml:"reconnect_delay"`
+	} `json:"websocket" yaml:"websocket"`
+
+	Wallet struct {
+		Seed           string `json:"seed" yaml:"seed"`
+		Mnemonic       string `json:"mnemonic" yaml:"mnemonic"`
+		Passphrase     string `json:"passphrase" yaml:"passphrase"`
+		LegacyMnemonic bool   `json:"legacy_mnemonic" yaml:"legacy_mnemonic"`
+	} `json:"wallet" yaml:"wallet"`
+}
+
+// LoadConfig loads a configuration file (.json, .yaml or .yml) and applies the
+// NANOGO_ environment variables over it,
+// path: the path of the file,
+// returns the validated configuration or an error.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	format := strings.TrimPrefix(filepath.Ext(path), ".")
+	cfg, err := ParseConfig(data, format)
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.ApplyEnv("NANOGO"); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// ParseConfig parses a configuration,
+// data: the configuration,
+// format: the format of the configuration (json, yaml or yml),
+// returns the configuration (not validated) or an error.
+func ParseConfig(data []byte, format string) (Config, error) {
+	var cfg Config
+	var err error
+
+	switch strings.ToLower(format) {
+	case "json":
+		err = json.Unmarshal(data, &cfg)
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &cfg)
+	default:
+		return Config{}, fmt.Errorf("unknown config format (%s)", format)
+	}
+
+	if err != nil {
+		return Config{}, fmt.Errorf("could not parse config: %v", err)
+	}
+
+	return cfg, nil
+}
+
+// ApplyEnv overrides the configuration with environment variables, e.g. with the
+// NANOGO prefix: NANOGO_RPC_PRESET, NANOGO_RPC_URL, NANOGO_RPC_URLS (comma separated), NANOGO_RPC_AUTH_HEADER,
+// NANOGO_RPC_AUTH_TOKEN, NANOGO_RPC_TIMEOUT, NANOGO_RPC_RETRIES, NANOGO_RPC_BACKOFF,
+// NANOGO_WORK_PROVIDER, NANOGO_WORK_THREADS, NANOGO_WS_URL, NANOGO_WALLET_SEED,
+// NANOGO_WALLET_MNEMONIC and NANOGO_WALLET_PASSPHRASE,
+// prefix: the prefix of the variables,
+// returns an error if a variable could not be parsed.
+func (c *Config) ApplyEnv(prefix string) error {
+	str := func(name string, dst *string) {
+		if v, ok := os.LookupEnv(prefix + "_" + name); ok {
+			*dst = v
+		}
+	}
+
+	dur := func(name string, dst *Duration) error {
+		if v, ok := os.LookupEnv(prefix + "_" + name); ok {
+			if err := dst.UnmarshalText([]byte(v)); err != nil {
+				return fmt.Errorf("could not parse %s_%s: %v", prefix, name, err)
+			}
+		}
+
+		return nil
+	}
+
+	num := func(name string, dst *int) error {
+		if v, ok := os.LookupEnv(prefix + "_" + name); ok {
+			n, err := strconv.Atoi(v)
+
+			if err != nil {
+				return fmt.Errorf("could not parse %s_%s: %v", prefix, name, err)
+			}
+
+			*dst = n
+		}
+
+		return nil
+	}
+
+	str("RPC_PRESET", &c.RPC.Preset)
+	str("RPC_URL", &c.RPC.Url)
+	str("RPC_AUTH_HEADER", &c.RPC.AuthHeader)
+	str("RPC_AUTH_TOKEN", &c.RPC.AuthToken)
+	str("WORK_PROVIDER", &c.Work.Provider)
+	str("WS_URL", &c.WebSocket.Url)
+	str("WALLET_SEED", &c.Wallet.Seed)
+	str("WALLET_MNEMONIC", &c.Wallet.Mnemonic)
+	str("WALLET_PASSPHRASE", &c.Wallet.Passphrase)
+
+	if v, ok := os.LookupEnv(prefix + "_RPC_URLS"); ok {
+		c.RPC.Urls = nil
+
+		for _, u := range strings.Split(v, ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				c.RPC.Urls = append(c.RPC.Urls, u)
+			}
+		}
+	}
+
+	if err := dur("RPC_TIMEOUT", &c.RPC.Timeout); err != nil {
+		return err
+	}
+
+	if err := dur("RPC_BACKOFF", &c.RPC.Backoff); err != nil {
+		return err
+	}
+
+	if err := num("RPC_RETRIES", &c.RPC.Retries); err != nil {
+		return err
+	}
+
+	return num("WORK_THREADS", &c.Work.Threads)
+}
+
+// Validate checks the configuration,
+// returns an error describing the first invalid setting.
+func (c *Config) Validate() error {
+	if c.RPC.Preset != "" {
+		if _, ok := GetNodePreset(c.RPC.Preset); !ok {
+			return fmt.Errorf("config: unknown rpc.preset (%s)", c.RPC.Preset)
+		}
+	} else if c.RPC.Url == "" {
+		return fmt.Errorf("config: rpc.url or rpc.preset is required")
+	}
+
+	if c.RPC.Retries < 0 {
+		return fmt.Errorf("config: rpc.retries must not be negative")
+	}
+
+	switch c.Work.Provider {
+	case "", "rpc", "local":
+	default:
+		return fmt.Errorf("config: unknown work.provider (%s)", c.Work.Provider)
+	}
+
+	if c.Work.Threads < 0 {
+		return fmt.Errorf("config: work.threads must not be negative")
+	}
+
+	if c.WebSocket.Url != "" && !strings.HasPrefix(c.WebSocket.Url, "ws://") && !strings.HasPrefix(c.WebSocket.Url, "wss://") {
+		return fmt.Errorf("config: websocket.url must start with ws:// or wss://")
+	}
+
+	if c.Wallet.Seed != "" && c.Wallet.Mnemonic != "" {
+		return fmt.Errorf("config: wallet.seed and wallet.mnemonic are exclusive")
+	}
+
+	if c.Wallet.Mnemonic != "" && !MnemonicIsValid(c.Wallet.Mnemonic) {
+		return fmt.Errorf("config: wallet.mnemonic is invalid")
+	}
+
+	return nil
+}
+
+// NewClient creates the client of the configuration, starting from the node preset if set,
+// returns the client or an error.
+func (c *Config) NewClient() (*Client, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	client := &Client{}
+
+	if c.RPC.Preset != "" {
+		preset, _ := GetNodePreset(c.RPC.Preset)
+		client = preset.NewClient(c.RPC.AuthToken)
+	}
+
+	if c.RPC.Url != "" {
+		client.Url = c.RPC.Url
+	}
+
+	if c.RPC.AuthHeader != "" {
+		client.AuthHeader = c.RPC.AuthHeader
+		client.AuthToken = c.RPC.AuthToken
+	}
+
+	client.Urls = c.RPC.Urls
+	client.Timeout = time.Duration(c.RPC.Timeout)
+	client.Retries = c.RPC.Retries
+	client.Backoff = time.Duration(c.RPC.Backoff)
+
+	if c.Work.Provider == "rpc" {
+		client.Work = nil
+	}
+
+	if c.Work.Provider == "local" {
+		client.Work = LocalWorkProvider{Threads: c.Work.Threads}
+	}
+
+	return client, nil
+}
+
+// NewWallet creates the wallet of the configuration,
+// client: the client used by the accounts of the wallet,
+// returns the wallet or an error if no wallet is configured.
+func (c *Config) NewWallet(client *Client) (*Wallet, error) {
+	switch {
+	case c.Wallet.Seed != "":
+		return NewWalletFromSeed(c.Wallet.Seed, client)
+	case c.Wallet.Mnemonic != "" && c.Wallet.LegacyMnemonic:
+		return NewWalletFromLegacyMnemonic(c.Wallet.Mnemonic, client)
+	case c.Wallet.Mnemonic != "":
+		return NewWalletFromMnemonic(c.Wallet.Mnemonic, c.Wallet.Passphrase, client)
+	default:
+		return nil, fmt.Errorf("config: no wallet configured")
+	}
+}
+
+// NewWSClient creates the WebSocket client of the configuration, the one of the preset if no url is set,
+// returns the WebSocket client or an error if no WebSocket server is configured.
+func (c *Config) NewWSClient() (*WSClient, error) {
+	url := c.WebSocket.Url
+
+	if preset, ok := GetNodePreset(c.RPC.Preset); ok && url == "" {
+		url = preset.WSUrl
+	}
+
+	if url == "" {
+		return nil, fmt.Errorf("config: no websocket configured")
+	}
+
+	return &WSClient{
+		Url:            url,
+		PingInterval:   time.Duration(c.WebSocket.PingInterval),
+		ReconnectDelay: time.Duration(c.WebSocket.ReconnectDelay),
+	}, nil
+}
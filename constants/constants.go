@@ -0,0 +1,109 @@
+// Package constants contains the genesis, epoch and burn constants of the Nano networks.
+package constants
+
+import "strings"
+
+// Network is the constants of a Nano network,
+// Name: the name of the network,
+// GenesisAccount: the wallet address of the genesis account,
+// GenesisPublicKey: the public key of the genesis account in hex,
+// GenesisBlock: the hash of the genesis open block,
+// EpochV1Signer: the public key signing the epoch v1 blocks in hex,
+// EpochV2Signer: the public key signing the epoch v2 blocks in hex.
+type Network struct {
+	Name             string
+	GenesisAccount   string
+	GenesisPublicKey string
+	GenesisBlock     string
+	EpochV1Signer    string
+	EpochV2Signer    string
+}
+
+const (
+	// BurnAddress is the account of the zero public key, its funds can never be spent.
+	BurnAddress = "nano_1111111111111111111111111111111111111111111111111111hifc8npp"
+
+	// GenesisAmount is the supply created by the genesis block in raw (2^128 - 1).
+	GenesisAmount = "340282366920938463463374607431768211455"
+
+	// EpochV1Link is the link of the epoch v1 blocks ("epoch v1 block" padded with zeros).
+	EpochV1Link = "65706F636820763120626C6F636B000000000000000000000000000000000000"
+
+	// LiveGenesisAccount is the wallet address of the genesis account of the live network.
+	LiveGenesisAccount = "nano_3t6k35gi95xu6tergt6p69ck76ogmitsa8mnijtpxm9fkcm736xtoncuohr3"
+
+	// EpochV2Link is the link of the epoch v2 blocks ("epoch v2 block" padded with zeros).
+	EpochV2Link = "65706F636820763220626C6F636B000000000000000000000000000000000000"
+)
+
+var (
+	// Live is the main Nano network.
+	Live = Network{
+		Name:             "live",
+		GenesisAccount:   LiveGenesisAccount,
+		GenesisPublicKey: "E89208DD038FBB269987689621D52292AE9C35941A7484756ECCED92A65093BA",
+		GenesisBlock:     "991CF190094C00F0B68E2E5F75F6BEE95A2E0BD93CEAA4A6734DB9F19B728948",
+		EpochV1Signer:    "E89208DD038FBB269987689621D52292AE9C35941A7484756ECCED92A65093BA",
+		EpochV2Signer:    "DD24A9200D4BF8247981E4AC63DBDE38FD2319386970A26D02ECC98C79975DB1",
+	}
+
+	// Beta is the beta Nano network.
+	Beta = Network{
+		Name:             "beta",
+		GenesisAccount:   "nano_1betag7az9wk6rbis38s1d35hdsycz1bi95xg4g4j148p6afjk7embcurda4",
+		GenesisPublicKey: "259A438A8F9F9226130C84D902C237AF3E57C0981C7D709C288046B110D8C8AC",
+		GenesisBlock:     "E1227CF974C1455A8B630433D94F3DDBF495EEAC9ADD2481A4A1D90A0D00F488",
+		EpochV1Signer:    "259A438A8F9F9226130C84D902C237AF3E57C0981C7D709C288046B110D8C8AC",
+		EpochV2Signer:    "259A438A8F9F9226130C84D902C237AF3E57C0981C7D709C288046B110D8C8AC",
+	}
+
+	// Test is the public test Nano network.
+	Test = Network{
+		Name:             "test",
+		GenesisAccount:   "nano_1jg8zygjg3pp5w644emqcbmjqpnzmubfni3kfe1s8pooeuxsw49fdq1mco9j",
+		GenesisPublicKey: "45C6FF9D1706D61F0821327752671BDA9F9ED2DA40326B01935AB566FB9E08ED",
+		GenesisBlock:     "B1D60C0B886B57401EF5A1DAA04340E53726AA6F4D706C085706F31BBD100CEE",
+		EpochV1Signer:    "45C6FF9D1706D61F0821327752671BDA9F9ED2DA40326B01935AB566FB9E08ED",
+		EpochV2Signer:    "45C6FF9D1706D61F0821327752671BDA9F9ED2DA40326B01935AB566FB9E08ED",
+	}
+
+	// Dev is the local dev Nano network of the node (--network=dev). The private key of
+	// its genesis account and epoch signer is public, so it is not in Networks.
+	Dev = Network{
+		Name:             "dev",
+		GenesisAccount:   "nano_3e3j5tkog48pnny9dmfzj1r16pg8t1e76dz5tmac6iq689wyjfpiij4txtdo",
+		GenesisPublicKey: "B0311EA55708D6A53C75CDBF88300259C6D018522FE3D4D0A242E431F9E8B6D0",
+		GenesisBlock:     "04270D7F11C4B2B472F2854C5A59F2A7E84226CE9ED799DE75744BD7D85FC9D9",
+		EpochV1Signer:    "B0311EA55708D6A53C75CDBF88300259C6D018522FE3D4D0A242E431F9E8B6D0",
+		EpochV2Signer:    "B0311EA55708D6A53C75CDBF88300259C6D018522FE3D4D0A242E431F9E8B6D0",
+	}
+
+	// Networks is every known network.
+	Networks = []Network{Live, Beta, Test}
+)
+
+// EpochSigner returns the public key of the epoch signer of the network for an epoch link,
+// link: the link of the epoch block in hex,
+// returns the public key in hex and true, or false if the link is not an epoch link.
+func (n Network) EpochSigner(link string) (string, bool) {
+	switch strings.ToUpper(link) {
+	case EpochV1Link:
+		return n.EpochV1Signer, true
+	case EpochV2Link:
+		return n.EpochV2Signer, true
+	default:
+		return "", false
+	}
+}
+
+// EpochSigners returns the public keys of the epoch signers of every known network by epoch link.
+func EpochSigners() map[string][]string {
+	signers := map[string][]string{}
+
+	for _, n := range Networks {
+		signers[EpochV1Link] = append(signers[EpochV1Link], n.EpochV1Signer)
+		signers[EpochV2Link] = append(signers[EpochV2Link], n.EpochV2Signer)
+	}
+
+	return signers
+}
@@ -5,16 +5,22 @@ import (
 	"encoding/hex"
 	"filippo.io/edwards25519"
 	"fmt"
-	"github.com/shopspring/decimal"
 	"golang.org/x/crypto/blake2b"
+	"math"
 	"strings"
 )
 
 // SeedToPrivateKey converts a seed to a private key,
 // seed: the seed to convert,
-// index: the index of the private key to generate,
+// index: the index of the private key to generate, from 0 to 2^32-1,
 // returns the private key or an error.
 func SeedToPrivateKey(seed string, index int) ([32]byte, error) {
+	i, err := nanoIndex(index)
+
+	if err != nil {
+		return [32]byte{}, err
+	}
+
 	seedBytes, err := hex.DecodeString(seed)
 
 	if err != nil {
@@ -25,12 +31,29 @@ func SeedToPrivateKey(seed string, index int) ([32]byte, error) {
 		return [32]byte{}, fmt.Errorf("seed length is not 32 bytes")
 	}
 
-	iBytes := make([]byte, 4)
-	binary.BigEndian.PutUint32(iBytes, uint32(index))
-	comb := append(seedBytes, iBytes...)
+	return deriveNanoKey(seedBytes, i), nil
+}
+
+// nanoIndex checks an index of the Nano derivation, which would wrap onto another
+// index out of the uint32 range,
+// returns the index or an error.
+func nanoIndex(index int) (uint32, error) {
+	if index < 0 || uint64(index) > math.MaxUint32 {
+		return 0, fmt.Errorf("index %d is out of the range 0 to %d", index, uint32(math.MaxUint32))
+	}
+
+	return uint32(index), nil
+}
+
+// deriveNanoKey derives the private key at an index of a raw seed as blake2b(seed || index).
+func deriveNanoKey(seed []byte, index uint32) [32]byte {
+	comb := make([]byte, len(seed)+4)
+	copy(comb, seed)
+	binary.BigEndian.PutUint32(comb[len(seed):], index)
 	privKeyBytes := blake2b.Sum256(comb)
+	wipe(comb)
 
-	return privKeyBytes, nil
+	return privKeyBytes
 }
 
 // PrivateKeyToPublicKey converts a private key to a public key,
@@ -49,6 +72,16 @@ func PrivateKeyToPublicKey(privateKey [32]byte) ([32]byte, error) {
 	return [32]byte(pubKeyBytes), nil
 }
 
+func privateKeyToAddress(privateKey [32]byte) (string, error) {
+	pubKey, err := PrivateKeyToPublicKey(privateKey)
+
+	if err != nil {
+		return "", err
+	}
+
+	return PublicKeyToAddress(pubKey)
+}
+
 // PublicKeyToAddress converts a public key to a wallet address,
 // publicKey: the public key to convert,
 // returns the address or an error.
@@ -76,56 +109,57 @@ func PublicKeyToAddress(publicKey [32]byte) (string, error) {
 // address: the address to get the public key from,
 // returns the public key or an error.
 func AddressToPublicKey(address string) ([32]byte, error) {
+	var encoded string
+
 	if len(address) == 64 {
-		bytes, err := base32Decode(address[4:56])
-		return [32]byte(bytes), err
+		encoded = address[4:56]
 	} else if len(address) == 65 {
-		bytes, err := base32Decode(address[5:57])
-		return [32]byte(bytes), err
+		encoded = address[5:57]
+	} else {
+		return [32]byte{}, fmt.Errorf("could not parse address (%s)", address)
 	}
 
-	return [32]byte{}, fmt.Errorf("could not parse address (%s)", address)
-}
-
-// NanoToRaw converts a nano amount to raw,
-// nano: the nano amount to convert,
-// returns the raw or an error.
-func NanoToRaw(nano string) (string, error) {
-	nanoDec, err := decimal.NewFromString(nano)
+	bytes, err := base32Decode(encoded)
 
 	if err != nil {
-		return "", fmt.Errorf("could not parse nano: %v", err)
+		return [32]byte{}, err
 	}
 
-	rawPerNano, err := decimal.NewFromString("1000000000000000000000000000000")
-
-	if err != nil {
-		return "", fmt.Errorf("could not parse raw per nano: %v", err)
+	if len(bytes) > 32 {
+		return [32]byte{}, fmt.Errorf("could not parse address (%s)", address)
 	}
 
-	raw := nanoDec.Mul(rawPerNano)
+	// leading zero bytes are dropped by the decoding
+	var pubKey [32]byte
+	copy(pubKey[32-len(bytes):], bytes)
 
-	return raw.String(), nil
+	return pubKey, nil
 }
 
-// RawToNano converts a raw to nano amount,
-// raw: the raw to convert,
-// returns the nano amount or an error.
-func RawToNano(raw string) (string, error) {
-	decimal.DivisionPrecision = 30
-	rawDec, err := decimal.NewFromString(raw)
+// NanoToRaw converts a nano amount to raw,
+// nano: the nano amount to convert, a plain decimal number,
+// returns the raw or an error wrapping ErrInvalidAmount (also if the amount is finer than a raw),
+// ErrScientificNotation, ErrNegativeAmount or ErrAmountOverflow.
+func NanoToRaw(nano string) (string, error) {
+	raw, err := ParseNano(nano)
 
 	if err != nil {
-		return "", fmt.Errorf("could not parse raw: %v", err)
+		return "", fmt.Errorf("could not parse nano: %w", err)
 	}
 
-	rawPerNano, err := decimal.NewFromString("1000000000000000000000000000000")
+	return raw.String(), nil
+}
+
+// RawToNano converts a raw to nano amount,
+// raw: the raw to convert, a plain integer of at most 128 bits,
+// returns the nano amount or an error wrapping ErrInvalidAmount, ErrScientificNotation,
+// ErrNegativeAmount or ErrAmountOverflow.
+func RawToNano(raw string) (string, error) {
+	amount, err := ParseAmount(raw)
 
 	if err != nil {
-		return "", fmt.Errorf("could not parse raw per nano: %v", err)
+		return "", fmt.Errorf("could not parse raw: %w", err)
 	}
 
-	nano := rawDec.Div(rawPerNano)
-
-	return nano.String(), nil
+	return amount.Nano(), nil
 }
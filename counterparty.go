@@ -0,0 +1,121 @@
+package nanogo
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Direction is the direction of a payment from the point of view of the client.
+type Direction int
+
+const (
+	// Outgoing is a payment sent by the client.
+	Outgoing Direction = iota
+	// Incoming is a payment received by the client.
+	Incoming
+)
+
+// CounterpartyVerdict is the decision of a CounterpartyHook,
+// Deny: true if the client must not transact with the counterparty,
+// Reason: the reason of the decision (optional),
+// Tags: the tags of the counterparty (e.g. "exchange", "sanctioned").
+type CounterpartyVerdict struct {
+	Deny   bool
+	Reason string
+	Tags   []string
+}
+
+// CounterpartyHook is consulted by Send, FastSend and Receive before a block
+// with a counterparty is built and by DepositMonitor before a deposit is emitted,
+// so applications can tag or deny wallets.
+type CounterpartyHook interface {
+	Counterparty(address string, direction Direction) (CounterpartyVerdict, error)
+}
+
+// StaticCounterparties is a CounterpartyHook backed by static lists, which must not
+// be changed after the first lookup,
+// Denied: the denied wallet addresses with the reason,
+// Tags: the tags of wallet addresses.
+// Addresses match regardless of their nano_ or xrb_ prefix.
+type StaticCounterparties struct {
+	Denied map[string]string
+	Tags   map[string][]string
+
+	once   sync.Once
+	denied map[string]string
+	tags   map[string][]string
+}
+
+// Counterparty returns the verdict for a counterparty,
+// address: the wallet address of the counterparty,
+// direction: the direction of the payment,
+// returns the verdict or an error.
+func (s *StaticCounterparties) Counterparty(address string, direction Direction) (CounterpartyVerdict, error) {
+	s.once.Do(s.index)
+
+	key := counterpartyKey(address)
+	reason, deny := s.denied[key]
+
+	return CounterpartyVerdict{Deny: deny, Reason: reason, Tags: append([]string(nil), s.tags[key]...)}, nil
+}
+
+// index keys the lists by public key, the entries of an address under both prefixes
+// being merged in the order of the addresses.
+func (s *StaticCounterparties) index() {
+	s.denied = make(map[string]string, len(s.Denied))
+	s.tags = make(map[string][]string, len(s.Tags))
+
+	for _, addr := range sortedKeys(s.Denied) {
+		key := counterpartyKey(addr)
+
+		if _, ok := s.denied[key]; !ok {
+			s.denied[key] = s.Denied[addr]
+		}
+	}
+
+	for _, addr := range sortedKeys(s.Tags) {
+		key := counterpartyKey(addr)
+		s.tags[key] = append(s.tags[key], s.Tags[addr]...)
+	}
+}
+
+func counterpartyKey(address string) string {
+	pubKey, err := AddressToPublicKey(address)
+
+	if err != nil {
+		return strings.ToLower(address)
+	}
+
+	return fmt.Sprintf("%064X", pubKey)
+}
+
+func (c *Client) checkCounterparty(address string, direction Direction) error {
+	_, err := checkCounterparty(c.Counterparties, address, direction)
+
+	return err
+}
+
+// checkCounterparty consults a hook about a counterparty, a nil hook allowing every one,
+// returns the verdict or an error wrapping ErrCounterpartyDenied if it is denied.
+func checkCounterparty(hook CounterpartyHook, address string, direction Direction) (CounterpartyVerdict, error) {
+	if hook == nil {
+		return CounterpartyVerdict{}, nil
+	}
+
+	verdict, err := hook.Counterparty(address, direction)
+
+	if err != nil {
+		return CounterpartyVerdict{}, err
+	}
+
+	if verdict.Deny {
+		if verdict.Reason == "" {
+			return verdict, fmt.Errorf("%w: %s", ErrCounterpartyDenied, address)
+		}
+
+		return verdict, fmt.Errorf("%w: %s (%s)", ErrCounterpartyDenied, address, verdict.Reason)
+	}
+
+	return verdict, nil
+}
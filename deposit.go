@@ -0,0 +1,359 @@
+package nanogo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PriceProvider provides the price of one Nano in a fiat currency at a time.
+type PriceProvider interface {
+	Price(currency string, at time.Time) (string, error)
+}
+
+// StaticPrices is a PriceProvider with fixed prices by currency (e.g. "USD": "1.25").
+type StaticPrices map[string]string
+
+// Price returns the fixed price of a currency,
+// currency: the fiat currency,
+// at: the time of the price (ignored),
+// returns the price of one Nano or an error.
+func (s StaticPrices) Price(currency string, at time.Time) (string, error) {
+	price, ok := s[currency]
+
+	if !ok {
+		return "", fmt.Errorf("no price for %s", currency)
+	}
+
+	return price, nil
+}
+
+// FiatValue is the fiat value of an amount at a time,
+// Currency: the fiat currency,
+// Price: the price of one Nano,
+// Value: the value of the amount,
+// At: the time of the price.
+type FiatValue struct {
+	Currency string    `json:"currency" protobuf:"1"`
+	Price    string    `json:"price" protobuf:"2"`
+	Value    string    `json:"value" protobuf:"3"`
+	At       time.Time `json:"at" protobuf:"4"`
+}
+
+// Deposit is a confirmed incoming payment to a watched wallet,
+// Hash: the hash of the send block,
+// Account: the watched wallet address,
+// Source: the sending wallet address,
+// Amount: the amount in raw,
+// ConfirmedAt: the time the node saw the send block (local_timestamp), or the time of
+// the poll if the client does not read blocks or the node has no timestamp,
+// Fiat: the fiat value at confirmation (nil without a PriceProvider or if the price failed),
+// Tags: the tags of the source by the CounterpartyHook of the monitor.
+type Deposit struct {
+	Hash        string     `json:"hash" protobuf:"1"`
+	Account     string     `json:"account" protobuf:"2"`
+	Source      string     `json:"source" protobuf:"3"`
+	Amount      string     `json:"amount" protobuf:"4"`
+	ConfirmedAt time.Time  `json:"confirmed_at" protobuf:"5"`
+	Fiat        *FiatValue `json:"fiat,omitempty" protobuf:"6"`
+	Tags        []string   `json:"tags,omitempty" protobuf:"7"`
+}
+
+// DepositMonitor watches wallets for confirmed incoming payments,
+// Client: the client of the node,
+// Accounts: the watched wallet addresses,
+// Interval: the interval between polls (default 5 seconds),
+// Prices: the provider stamping deposits with their fiat value (optional),
+// Currency: the fiat currency of the stamps,
+// OnError: called with errors of the polls and the denied deposits (optional),
+// Events: the sink persisting the new deposits (optional),
+// Counterparties: consulted about the source of every deposit like by Receive, a denied
+// deposit is reported to OnError instead of emitted (optional, the Counterparties of the
+// client by default if it is a Client).
+type DepositMonitor struct {
+	Client         AccountReader
+	Accounts       []string
+	Interval       time.Duration
+	Prices         PriceProvider // optional
+	Currency       string
+	OnError        func(err error)
+	Events         EventSink        // optional
+	Counterparties CounterpartyHook // optional
+
+	mu   sync.Mutex
+	seen map[string]string // hash -> account
+}
+
+// Watch polls the watched wallets until the context is done and emits every
+// new confirmed deposit once,
+// ctx: the context stopping the monitor,
+// returns the channel of deposits, closed when the context is done.
+func (m *DepositMonitor) Watch(ctx context.Context) <-chan Deposit {
+	deposits := make(chan Deposit)
+	interval := m.Interval
+
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	go func() {
+		defer close(deposits)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			found, err := m.Poll()
+
+			if err != nil && m.OnError != nil {
+				m.OnError(err)
+			}
+
+			for _, d := range found {
+				select {
+				case deposits <- d:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return deposits
+}
+
+// Poll checks the watched wallets once, a deposit counting as seen only once it is
+// persisted to the Events sink, so a failed append is retried by the next poll, and
+// until it is no longer receivable,
+// returns the new confirmed deposits or an error (with the deposits found before it).
+func (m *DepositMonitor) Poll() ([]Deposit, error) {
+	var deposits []Deposit
+
+	for _, addr := range m.Accounts {
+		receivable, err := m.Client.GetReceivable(addr)
+
+		if err != nil {
+			return deposits, err
+		}
+
+		m.pruneSeen(addr, receivable)
+		times, err := m.sentTimes(receivable)
+
+		if err != nil {
+			return deposits, err
+		}
+
+		for hash, b := range receivable.Blocks {
+			if !m.markSeen(addr, hash) {
+				continue
+			}
+
+			verdict, err := checkCounterparty(m.counterparties(), b.Source, Incoming)
+
+			if errors.Is(err, ErrCounterpartyDenied) {
+				if m.OnError != nil {
+					m.OnError(fmt.Errorf("deposit %s: %w", hash, err))
+				}
+
+				continue
+			}
+
+			if err != nil {
+				m.forgetSeen(hash)
+				return deposits, err
+			}
+
+			d := m.newDeposit(hash, addr, b.Source, b.Amount, times[strings.ToUpper(hash)])
+			d.Tags = verdict.Tags
+
+			if m.Events != nil {
+				if err := m.Events.Append(d); err != nil {
+					m.forgetSeen(hash)
+					return deposits, err
+				}
+			}
+
+			deposits = append(deposits, d)
+		}
+	}
+
+	return deposits, nil
+}
+
+// counterparties returns the hook of the monitor or of its client.
+func (m *DepositMonitor) counterparties() CounterpartyHook {
+	if m.Counterparties != nil {
+		return m.Counterparties
+	}
+
+	if c, ok := m.Client.(*Client); ok {
+		return c.Counterparties
+	}
+
+	return nil
+}
+
+// sentTimes returns the times the node saw the unseen receivable blocks by uppercase
+// hash, nil if the client does not read blocks.
+func (m *DepositMonitor) sentTimes(receivable Receivable) (map[string]time.Time, error) {
+	reader, ok := m.Client.(BlockReader)
+
+	if !ok {
+		return nil, nil
+	}
+
+	var hashes []string
+	m.mu.Lock()
+
+	for hash := range receivable.Blocks {
+		if _, ok := m.seen[strings.ToUpper(hash)]; !ok {
+			hashes = append(hashes, hash)
+		}
+	}
+
+	m.mu.Unlock()
+
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+
+	infos, err := reader.GetBlocksInfo(hashes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	times := map[string]time.Time{}
+
+	for hash, info := range infos {
+		if ts, err := strconv.ParseInt(info.LocalTimestamp, 10, 64); err == nil && ts > 0 {
+			times[strings.ToUpper(hash)] = time.Unix(ts, 0)
+		}
+	}
+
+	return times, nil
+}
+
+func (m *DepositMonitor) markSeen(account, hash string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.seen == nil {
+		m.seen = map[string]string{}
+	}
+
+	hash = strings.ToUpper(hash)
+
+	if _, ok := m.seen[hash]; ok {
+		return false
+	}
+
+	m.seen[hash] = account
+
+	return true
+}
+
+// pruneSeen forgets the seen deposits of an account that are no longer receivable,
+// so the seen hashes do not grow with every deposit ever made.
+func (m *DepositMonitor) pruneSeen(account string, receivable Receivable) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pending := make(map[string]bool, len(receivable.Blocks))
+
+	for hash := range receivable.Blocks {
+		pending[strings.ToUpper(hash)] = true
+	}
+
+	for hash, a := range m.seen {
+		if a == account && !pending[hash] {
+			delete(m.seen, hash)
+		}
+	}
+}
+
+// forgetSeen releases a deposit claimed by markSeen whose event could not be persisted.
+func (m *DepositMonitor) forgetSeen(hash string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.seen, strings.ToUpper(hash))
+}
+
+func (m *DepositMonitor) newDeposit(hash, account, source, amount string, sentAt time.Time) Deposit {
+	d := Deposit{
+		Hash:        hash,
+		Account:     account,
+		Source:      source,
+		Amount:      amount,
+		ConfirmedAt: sentAt,
+	}
+
+	if d.ConfirmedAt.IsZero() {
+		d.ConfirmedAt = time.Now()
+	}
+
+	if m.Prices != nil {
+		fiat, err := FiatValueOf(m.Prices, m.Currency, amount, d.ConfirmedAt)
+
+		if err != nil && m.OnError != nil {
+			m.OnError(err)
+		}
+
+		if err == nil {
+			d.Fiat = &fiat
+		}
+	}
+
+	return d
+}
+
+// FiatValueOf computes the fiat value of a raw amount at a time,
+// prices: the price provider,
+// currency: the fiat currency,
+// raw: the amount in raw,
+// at: the time of the price,
+// returns the fiat value or an error.
+func FiatValueOf(prices PriceProvider, currency, raw string, at time.Time) (FiatValue, error) {
+	price, err := prices.Price(currency, at)
+
+	if err != nil {
+		return FiatValue{}, err
+	}
+
+	priceDec, err := decimal.NewFromString(price)
+
+	if err != nil {
+		return FiatValue{}, fmt.Errorf("could not parse price: %v", err)
+	}
+
+	nano, err := RawToNano(raw)
+
+	if err != nil {
+		return FiatValue{}, err
+	}
+
+	nanoDec, err := decimal.NewFromString(nano)
+
+	if err != nil {
+		return FiatValue{}, fmt.Errorf("could not parse nano: %v", err)
+	}
+
+	return FiatValue{
+		Currency: currency,
+		Price:    price,
+		Value:    nanoDec.Mul(priceDec).String(),
+		At:       at,
+	}, nil
+}
@@ -0,0 +1,140 @@
+package nanogo
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Destination is a wallet sent to by an account,
+// Address: the destination wallet address,
+// Count: the number of sends to the destination,
+// Total: the total amount sent to the destination,
+// FirstSent: the local timestamp of the first send (zero if unknown),
+// LastSent: the local timestamp of the last send (zero if unknown).
+type Destination struct {
+	Address   string
+	Count     int
+	Total     Raw
+	FirstSent time.Time
+	LastSent  time.Time
+}
+
+// Destinations is the destinations of an account by wallet address.
+type Destinations map[string]*Destination
+
+// ScanDestinations walks the outgoing history of a wallet and builds the frequency
+// of its destinations,
+// address: the wallet address to scan,
+// returns the destinations or an error.
+func (c *Client) ScanDestinations(address string) (Destinations, error) {
+	destinations := Destinations{}
+	pages := c.historyPages(address)
+
+	for pages.next() {
+		for _, h := range pages.page.History {
+			if h.Type != "send" || h.Account == "" {
+				continue
+			}
+
+			amount, err := ParseAmount(h.Amount)
+
+			if err != nil {
+				return nil, err
+			}
+
+			d, ok := destinations[h.Account]
+
+			if !ok {
+				d = &Destination{Address: h.Account}
+				destinations[h.Account] = d
+			}
+
+			d.Count++
+			d.Total = d.Total.Add(amount)
+
+			ts, err := strconv.ParseInt(h.LocalTimestamp, 10, 64)
+
+			if err != nil || ts == 0 {
+				continue
+			}
+
+			t := time.Unix(ts, 0)
+
+			if d.FirstSent.IsZero() || t.Before(d.FirstSent) {
+				d.FirstSent = t
+			}
+
+			if t.After(d.LastSent) {
+				d.LastSent = t
+			}
+		}
+	}
+
+	if pages.err != nil {
+		return nil, pages.err
+	}
+
+	return destinations, nil
+}
+
+// Recent returns the most recently sent to destinations, newest first,
+// n: the maximum count of destinations (0 for all),
+// returns the destinations.
+func (d Destinations) Recent(n int) []Destination {
+	list := d.list()
+
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].LastSent.Equal(list[j].LastSent) {
+			return list[i].LastSent.After(list[j].LastSent)
+		}
+
+		return list[i].Address < list[j].Address
+	})
+
+	return truncate(list, n)
+}
+
+// Frequent returns the most frequently sent to destinations,
+// n: the maximum count of destinations (0 for all),
+// returns the destinations.
+func (d Destinations) Frequent(n int) []Destination {
+	list := d.list()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+
+		return list[i].Address < list[j].Address
+	})
+
+	return truncate(list, n)
+}
+
+// IsNew checks if a wallet was never sent to, for new destination alerts,
+// address: the destination wallet address,
+// returns true if the wallet is a new destination, false otherwise.
+func (d Destinations) IsNew(address string) bool {
+	_, ok := d[address]
+
+	return !ok
+}
+
+func (d Destinations) list() []Destination {
+	list := make([]Destination, 0, len(d))
+
+	for _, dest := range d {
+		list = append(list, *dest)
+	}
+
+	return list
+}
+
+func truncate(list []Destination, n int) []Destination {
+	if n > 0 && len(list) > n {
+		return list[:n]
+	}
+
+	return list
+}
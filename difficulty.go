@@ -0,0 +1,175 @@
+package nanogo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ActiveDifficulty is the active difficulty of the network,
+// NetworkMinimum: the minimum difficulty of send and change blocks,
+// NetworkReceiveMinimum: the minimum difficulty of receive blocks,
+// NetworkCurrent: the current difficulty of send and change blocks,
+// NetworkReceiveCurrent: the current difficulty of receive blocks,
+// Multiplier: the current multiplier of the difficulty,
+// Error: the error of the request.
+type ActiveDifficulty struct {
+	NetworkMinimum        string `json:"network_minimum"`
+	NetworkReceiveMinimum string `json:"network_receive_minimum"`
+	NetworkCurrent        string `json:"network_current"`
+	NetworkReceiveCurrent string `json:"network_receive_current"`
+	Multiplier            string `json:"multiplier"`
+
+	Error any `json:"error"`
+}
+
+// GetActiveDifficulty gets the active difficulty of the network,
+// returns the active difficulty or an error.
+func (c *Client) GetActiveDifficulty() (ActiveDifficulty, error) {
+	data := map[string]any{
+		"action": "active_difficulty",
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return ActiveDifficulty{}, err
+	}
+
+	var diff ActiveDifficulty
+	json.Unmarshal(res, &diff)
+
+	if diff.Error != nil {
+		return ActiveDifficulty{}, rpcError(diff.Error)
+	}
+
+	return diff, nil
+}
+
+// DifficultyFloor keeps a rolling floor of the network difficulty sampled
+// with active_difficulty, so work is never generated at a threshold that is
+// about to be insufficient,
+// Client: the client used to sample the difficulty,
+// Interval: the interval between samples (default 1 minute),
+// Window: the number of samples the floor is computed over (default 10).
+type DifficultyFloor struct {
+	Client   *Client
+	Interval time.Duration
+	Window   int
+
+	mu      sync.Mutex
+	samples []difficultySample
+}
+
+type difficultySample struct {
+	send    uint64
+	receive uint64
+}
+
+// Sample samples the current network difficulty once,
+// returns an error if the difficulty could not be sampled.
+func (f *DifficultyFloor) Sample() error {
+	diff, err := f.Client.GetActiveDifficulty()
+
+	if err != nil {
+		return err
+	}
+
+	send, err := parseDifficulty(diff.NetworkCurrent)
+
+	if err != nil {
+		return err
+	}
+
+	receive, err := parseDifficulty(diff.NetworkReceiveCurrent)
+
+	if err != nil {
+		return err
+	}
+
+	window := f.Window
+
+	if window <= 0 {
+		window = 10
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.samples = append(f.samples, difficultySample{send: send, receive: receive})
+
+	if len(f.samples) > window {
+		f.samples = f.samples[len(f.samples)-window:]
+	}
+
+	return nil
+}
+
+// Run samples the network difficulty every Interval until the context is done,
+// sampling errors are skipped and the previous samples are kept,
+// ctx: the context stopping the sampling.
+func (f *DifficultyFloor) Run(ctx context.Context) {
+	interval := f.Interval
+
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		f.Sample()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Send returns the difficulty floor of send and change blocks (0 if not sampled yet).
+func (f *DifficultyFloor) Send() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var floor uint64
+
+	for _, s := range f.samples {
+		if s.send > floor {
+			floor = s.send
+		}
+	}
+
+	return floor
+}
+
+// Receive returns the difficulty floor of receive blocks (0 if not sampled yet).
+func (f *DifficultyFloor) Receive() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var floor uint64
+
+	for _, s := range f.samples {
+		if s.receive > floor {
+			floor = s.receive
+		}
+	}
+
+	return floor
+}
+
+func parseDifficulty(difficulty string) (uint64, error) {
+	d, err := strconv.ParseUint(difficulty, 16, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("could not parse difficulty (%s)", difficulty)
+	}
+
+	return d, nil
+}
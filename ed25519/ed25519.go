@@ -1,11 +1,24 @@
 package ed25519
 
 import (
+	"crypto/rand"
 	"filippo.io/edwards25519"
 	"golang.org/x/crypto/blake2b"
 )
 
 func Sign(pubKey, privKey [32]byte, msg []byte) ([]byte, error) {
+	return sign(pubKey, privKey, msg, nil)
+}
+
+// SignRandomized signs like Sign with a hedged nonce, mixing 32 bytes of
+// entropy into the deterministic nonce hash. The signature verifies like any
+// other and a broken entropy source degrades to deterministic signing instead
+// of leaking the private key.
+func SignRandomized(pubKey, privKey [32]byte, msg []byte, entropy [32]byte) ([]byte, error) {
+	return sign(pubKey, privKey, msg, entropy[:])
+}
+
+func sign(pubKey, privKey [32]byte, msg, entropy []byte) ([]byte, error) {
 	sig := make([]byte, 64)
 
 	h, err := blake2b.New512(nil)
@@ -27,6 +40,7 @@ func Sign(pubKey, privKey [32]byte, msg []byte) ([]byte, error) {
 
 	h.Reset()
 	h.Write(dig[32:])
+	h.Write(entropy)
 	h.Write(msg)
 	h.Sum(msgDig[:0])
 
@@ -58,3 +72,133 @@ func Sign(pubKey, privKey [32]byte, msg []byte) ([]byte, error) {
 
 	return sig, nil
 }
+
+// Verify verifies a signature with the cofactored equation [8](sB - kA - R) = 0
+// of ZIP 215: it accepts non-canonical encodings of R and A and signatures with a
+// small-order component, which single and batch verification can only agree on
+// when both multiply by the cofactor. The scalar s must be canonical.
+func Verify(pubKey [32]byte, msg, sig []byte) bool {
+	if len(sig) != 64 {
+		return false
+	}
+
+	a, err := new(edwards25519.Point).SetBytes(pubKey[:])
+
+	if err != nil {
+		return false
+	}
+
+	s, err := new(edwards25519.Scalar).SetCanonicalBytes(sig[32:])
+
+	if err != nil {
+		return false
+	}
+
+	k, err := challenge(sig[:32], pubKey, msg)
+
+	if err != nil {
+		return false
+	}
+
+	r, err := new(edwards25519.Point).SetBytes(sig[:32])
+
+	if err != nil {
+		return false
+	}
+
+	minusA := new(edwards25519.Point).Negate(a)
+	check := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(k, minusA, s)
+	check.Subtract(check, r).MultByCofactor(check)
+
+	return check.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+// VerifyBatch verifies signatures with a single multi-scalar multiplication
+// with random coefficients. It decodes keys and signatures like Verify and checks
+// the same cofactored equation, so a batch passes exactly when every signature
+// passes Verify. A false result means at least one signature is invalid.
+func VerifyBatch(pubKeys [][32]byte, msgs, sigs [][]byte) bool {
+	n := len(pubKeys)
+
+	if n == 0 {
+		return true
+	}
+
+	if len(msgs) != n || len(sigs) != n {
+		return false
+	}
+
+	scalars := make([]*edwards25519.Scalar, 0, 2*n+1)
+	points := make([]*edwards25519.Point, 0, 2*n+1)
+	sumS := edwards25519.NewScalar()
+
+	for i := 0; i < n; i++ {
+		if len(sigs[i]) != 64 {
+			return false
+		}
+
+		a, err := new(edwards25519.Point).SetBytes(pubKeys[i][:])
+
+		if err != nil {
+			return false
+		}
+
+		r, err := new(edwards25519.Point).SetBytes(sigs[i][:32])
+
+		if err != nil {
+			return false
+		}
+
+		s, err := new(edwards25519.Scalar).SetCanonicalBytes(sigs[i][32:])
+
+		if err != nil {
+			return false
+		}
+
+		k, err := challenge(sigs[i][:32], pubKeys[i], msgs[i])
+
+		if err != nil {
+			return false
+		}
+
+		var zBytes [64]byte
+
+		if _, err := rand.Read(zBytes[:16]); err != nil {
+			return false
+		}
+
+		z, err := new(edwards25519.Scalar).SetUniformBytes(zBytes[:])
+
+		if err != nil {
+			return false
+		}
+
+		sumS.MultiplyAdd(z, s, sumS)
+		scalars = append(scalars, z, new(edwards25519.Scalar).Multiply(z, k))
+		points = append(points, r, a)
+	}
+
+	scalars = append(scalars, new(edwards25519.Scalar).Negate(sumS))
+	points = append(points, edwards25519.NewGeneratorPoint())
+
+	check := new(edwards25519.Point).VarTimeMultiScalarMult(scalars, points)
+	check.MultByCofactor(check)
+
+	return check.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+func challenge(r []byte, pubKey [32]byte, msg []byte) (*edwards25519.Scalar, error) {
+	h, err := blake2b.New512(nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var hram [64]byte
+	h.Write(r)
+	h.Write(pubKey[:])
+	h.Write(msg)
+	h.Sum(hram[:0])
+
+	return new(edwards25519.Scalar).SetUniformBytes(hram[:])
+}
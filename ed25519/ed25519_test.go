@@ -0,0 +1,112 @@
+package ed25519
+
+import (
+	"encoding/hex"
+	"filippo.io/edwards25519"
+	"golang.org/x/crypto/blake2b"
+	"testing"
+)
+
+// torsionPoint is a point of order 8.
+const torsionPoint = "26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05"
+
+// torsionedSignature signs msg with a nonce point R = rB + T carrying a
+// small-order component T, which only the cofactored equation accepts.
+func torsionedSignature(t *testing.T, privKey [32]byte, msg []byte) ([32]byte, []byte) {
+	t.Helper()
+
+	a, pubKey := keyPair(t, privKey)
+	enc, _ := hex.DecodeString(torsionPoint)
+	torsion, err := new(edwards25519.Point).SetBytes(enc)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if new(edwards25519.Point).MultByCofactor(torsion).Equal(edwards25519.NewIdentityPoint()) != 1 {
+		t.Fatal("torsion point is not of small order")
+	}
+
+	var nonce [64]byte
+	nonce[0] = 7
+	r, err := new(edwards25519.Scalar).SetUniformBytes(nonce[:])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rp := new(edwards25519.Point).ScalarBaseMult(r)
+	rp.Add(rp, torsion)
+	rEnc := rp.Bytes()
+
+	k, err := challenge(rEnc, pubKey, msg)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := new(edwards25519.Scalar).MultiplyAdd(k, a, r)
+
+	return pubKey, append(rEnc, s.Bytes()...)
+}
+
+func TestVerifyBatchTorsionedSignature(t *testing.T) {
+	var privKey [32]byte
+	privKey[0] = 1
+	msg := []byte("torsioned")
+
+	pubKey, sig := torsionedSignature(t, privKey, msg)
+
+	if !Verify(pubKey, msg, sig) {
+		t.Fatal("torsioned signature rejected by Verify")
+	}
+
+	var otherKey [32]byte
+	otherKey[0] = 2
+	_, otherPub := keyPair(t, otherKey)
+	otherSig, err := Sign(otherPub, otherKey, msg)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKeys := [][32]byte{otherPub, pubKey}
+	msgs := [][]byte{msg, msg}
+	sigs := [][]byte{otherSig, sig}
+
+	// a cofactorless batch accepts the torsioned signature with probability 1/8
+	for i := 0; i < 64; i++ {
+		if !VerifyBatch(pubKeys, msgs, sigs) {
+			t.Fatalf("batch rejected a signature accepted by Verify on run %d", i)
+		}
+	}
+
+	sig[40] ^= 1
+
+	if Verify(pubKey, msg, sig) {
+		t.Fatal("tampered signature accepted by Verify")
+	}
+
+	for i := 0; i < 64; i++ {
+		if VerifyBatch(pubKeys, msgs, sigs) {
+			t.Fatalf("batch accepted a signature rejected by Verify on run %d", i)
+		}
+	}
+}
+
+// keyPair returns the private scalar and the public key of a private key.
+func keyPair(t *testing.T, privKey [32]byte) (*edwards25519.Scalar, [32]byte) {
+	t.Helper()
+
+	dig := blake2b.Sum512(privKey[:])
+	a, err := new(edwards25519.Scalar).SetBytesWithClamping(dig[:32])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pubKey [32]byte
+	copy(pubKey[:], new(edwards25519.Point).ScalarBaseMult(a).Bytes())
+
+	return a, pubKey
+}
@@ -0,0 +1,178 @@
+package nanogo
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"github.com/zenitria/nanogo/ed25519"
+	"io"
+	"sync"
+)
+
+// EntropyFallback is the policy of an EntropySource when its reader fails.
+type EntropyFallback int
+
+const (
+	// EntropyFail fails the signature with ErrEntropyUnhealthy.
+	EntropyFail EntropyFallback = iota
+	// EntropySystem falls back to the entropy of crypto/rand.
+	EntropySystem
+	// EntropyDeterministic falls back to a deterministic nonce.
+	EntropyDeterministic
+)
+
+// EntropySource is a health checked source of signature nonce entropy,
+// Reader: the entropy source, e.g. an HSM or audited RNG (optional, crypto/rand by default),
+// Fallback: the policy when the reader errors or fails the health checks,
+// OnFailure: called with every failure of the reader (optional).
+type EntropySource struct {
+	Reader    io.Reader
+	Fallback  EntropyFallback
+	OnFailure func(err error)
+
+	mu       sync.Mutex
+	last     [32]byte
+	read     bool
+	failures int
+}
+
+// Failures returns the number of failures of the reader,
+// returns the number of failures.
+func (s *EntropySource) Failures() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.failures
+}
+
+// Check reads a sample from the reader and runs the health checks on it,
+// returns an error if the reader is unhealthy.
+func (s *EntropySource) Check() error {
+	_, err := s.sample()
+
+	return err
+}
+
+// entropy returns 32 bytes of entropy, applying the fallback policy,
+// returns the entropy, false if the nonce must be deterministic, or an error.
+func (s *EntropySource) entropy() ([32]byte, bool, error) {
+	b, err := s.sample()
+
+	if err == nil {
+		return b, true, nil
+	}
+
+	switch s.Fallback {
+	case EntropySystem:
+		if _, err := io.ReadFull(rand.Reader, b[:]); err != nil {
+			return b, false, fmt.Errorf("%w: %v", ErrEntropyUnhealthy, err)
+		}
+
+		return b, true, nil
+	case EntropyDeterministic:
+		return [32]byte{}, false, nil
+	default:
+		return b, false, err
+	}
+}
+
+func (s *EntropySource) sample() ([32]byte, error) {
+	var b [32]byte
+
+	r := s.Reader
+
+	if r == nil {
+		r = rand.Reader
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := checkEntropy(r, &b, s.last, s.read)
+
+	if err != nil {
+		s.failures++
+
+		if s.OnFailure != nil {
+			s.OnFailure(err)
+		}
+
+		return b, err
+	}
+
+	s.last = b
+	s.read = true
+
+	return b, nil
+}
+
+// checkEntropy reads a sample and rejects stuck output (a single repeated
+// byte) and output repeating the previous sample.
+func checkEntropy(r io.Reader, b *[32]byte, last [32]byte, read bool) error {
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return fmt.Errorf("%w: %v", ErrEntropyUnhealthy, err)
+	}
+
+	if bytes.Count(b[:], b[:1]) == len(b) {
+		return fmt.Errorf("%w: stuck output", ErrEntropyUnhealthy)
+	}
+
+	if read && *b == last {
+		return fmt.Errorf("%w: repeated output", ErrEntropyUnhealthy)
+	}
+
+	return nil
+}
+
+// RandomizedSigner is a Signer signing blocks locally with hedged nonces,
+// mixing entropy into the deterministic nonce so the signature does not
+// depend on the entropy alone,
+// PrivateKey: the private key of the account,
+// Entropy: the entropy source of the nonces (optional, crypto/rand by default).
+type RandomizedSigner struct {
+	PrivateKey [32]byte
+	Entropy    *EntropySource
+}
+
+// SignBlock signs a block with the private key and a hedged nonce,
+// block: the block to sign,
+// returns an error.
+func (s RandomizedSigner) SignBlock(block *Block) error {
+	source := s.Entropy
+
+	if source == nil {
+		source = &EntropySource{}
+	}
+
+	entropy, randomized, err := source.entropy()
+
+	if err != nil {
+		return err
+	}
+
+	if !randomized {
+		return block.Sign(s.PrivateKey)
+	}
+
+	pubKey, err := PrivateKeyToPublicKey(s.PrivateKey)
+
+	if err != nil {
+		return err
+	}
+
+	hash, err := block.hashBytes()
+
+	if err != nil {
+		return err
+	}
+
+	sig, err := ed25519.SignRandomized(pubKey, s.PrivateKey, hash, entropy)
+
+	if err != nil {
+		return err
+	}
+
+	block.Signature = fmt.Sprintf("%0128X", sig)
+
+	return nil
+}
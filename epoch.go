@@ -0,0 +1,91 @@
+package nanogo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/zenitria/nanogo/constants"
+	"strconv"
+	"strings"
+)
+
+// EpochStatus is the epoch of an account and the work its next blocks need,
+// Account: the wallet address,
+// Opened: whether the account is opened,
+// Version: the epoch version of the account (0 to 2, 2 for unopened accounts),
+// NeedsV2Work: whether the blocks of the account need the epoch 2 work thresholds.
+type EpochStatus struct {
+	Account     string
+	Opened      bool
+	Version     int
+	NeedsV2Work bool
+}
+
+// GetEpochStatus gets the epoch of an account from account_info,
+// unopened accounts are opened at epoch 2,
+// address: the wallet address,
+// returns the epoch status or an error.
+func (c *Client) GetEpochStatus(address string) (EpochStatus, error) {
+	info, err := c.GetAccountInfo(address)
+
+	if errors.Is(err, ErrAccountNotFound) {
+		return EpochStatus{Account: address, Version: 2, NeedsV2Work: true}, nil
+	}
+
+	if err != nil {
+		return EpochStatus{}, err
+	}
+
+	version := 0
+
+	if info.AccountVersion != "" {
+		version, err = strconv.Atoi(info.AccountVersion)
+
+		if err != nil {
+			return EpochStatus{}, fmt.Errorf("could not parse account version (%s)", info.AccountVersion)
+		}
+	}
+
+	return EpochStatus{
+		Account:     address,
+		Opened:      true,
+		Version:     version,
+		NeedsV2Work: version >= 2,
+	}, nil
+}
+
+// WorkThreshold returns the work threshold of the next block of the account,
+// an account before epoch 2 keeps the epoch 1 threshold until it is upgraded
+// (by an epoch block or a receive from an epoch 2 source, which need the epoch 2 receive threshold),
+// subtype: the subtype of the block,
+// returns the work threshold.
+func (s EpochStatus) WorkThreshold(subtype string) uint64 {
+	if s.NeedsV2Work || subtype == "epoch" {
+		return WorkThreshold(subtype)
+	}
+
+	return WorkThresholdEpoch1
+}
+
+// VerifyEpochBlock checks if a block is a valid epoch block of the network of the client
+// with Block.VerifyEpochSignature, getting its previous block from the node,
+// block: the block to check,
+// returns true if the block is a valid epoch block, false otherwise, or an error.
+func (c *Client) VerifyEpochBlock(block Block) (bool, error) {
+	network := constants.Live
+
+	if c.Network != nil {
+		network = *c.Network
+	}
+
+	if block.Previous == "0" || block.Previous == strings.Repeat("0", 64) {
+		return block.VerifyEpochSignature(network, nil)
+	}
+
+	info, err := c.GetBlockInfo(block.Previous)
+
+	if err != nil {
+		return false, err
+	}
+
+	return block.VerifyEpochSignature(network, &info.Contents)
+}
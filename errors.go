@@ -1,8 +1,162 @@
 package nanogo
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode is the stable code of a typed error, safe to use as a translation key.
+type ErrorCode string
+
+// Error is a typed error of the package,
+// Code: the stable code of the error,
+// Message: the English message of the error.
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+// Error returns the English message of the error.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Translator translates an error code to a localized message,
+// returns false if there is no translation for the code.
+type Translator func(code ErrorCode) (string, bool)
+
+var catalogue []*Error
+
+func newError(code ErrorCode, message string) *Error {
+	e := &Error{Code: code, Message: message}
+	catalogue = append(catalogue, e)
+
+	return e
+}
 
 var (
-	// ErrAccountNotFound ErrBlockNotFound is returned when the account isn't opened.
-	ErrAccountNotFound = fmt.Errorf("account not found")
+	// ErrAccountNotFound is returned when the account isn't opened.
+	ErrAccountNotFound = newError("account_not_found", "account not found")
+
+	// ErrBlockNotFound is returned when the node does not know a block.
+	ErrBlockNotFound = newError("block_not_found", "block not found")
+
+	// ErrCounterpartyDenied is returned when the CounterpartyHook denies a counterparty.
+	ErrCounterpartyDenied = newError("counterparty_denied", "counterparty denied")
+
+	// ErrActionDisabled is returned when the ActionPolicy of the client does not permit an RPC action.
+	ErrActionDisabled = newError("action_disabled", "rpc action disabled by policy")
+
+	// ErrFrontierMismatch is returned when the frontier of an account is not the one a block was built on.
+	ErrFrontierMismatch = newError("frontier_mismatch", "frontier mismatch")
+
+	// ErrAliasNotResolved is returned when a destination handle could not be resolved to a wallet address.
+	ErrAliasNotResolved = newError("alias_not_resolved", "alias not resolved")
+
+	// ErrPanic is returned when a panic was recovered in the RPC layer.
+	ErrPanic = newError("panic", "recovered panic")
+
+	// ErrServerUnavailable is returned when the RPC server answered with a server error status.
+	ErrServerUnavailable = newError("server_unavailable", "rpc server unavailable")
+
+	// ErrInvalidBlock is returned when a block fails local validation.
+	ErrInvalidBlock = newError("invalid_block", "invalid block")
+
+	// ErrSnapshotUnstable is returned when accounts kept changing during a consistent read.
+	ErrSnapshotUnstable = newError("snapshot_unstable", "accounts kept changing during the read")
+
+	// ErrNotConfirmed is returned when a block was not confirmed in time.
+	ErrNotConfirmed = newError("not_confirmed", "block not confirmed in time")
+
+	// ErrInvalidProof is returned when a proof bundle does not prove what it claims.
+	ErrInvalidProof = newError("invalid_proof", "invalid proof")
+
+	// ErrCheckpointNotOnChain is returned when a sync checkpoint is no longer on the chain of the account.
+	ErrCheckpointNotOnChain = newError("checkpoint_not_on_chain", "checkpoint is not on the chain of the account")
+
+	// ErrSendLimited is returned when a send exceeds the SendLimiter of the client.
+	ErrSendLimited = newError("send_limited", "send limit of the destination exceeded")
+
+	// ErrInsufficientBalance is returned when an amount is bigger than the balance of the account.
+	ErrInsufficientBalance = newError("insufficient_balance", "raw is bigger than wallet balance")
+
+	// ErrBalanceOverflow is returned when a balance would not fit in 128 bits.
+	ErrBalanceOverflow = newError("balance_overflow", "balance overflows 128 bits")
+
+	// ErrInvalidCheckpoint is returned when a ledger checkpoint or its snapshot is invalid.
+	ErrInvalidCheckpoint = newError("invalid_checkpoint", "invalid ledger checkpoint")
+
+	// ErrInvalidPaymentURI is returned when a scanned text has no valid address or nano: URI.
+	ErrInvalidPaymentURI = newError("invalid_payment_uri", "invalid payment uri")
+
+	// ErrNodeBehind is returned when the node has fewer blocks of an account than a local checkpoint.
+	ErrNodeBehind = newError("node_behind", "node is behind the local checkpoint")
+
+	// ErrInvalidAmount is returned when an amount is not a plain decimal number or is finer than a raw.
+	ErrInvalidAmount = newError("invalid_amount", "invalid amount")
+
+	// ErrScientificNotation is returned when an amount is written in scientific notation (e.g. 1e30).
+	ErrScientificNotation = newError("scientific_notation", "amount in scientific notation")
+
+	// ErrNegativeAmount is returned when an amount is negative.
+	ErrNegativeAmount = newError("negative_amount", "negative amount")
+
+	// ErrAmountOverflow is returned when an amount does not fit in 128 bits (more than 39 digits of raw).
+	ErrAmountOverflow = newError("amount_overflow", "amount overflows 128 bits")
+
+	// ErrApprovalPending is returned when a send is held by the ApprovalGate of the client until it is approved.
+	ErrApprovalPending = newError("approval_pending", "send is pending approval")
+
+	// ErrEntropyUnhealthy is returned when the entropy source of a RandomizedSigner fails or its output fails the health checks.
+	ErrEntropyUnhealthy = newError("entropy_unhealthy", "entropy source is unhealthy")
 )
+
+// ErrorCatalogue returns every typed error of the package,
+// returns the typed errors.
+func ErrorCatalogue() []*Error {
+	return append([]*Error{}, catalogue...)
+}
+
+// Code returns the code of the first typed error in the chain of an error,
+// err: the error to get the code of,
+// returns the code or an empty code if the chain has no typed error.
+func Code(err error) ErrorCode {
+	var e *Error
+
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	return ""
+}
+
+// Localize returns a localized message for an error,
+// err: the error to localize,
+// translator: the translator of error codes,
+// returns the translated message of the first typed error in the chain,
+// or the English message of the error if there is no translation.
+func Localize(err error, translator Translator) string {
+	if err == nil {
+		return ""
+	}
+
+	if code := Code(err); code != "" && translator != nil {
+		if msg, ok := translator(code); ok {
+			return msg
+		}
+	}
+
+	return err.Error()
+}
+
+// rpcError maps the error field of an RPC response to a typed error when the node reports a known condition.
+func rpcError(e any) error {
+	switch e {
+	case "Account not found":
+		return ErrAccountNotFound
+	case "Block not found":
+		return ErrBlockNotFound
+	}
+
+	return fmt.Errorf("%v", e)
+}
@@ -0,0 +1,262 @@
+package nanogo
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FrontierState is the state of an account at a frontier,
+// Frontier: the hash of the frontier block,
+// Balance: the balance of the account in raw at the frontier,
+// Representative: the representative of the account at the frontier.
+type FrontierState struct {
+	Frontier       string `json:"frontier"`
+	Balance        string `json:"balance"`
+	Representative string `json:"representative"`
+}
+
+// DelayedSend is a signed send block held back until a release condition is met,
+// Block: the signed send block (work may be empty until release).
+type DelayedSend struct {
+	Block Block `json:"block"`
+}
+
+// BuildDelayedSend builds and signs a send block on top of a frontier state,
+// which may be a future state of the account,
+// state: the frontier state the send builds on,
+// toAddress: the destination wallet address,
+// raw: the amount to send in raw,
+// privateKey: the private key of the sending wallet,
+// returns the delayed send or an error.
+func BuildDelayedSend(state FrontierState, toAddress, raw string, privateKey [32]byte) (DelayedSend, error) {
+	pubKey, err := PrivateKeyToPublicKey(privateKey)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	addr, err := PublicKeyToAddress(pubKey)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	balAfter, err := BalanceAfterSend(state.Balance, raw)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	rcptPubKey, err := AddressToPublicKey(toAddress)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	block := Block{
+		Type:           "state",
+		Account:        addr,
+		Previous:       state.Frontier,
+		Representative: state.Representative,
+		Balance:        balAfter,
+		Link:           fmt.Sprintf("%064X", rcptPubKey),
+		LinkAsAccount:  toAddress,
+	}
+
+	if err := block.Sign(privateKey); err != nil {
+		return DelayedSend{}, err
+	}
+
+	return DelayedSend{Block: block}, nil
+}
+
+// PrepareDelayedSend builds and signs a send block on top of the current frontier
+// of the wallet and generates its work,
+// toAddress: the destination wallet address (or a handle resolved with the AliasResolver),
+// raw: the amount to send in raw,
+// seed: the seed of the sending wallet,
+// index: the index of the sending wallet (usually 0),
+// returns the delayed send or an error.
+func (c *Client) PrepareDelayedSend(toAddress, raw, seed string, index int) (DelayedSend, error) {
+	toAddress, err := c.resolveDestination(toAddress)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	if err := c.checkCounterparty(toAddress, Outgoing); err != nil {
+		return DelayedSend{}, err
+	}
+
+	privKey, err := SeedToPrivateKey(seed, index)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	pubKey, err := PrivateKeyToPublicKey(privKey)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	addr, err := PublicKeyToAddress(pubKey)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	state, err := c.sendState(addr)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	d, err := BuildDelayedSend(state, toAddress, raw, privKey)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	work, err := c.GenerateWork(d.Block)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	d.Block.AddWork(work)
+
+	return d, nil
+}
+
+// Seal encrypts the delayed send with AES-256-GCM for storage,
+// key: the encryption key,
+// returns the sealed delayed send or an error.
+func (d DelayedSend) Seal(key [32]byte) ([]byte, error) {
+	plain, err := json.Marshal(d)
+
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newEscrowCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plain, nil), nil
+}
+
+// OpenDelayedSend decrypts a delayed send sealed with Seal,
+// sealed: the sealed delayed send,
+// key: the encryption key,
+// returns the delayed send or an error.
+func OpenDelayedSend(sealed []byte, key [32]byte) (DelayedSend, error) {
+	gcm, err := newEscrowCipher(key)
+
+	if err != nil {
+		return DelayedSend{}, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return DelayedSend{}, fmt.Errorf("sealed delayed send is too short")
+	}
+
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+
+	if err != nil {
+		return DelayedSend{}, fmt.Errorf("could not open delayed send: %v", err)
+	}
+
+	var d DelayedSend
+
+	if err := json.Unmarshal(plain, &d); err != nil {
+		return DelayedSend{}, err
+	}
+
+	return d, nil
+}
+
+// Release publishes a delayed send if its account frontier still matches the
+// previous block of the send, generating work if it has none, the send being held
+// like any other by the ApprovalGate of the client if set,
+// d: the delayed send,
+// returns the block hash, ErrFrontierMismatch if the account moved on, or an error.
+func (c *Client) Release(d DelayedSend) (string, error) {
+	return c.release(d, true)
+}
+
+// release publishes a delayed send like Release, holding the lock of its account and
+// going through the ApprovalGate of the client if gated is true.
+func (c *Client) release(d DelayedSend, gated bool) (string, error) {
+	addr := canonicalAddress(d.Block.Account)
+
+	defer c.lockAccount(addr)()
+
+	frontiers, err := c.GetAccountsFrontiers([]string{addr})
+
+	if err != nil {
+		return "", err
+	}
+
+	if frontier := frontierOf(frontiers, addr); !strings.EqualFold(frontier, d.Block.Previous) {
+		c.forgetAccount(addr)
+
+		return "", fmt.Errorf("%w: expected %s, got %s", ErrFrontierMismatch, d.Block.Previous, frontier)
+	}
+
+	return c.publishSigned("send", d.Block, gated)
+}
+
+// ReleaseWhen checks a release condition every interval and releases the delayed send
+// once it is met,
+// ctx: the context stopping the checks,
+// d: the delayed send,
+// condition: the release condition,
+// interval: the interval between checks,
+// returns the block hash or an error (including errors of the condition).
+func (c *Client) ReleaseWhen(ctx context.Context, d DelayedSend, condition func() (bool, error), interval time.Duration) (string, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := condition()
+
+		if err != nil {
+			return "", err
+		}
+
+		if ok {
+			return c.Release(d)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func newEscrowCipher(key [32]byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key[:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,140 @@
+package nanogo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DerivedAddress is an address derived from a wallet, without private material,
+// Index: the index of the account,
+// Address: the wallet address of the account.
+type DerivedAddress struct {
+	Index   int    `json:"index"`
+	Address string `json:"address"`
+}
+
+// DeriveAddresses derives the addresses of a range of accounts in parallel,
+// without caching the accounts or keeping their private keys,
+// start: the index of the first account,
+// count: the count of accounts,
+// returns the addresses ordered by index or an error.
+func (w *Wallet) DeriveAddresses(start, count int) ([]DerivedAddress, error) {
+	if start < 0 || count < 0 {
+		return nil, fmt.Errorf("start and count must not be negative")
+	}
+
+	addrs := make([]DerivedAddress, count)
+	errs := make([]error, count)
+
+	workers := runtime.NumCPU()
+
+	if workers > count {
+		workers = count
+	}
+
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				addrs[i], errs[i] = w.deriveAddress(start + i)
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return addrs, nil
+}
+
+func (w *Wallet) deriveAddress(index int) (DerivedAddress, error) {
+	privKey, err := w.derive(index)
+
+	if err != nil {
+		return DerivedAddress{}, err
+	}
+
+	pubKey, err := PrivateKeyToPublicKey(privKey)
+
+	if err != nil {
+		return DerivedAddress{}, err
+	}
+
+	addr, err := PublicKeyToAddress(pubKey)
+
+	if err != nil {
+		return DerivedAddress{}, err
+	}
+
+	return DerivedAddress{Index: index, Address: addr}, nil
+}
+
+// ExportAddresses exports the addresses of a range of accounts of the wallet,
+// start: the index of the first account,
+// count: the count of accounts,
+// format: the format of the export (csv or json),
+// returns the export or an error.
+func (w *Wallet) ExportAddresses(start, count int, format string) ([]byte, error) {
+	addrs, err := w.DeriveAddresses(start, count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		return json.MarshalIndent(addrs, "", "  ")
+	case "csv":
+		var buf bytes.Buffer
+		cw := csv.NewWriter(&buf)
+		cw.Write([]string{"index", "address"})
+
+		for _, a := range addrs {
+			cw.Write([]string{strconv.Itoa(a.Index), a.Address})
+		}
+
+		cw.Flush()
+
+		return buf.Bytes(), cw.Error()
+	default:
+		return nil, fmt.Errorf("unknown export format (%s)", format)
+	}
+}
+
+// ExportAddresses exports the addresses of a range of accounts of a seed (Nano derivation),
+// seed: the seed in hex,
+// start: the index of the first account,
+// count: the count of accounts,
+// format: the format of the export (csv or json),
+// returns the export or an error.
+func ExportAddresses(seed string, start, count int, format string) ([]byte, error) {
+	w, err := NewWalletFromSeed(seed, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return w.ExportAddresses(start, count, format)
+}
@@ -0,0 +1,88 @@
+package nanogo
+
+import (
+	"context"
+	"time"
+)
+
+// IsReadAction checks if an RPC action only reads from the node, so it is safe to
+// retry and to send to failover servers,
+// action: the RPC action to check,
+// returns true if the action is a read action, false otherwise.
+func IsReadAction(action string) bool {
+	if action == "process" || action == "sign" || action == "work_generate" {
+		return false
+	}
+
+	for _, a := range ControlActions {
+		if a == action {
+			return false
+		}
+	}
+
+	return true
+}
+
+// do sends the JSON-RPC request, retrying read actions with backoff over Url and the failover Urls.
+func (c *Client) do(ctx context.Context, data map[string]any) ([]byte, error) {
+	action, _ := data["action"].(string)
+
+	if !IsReadAction(action) {
+		return c.attempt(ctx, c.Url, data, action, 0)
+	}
+
+	urls := append([]string{c.Url}, c.Urls...)
+	backoff := c.Backoff
+
+	if backoff <= 0 {
+		backoff = 200 * time.Millisecond
+	}
+
+	var lastErr error
+	retry := 0
+
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+		}
+
+		for _, url := range urls {
+			res, err := c.attempt(ctx, url, data, action, retry)
+			retry++
+
+			if err == nil {
+				return res, nil
+			}
+
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			lastErr = err
+		}
+	}
+
+	return nil, lastErr
+}
+
+// attempt posts the JSON-RPC request to one server and records the attempt in the trace of the client.
+func (c *Client) attempt(ctx context.Context, url string, data map[string]any, action string, retry int) ([]byte, error) {
+	start := time.Now()
+	res, err := c.post(ctx, url, data)
+
+	a := Attempt{Action: action, Url: url, Retry: retry, Latency: time.Since(start)}
+
+	if err != nil {
+		a.Error = err.Error()
+	}
+
+	c.tracer.attempt(a)
+
+	return res, err
+}
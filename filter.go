@@ -0,0 +1,114 @@
+package nanogo
+
+import "math/big"
+
+// EventFilter filters event streams before they reach user code,
+// Include: the only wallet addresses passed, as account or counterparty (empty passes every wallet),
+// Exclude: the wallet addresses dropped, as account or counterparty (optional),
+// MinAmount: the minimum amount passed (optional),
+// MaxAmount: the maximum amount passed (optional).
+// Addresses match regardless of their nano_ or xrb_ prefix.
+type EventFilter struct {
+	Include   []string
+	Exclude   []string // optional
+	MinAmount Raw      // optional
+	MaxAmount Raw      // optional
+}
+
+// Match checks if an event passes the filter,
+// amount: the amount of the event in raw,
+// accounts: the wallet addresses of the event,
+// returns true if the event passes the filter, false otherwise.
+func (f EventFilter) Match(amount string, accounts ...string) bool {
+	keys := make([]string, 0, len(accounts))
+
+	for _, a := range accounts {
+		if a != "" {
+			keys = append(keys, counterpartyKey(a))
+		}
+	}
+
+	for _, e := range f.Exclude {
+		for _, k := range keys {
+			if counterpartyKey(e) == k {
+				return false
+			}
+		}
+	}
+
+	if len(f.Include) > 0 && !f.includes(keys) {
+		return false
+	}
+
+	if f.MinAmount.IsZero() && f.MaxAmount.IsZero() {
+		return true
+	}
+
+	raw, ok := new(big.Int).SetString(amount, 10)
+
+	if !ok {
+		return false
+	}
+
+	if !f.MinAmount.IsZero() && raw.Cmp(f.MinAmount.BigInt()) < 0 {
+		return false
+	}
+
+	if !f.MaxAmount.IsZero() && raw.Cmp(f.MaxAmount.BigInt()) > 0 {
+		return false
+	}
+
+	return true
+}
+
+func (f EventFilter) includes(keys []string) bool {
+	for _, i := range f.Include {
+		for _, k := range keys {
+			if counterpartyKey(i) == k {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// FilterConfirmations filters a stream of confirmations by their account and counterparty,
+// in: the stream to filter,
+// filter: the filter,
+// returns the filtered stream, closed when in is closed.
+func FilterConfirmations(in <-chan ConfirmationMessage, filter EventFilter) <-chan ConfirmationMessage {
+	out := make(chan ConfirmationMessage)
+
+	go func() {
+		defer close(out)
+
+		for m := range in {
+			if filter.Match(m.Amount, m.Account, m.Block.LinkAsAccount) {
+				out <- m
+			}
+		}
+	}()
+
+	return out
+}
+
+// FilterDeposits filters a stream of deposits by their account and source,
+// in: the stream to filter,
+// filter: the filter,
+// returns the filtered stream, closed when in is closed.
+func FilterDeposits(in <-chan Deposit, filter EventFilter) <-chan Deposit {
+	out := make(chan Deposit)
+
+	go func() {
+		defer close(out)
+
+		for d := range in {
+			if filter.Match(d.Amount, d.Account, d.Source) {
+				out <- d
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,181 @@
+package nanogo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ChainCheckpoint is the last block of a wallet processed locally,
+// Account: the wallet address,
+// Height: the height of the block (0 if no block was processed),
+// Hash: the hash of the block (optional, checked to be on the chain of the wallet).
+type ChainCheckpoint struct {
+	Account string `json:"account" protobuf:"1"`
+	Height  uint64 `json:"height" protobuf:"2"`
+	Hash    string `json:"hash" protobuf:"3"`
+}
+
+// ChainGap is a range of blocks of a wallet missing locally,
+// Account: the wallet address,
+// From: the height of the first missing block,
+// To: the height of the last block reported by the node.
+type ChainGap struct {
+	Account string `json:"account" protobuf:"1"`
+	From    uint64 `json:"from" protobuf:"2"`
+	To      uint64 `json:"to" protobuf:"3"`
+}
+
+// GapRepair is the event of the repair of a gap,
+// Gap: the repaired gap,
+// Blocks: the backfilled blocks including the change and epoch blocks, oldest first in the order to apply them,
+// Checkpoint: the checkpoint after the backfilled blocks,
+// Error: the error of the repair (empty on success).
+type GapRepair struct {
+	Gap        ChainGap        `json:"gap" protobuf:"1"`
+	Blocks     AccountHistory  `json:"blocks" protobuf:"2"`
+	Checkpoint ChainCheckpoint `json:"checkpoint" protobuf:"3"`
+	Error      string          `json:"error,omitempty" protobuf:"4"`
+}
+
+// GapChecker detects blocks missing between local checkpoints and the chains reported
+// by the node, e.g. after switching to another node, and backfills them by traversing the chains,
+// Client: the client of the node,
+// OnRepair: called with every repair event (optional),
+// Events: the sink persisting the repair events (optional).
+type GapChecker struct {
+	Client   *Client
+	OnRepair func(event GapRepair) // optional
+	Events   EventSink             // optional
+}
+
+// Check compares a checkpoint with the chain of its wallet on the node,
+// cp: the local checkpoint,
+// returns the gap and true if blocks are missing locally, an error wrapping
+// ErrCheckpointNotOnChain if the checkpoint block is not on the chain, an error
+// wrapping ErrNodeBehind if the node has fewer blocks than the checkpoint, or an error.
+func (g *GapChecker) Check(cp ChainCheckpoint) (ChainGap, bool, error) {
+	if cp.Hash != "" {
+		info, err := g.Client.GetBlockInfo(cp.Hash)
+
+		if errors.Is(err, ErrBlockNotFound) {
+			return ChainGap{}, false, fmt.Errorf("%w: %s", ErrCheckpointNotOnChain, cp.Hash)
+		}
+
+		if err != nil {
+			return ChainGap{}, false, err
+		}
+
+		if canonicalAddress(info.BlockAccount) != canonicalAddress(cp.Account) || info.Height != strconv.FormatUint(cp.Height, 10) {
+			return ChainGap{}, false, fmt.Errorf("%w: %s is block %s of %s", ErrCheckpointNotOnChain, cp.Hash, info.Height, info.BlockAccount)
+		}
+	}
+
+	count, err := g.Client.GetAccountBlockCount(cp.Account)
+
+	if errors.Is(err, ErrAccountNotFound) {
+		count, err = 0, nil
+	}
+
+	if err != nil {
+		return ChainGap{}, false, err
+	}
+
+	if count < cp.Height {
+		return ChainGap{}, false, fmt.Errorf("%w: node has %d blocks of %s, checkpoint is at height %d", ErrNodeBehind, count, cp.Account, cp.Height)
+	}
+
+	if count == cp.Height {
+		return ChainGap{}, false, nil
+	}
+
+	return ChainGap{Account: cp.Account, From: cp.Height + 1, To: count}, true, nil
+}
+
+// Repair checks a checkpoint and backfills the missing blocks, emitting a repair event
+// for every gap found, repaired or not,
+// cp: the local checkpoint,
+// returns the repair (with no blocks if there was no gap) or an error.
+func (g *GapChecker) Repair(cp ChainCheckpoint) (GapRepair, error) {
+	gap, ok, err := g.Check(cp)
+
+	if err != nil || !ok {
+		return GapRepair{Checkpoint: cp}, err
+	}
+
+	repair, err := g.backfill(cp, gap)
+
+	if err != nil {
+		repair = GapRepair{Gap: gap, Checkpoint: cp, Error: err.Error()}
+	}
+
+	if emitErr := g.emit(repair); err == nil {
+		err = emitErr
+	}
+
+	return repair, err
+}
+
+// RepairAll repairs the gaps of multiple checkpoints, failed checkpoints don't stop the others,
+// checkpoints: the local checkpoints,
+// returns the checkpoints after the repairs (unchanged if failed) and an error joining the errors of the failed checkpoints.
+func (g *GapChecker) RepairAll(checkpoints []ChainCheckpoint) ([]ChainCheckpoint, error) {
+	repaired := make([]ChainCheckpoint, len(checkpoints))
+	var errs []error
+
+	for i, cp := range checkpoints {
+		repair, err := g.Repair(cp)
+		repaired[i] = repair.Checkpoint
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", cp.Account, err))
+		}
+	}
+
+	return repaired, errors.Join(errs...)
+}
+
+// backfill traverses the chain down from the frontier to the checkpoint and checks the heights are contiguous.
+func (g *GapChecker) backfill(cp ChainCheckpoint, gap ChainGap) (GapRepair, error) {
+	// raw, the heights of the change and epoch blocks would be missing otherwise
+	history, err := g.Client.historySinceHeight(cp.Account, cp.Height, true)
+
+	if err != nil {
+		return GapRepair{}, err
+	}
+
+	blocks := AccountHistory{Account: history.Account}
+
+	for i := len(history.History) - 1; i >= 0; i-- {
+		h := history.History[i]
+		expected := cp.Height + uint64(len(blocks.History)) + 1
+
+		if h.Height != strconv.FormatUint(expected, 10) {
+			return GapRepair{}, fmt.Errorf("node returned block %s at height %s instead of %d", h.Hash, h.Height, expected)
+		}
+
+		blocks.History = append(blocks.History, h)
+	}
+
+	// the chain may have grown since the check, but not shrunk
+	if uint64(len(blocks.History)) < gap.To-cp.Height {
+		return GapRepair{}, fmt.Errorf("node returned %d blocks of %s above height %d, expected %d", len(blocks.History), cp.Account, cp.Height, gap.To-cp.Height)
+	}
+
+	last := blocks.History[len(blocks.History)-1]
+	checkpoint := ChainCheckpoint{Account: cp.Account, Height: cp.Height + uint64(len(blocks.History)), Hash: last.Hash}
+
+	return GapRepair{Gap: gap, Blocks: blocks, Checkpoint: checkpoint}, nil
+}
+
+func (g *GapChecker) emit(repair GapRepair) error {
+	if g.OnRepair != nil {
+		g.OnRepair(repair)
+	}
+
+	if g.Events != nil {
+		return g.Events.Append(repair)
+	}
+
+	return nil
+}
@@ -4,8 +4,16 @@ go 1.20
 
 require (
 	filippo.io/edwards25519 v1.1.0
+	github.com/gorilla/websocket v1.5.3
 	github.com/shopspring/decimal v1.4.0
+	github.com/tyler-smith/go-bip39 v1.1.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
 	golang.org/x/crypto v0.22.0
+	google.golang.org/protobuf v1.34.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-require golang.org/x/sys v0.19.0 // indirect
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/sys v0.19.0 // indirect
+)
@@ -0,0 +1,103 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// GetAccountBlockCount gets the count of blocks of a wallet, the height of its frontier,
+// address: the wallet address,
+// returns the block count or an error.
+func (c *Client) GetAccountBlockCount(address string) (uint64, error) {
+	data := map[string]any{
+		"action":  "account_block_count",
+		"account": address,
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var body struct {
+		BlockCount string `json:"block_count"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return 0, rpcError(body.Error)
+	}
+
+	count, err := strconv.ParseUint(body.BlockCount, 10, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("could not parse block count (%s)", body.BlockCount)
+	}
+
+	return count, nil
+}
+
+// GetBlockAtHeight gets the block of a wallet at a height, traversing the chain down from the frontier,
+// address: the wallet address,
+// height: the height of the block (1 for the open block),
+// returns the block hash and info, ErrBlockNotFound if the chain is shorter, or an error.
+func (c *Client) GetBlockAtHeight(address string, height uint64) (string, BlockInfo, error) {
+	info, err := c.GetAccountInfo(address)
+
+	if err != nil {
+		return "", BlockInfo{}, err
+	}
+
+	count, err := strconv.ParseUint(info.BlockCount, 10, 64)
+
+	if err != nil {
+		return "", BlockInfo{}, fmt.Errorf("could not parse block count (%s)", info.BlockCount)
+	}
+
+	if height == 0 || height > count {
+		return "", BlockInfo{}, fmt.Errorf("%w: height %d of %s (%d blocks)", ErrBlockNotFound, height, address, count)
+	}
+
+	data := map[string]any{
+		"action":  "account_history",
+		"account": address,
+		"head":    info.Frontier,
+		"offset":  count - height,
+		"count":   1,
+		"raw":     "true",
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return "", BlockInfo{}, err
+	}
+
+	var history AccountHistory
+	json.Unmarshal(res, &history)
+
+	if history.Error != nil {
+		return "", BlockInfo{}, rpcError(history.Error)
+	}
+
+	if len(history.History) == 0 {
+		return "", BlockInfo{}, fmt.Errorf("%w: height %d of %s", ErrBlockNotFound, height, address)
+	}
+
+	hash := history.History[0].Hash
+	block, err := c.GetBlockInfo(hash)
+
+	if err != nil {
+		return "", BlockInfo{}, err
+	}
+
+	if block.Height != strconv.FormatUint(height, 10) {
+		return "", BlockInfo{}, fmt.Errorf("node returned block %s at height %s instead of %d", hash, block.Height, height)
+	}
+
+	return hash, block, nil
+}
@@ -0,0 +1,168 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"github.com/zenitria/nanogo"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIntegrationNodeReachable(t *testing.T) {
+	step(t, func(t *testing.T) {
+		deadline := time.Now().Add(h.timeout)
+
+		for {
+			_, err := h.client.GetBlockCount()
+
+			if err == nil {
+				return
+			}
+
+			if time.Now().After(deadline) {
+				t.Fatal(err)
+			}
+
+			time.Sleep(time.Second)
+		}
+	})
+}
+
+func TestIntegrationGenesisVotes(t *testing.T) {
+	step(t, func(t *testing.T) {
+		balance, err := h.client.GetAccountBalance(h.genesis)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if balance.Balance == "" || balance.Balance == "0" {
+			t.Fatalf("genesis account %s has no balance, is the node on the dev network?", h.genesis)
+		}
+
+		res, err := h.client.RPC(map[string]any{"action": "wallet_create"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wallet := jsonField(res, "wallet")
+
+		if wallet == "" {
+			t.Fatalf("could not create wallet: %s", res)
+		}
+
+		res, err = h.client.RPC(map[string]any{"action": "wallet_add", "wallet": wallet, "key": fmt.Sprintf("%X", h.genesisKey)})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if jsonField(res, "account") != h.genesis {
+			t.Fatalf("could not add genesis key: %s", res)
+		}
+	})
+}
+
+func TestIntegrationWebSocketSubscribes(t *testing.T) {
+	step(t, func(t *testing.T) {
+		if err := h.ws.Connect(h.ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := h.ws.SubscribeConfirmations(h.accounts); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestIntegrationGenesisFunds(t *testing.T) {
+	step(t, func(t *testing.T) {
+		payouts := make([]nanogo.Payout, len(h.accounts))
+
+		for i, a := range h.accounts {
+			payouts[i] = nanogo.Payout{Address: a, Amount: fundAmount}
+		}
+
+		batch, err := h.client.PresignBatchWithSigner(h.genesis, nanogo.PrivateKeySigner{PrivateKey: h.genesisKey}, payouts)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hashes, err := batch.Publish()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, hash := range hashes {
+			if err := h.client.WaitConfirmed(hash, h.timeout, time.Second); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestIntegrationReceive(t *testing.T) {
+	step(t, func(t *testing.T) {
+		for i, a := range h.accounts {
+			result, err := h.client.ReceiveAllWithOptions(h.seed, i, nanogo.ReceiveOptions{Confirm: true, ConfirmTimeout: h.timeout})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := result.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			expectBalance(t, a, fundAmount)
+		}
+	})
+}
+
+func TestIntegrationSend(t *testing.T) {
+	step(t, func(t *testing.T) {
+		hash, err := h.client.Send(h.accounts[1], sendAmount, h.seed, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		h.sent = hash
+
+		if err := h.client.WaitConfirmed(hash, h.timeout, time.Second); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := h.client.ReceiveAll(h.seed, 1); err != nil {
+			t.Fatal(err)
+		}
+
+		expectBalance(t, h.accounts[0], "900000000000000000000000000000")
+	})
+}
+
+func TestIntegrationWebSocketDelivers(t *testing.T) {
+	step(t, func(t *testing.T) {
+		timeout := time.After(h.timeout)
+
+		for {
+			select {
+			case m, ok := <-h.ws.Confirmations():
+				if !ok {
+					t.Fatal("websocket closed")
+				}
+
+				if strings.EqualFold(m.Hash, h.sent) {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("send %s not delivered", h.sent)
+			}
+		}
+	})
+}
@@ -0,0 +1,221 @@
+//go:build integration
+
+// Package integration runs the integration tests of the library against a node of
+// the dev network in Docker: TestMain starts the node and the tests fund test accounts
+// from the genesis account and exercise sends, receives and WebSocket confirmations
+// end to end, in order, each depending on the previous ones.
+//
+//	go test -tags integration ./integration
+package integration
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/zenitria/nanogo"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// devGenesisKey is the private key of the genesis account of the dev network.
+const devGenesisKey = "34F0A37AAD20F4A260F0A5B3CB3D7FB50673212263E58A380BC10474BB039CE4"
+
+// devWorkThreshold is the work threshold of the dev network.
+const devWorkThreshold = 0xfe00000000000000
+
+const (
+	fundAmount = "1000000000000000000000000000000"
+	sendAmount = "100000000000000000000000000000"
+)
+
+var (
+	image      = flag.String("image", "nanocurrency/nano:latest", "the Docker image of the node")
+	container  = flag.String("container", "nanogo-integration", "the name of the container")
+	rpcUrl     = flag.String("rpc", "http://127.0.0.1:45000", "the url of the RPC server")
+	wsUrl      = flag.String("ws", "ws://127.0.0.1:47000", "the url of the WebSocket server")
+	genesisKey = flag.String("genesis-key", devGenesisKey, "the private key of the genesis account")
+	external   = flag.Bool("external", false, "use a running node instead of starting a container")
+	keep       = flag.Bool("keep", false, "keep the container running after the tests")
+	timeout    = flag.Duration("timeout", time.Minute, "the timeout of every wait")
+)
+
+// harness is the state shared by the tests.
+type harness struct {
+	ctx        context.Context
+	client     *nanogo.Client
+	ws         *nanogo.WSClient
+	genesisKey [32]byte
+	genesis    string
+	seed       string
+	accounts   []string
+	sent       string
+	timeout    time.Duration
+	failed     bool
+}
+
+// h is the harness of the tests, set up by TestMain.
+var h *harness
+
+// devWork generates work with the work_generate RPC at the dev network threshold,
+// the client would request the live thresholds otherwise.
+type devWork struct {
+	nanogo.RPCWorkProvider
+}
+
+func (w devWork) GenerateWork(hash string, difficulty uint64) (string, error) {
+	return w.RPCWorkProvider.GenerateWork(hash, devWorkThreshold)
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	os.Exit(start(m))
+}
+
+// start sets up the harness, starts the node and runs the tests,
+// returns the exit code.
+func start(m *testing.M) int {
+	var err error
+
+	if h, err = newHarness(*rpcUrl, *wsUrl, *genesisKey, *timeout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if !*external {
+		if err := startNode(*image, *container); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+
+		if !*keep {
+			defer exec.Command("docker", "stop", *container).Run()
+		}
+	}
+
+	defer h.ws.Close()
+
+	return m.Run()
+}
+
+func newHarness(rpc, ws, genesisKey string, timeout time.Duration) (*harness, error) {
+	h := &harness{
+		ctx:     context.Background(),
+		client:  &nanogo.Client{Url: rpc},
+		ws:      &nanogo.WSClient{Url: ws},
+		timeout: timeout,
+	}
+
+	h.client.Work = devWork{nanogo.RPCWorkProvider{Client: h.client}}
+
+	key, err := hex.DecodeString(genesisKey)
+
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("could not decode genesis key")
+	}
+
+	copy(h.genesisKey[:], key)
+	pubKey, err := nanogo.PrivateKeyToPublicKey(h.genesisKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if h.genesis, err = nanogo.PublicKeyToAddress(pubKey); err != nil {
+		return nil, err
+	}
+
+	var seed [32]byte
+
+	if _, err := rand.Read(seed[:]); err != nil {
+		return nil, err
+	}
+
+	h.seed = hex.EncodeToString(seed[:])
+	wallet, err := nanogo.NewWalletFromSeed(h.seed, h.client)
+
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := wallet.DeriveAddresses(0, 2)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range addrs {
+		h.accounts = append(h.accounts, a.Address)
+	}
+
+	return h, nil
+}
+
+// startNode starts a node of the dev network with RPC control and WebSocket enabled.
+func startNode(image, name string) error {
+	cmd := exec.Command("docker", "run", "-d", "--rm", "--name", name,
+		"-p", "127.0.0.1:45000:45000", "-p", "127.0.0.1:47000:47000",
+		"--entrypoint", "nano_node", image,
+		"--daemon", "--network=dev", "--data_path=/root/Nano",
+		"--config", "node.enable_voting=true",
+		"--config", "rpc.enable=true",
+		"--config", "node.websocket.enable=true",
+		"--config", `node.websocket.address="::ffff:0.0.0.0"`,
+		"--rpcconfig", "enable_control=true",
+		"--rpcconfig", `address="::ffff:0.0.0.0"`,
+	)
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not start node: %v", err)
+	}
+
+	return nil
+}
+
+// step runs a test depending on the previous ones, skipped after a failure.
+func step(t *testing.T, fn func(t *testing.T)) {
+	t.Helper()
+
+	if h.failed {
+		t.Skip("a previous test failed")
+	}
+
+	defer func() {
+		if t.Failed() {
+			h.failed = true
+		}
+	}()
+
+	fn(t)
+}
+
+func expectBalance(t *testing.T, address, raw string) {
+	t.Helper()
+
+	balance, err := h.client.GetAccountBalance(address)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if balance.Balance != raw {
+		t.Fatalf("balance of %s is %s instead of %s", address, balance.Balance, raw)
+	}
+}
+
+func jsonField(res []byte, field string) string {
+	var body map[string]any
+
+	if err := json.Unmarshal(res, &body); err != nil {
+		return ""
+	}
+
+	s, _ := body[field].(string)
+
+	return s
+}
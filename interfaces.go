@@ -0,0 +1,38 @@
+package nanogo
+
+// RPCCaller sends raw JSON-RPC requests, it is implemented by Client.
+type RPCCaller interface {
+	RPC(data map[string]any) ([]byte, error)
+}
+
+// AccountReader reads the state of wallets, it is implemented by Client.
+type AccountReader interface {
+	GetAccountBalance(address string) (AccountBalance, error)
+	GetAccountInfo(address string) (AccountInfo, error)
+	GetAccountHistory(address string, count int) (AccountHistory, error)
+	GetReceivable(address string) (Receivable, error)
+}
+
+// BlockReader reads blocks, it is implemented by Client.
+type BlockReader interface {
+	GetBlockInfo(hash string) (BlockInfo, error)
+	GetBlocksInfo(hashes []string) (map[string]BlockInfo, error)
+}
+
+// BlockPublisher publishes signed blocks, it is implemented by Client.
+type BlockPublisher interface {
+	Process(subtype string, block Block) (string, error)
+}
+
+// WorkGenerator generates work with the node, it is implemented by Client.
+type WorkGenerator interface {
+	WorkGenerate(hash string, difficulty uint64) (string, error)
+}
+
+var (
+	_ RPCCaller      = (*Client)(nil)
+	_ AccountReader  = (*Client)(nil)
+	_ BlockReader    = (*Client)(nil)
+	_ BlockPublisher = (*Client)(nil)
+	_ WorkGenerator  = (*Client)(nil)
+)
@@ -0,0 +1,128 @@
+package nanogo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// JournalDeposit is a confirmed incoming payment observed by a DepositMonitor.
+	JournalDeposit = "deposit"
+	// JournalPayout is a send published by an account of the wallet.
+	JournalPayout = "payout"
+	// JournalReceive is a receive published by an account of the wallet.
+	JournalReceive = "receive"
+)
+
+// JournalEvent is an event of the journal of a wallet,
+// Seq: the sequence number of the event in the journal, starting at 1,
+// Kind: the kind of the event (JournalDeposit, JournalPayout or JournalReceive),
+// Account: the wallet address of the account of the wallet,
+// Counterparty: the destination of a payout or the source of a deposit or receive,
+// Amount: the amount in raw,
+// Hash: the hash of the block of the event (the send block of a deposit),
+// Source: the hash of the received send block of a receive,
+// Time: the time the event was recorded.
+type JournalEvent struct {
+	Seq          uint64    `json:"seq" protobuf:"1"`
+	Kind         string    `json:"kind" protobuf:"2"`
+	Account      string    `json:"account" protobuf:"3"`
+	Counterparty string    `json:"counterparty" protobuf:"4"`
+	Amount       string    `json:"amount" protobuf:"5"`
+	Hash         string    `json:"hash" protobuf:"6"`
+	Source       string    `json:"source,omitempty" protobuf:"7"`
+	Time         time.Time `json:"time" protobuf:"8"`
+}
+
+// WalletJournal is the persistent journal of the deposits, payouts and receives of a wallet,
+// replayable into a fresh consumer to rebuild downstream databases without re-querying the chain,
+// it is an EventSink accepting JournalEvent and Deposit events (e.g. the Events of a DepositMonitor),
+// Log: the event log of the journal.
+type WalletJournal struct {
+	Log *FileEventLog
+
+	mu     sync.Mutex
+	seq    uint64
+	loaded bool
+}
+
+// Append records an event, EventSink implementation,
+// event: a JournalEvent or a Deposit,
+// returns an error if the event could not be recorded or has another type.
+func (j *WalletJournal) Append(event any) error {
+	switch e := event.(type) {
+	case JournalEvent:
+		return j.Record(e)
+	case Deposit:
+		return j.Record(JournalEvent{Kind: JournalDeposit, Account: e.Account, Counterparty: e.Source, Amount: e.Amount, Hash: e.Hash, Time: e.ConfirmedAt})
+	default:
+		return fmt.Errorf("could not journal event of type %T", event)
+	}
+}
+
+// Record records an event with the next sequence number, at the current time if it has none,
+// event: the event,
+// returns an error.
+func (j *WalletJournal) Record(event JournalEvent) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if !j.loaded {
+		err := ReplayEvents(j.Log, func(e JournalEvent) error {
+			j.seq = e.Seq
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+
+		j.loaded = true
+	}
+
+	event.Seq = j.seq + 1
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	if err := j.Log.Append(event); err != nil {
+		return err
+	}
+
+	j.seq = event.Seq
+
+	return nil
+}
+
+// Replay replays every event of the journal in order,
+// fn: called with every event, an error stops the replay,
+// returns an error.
+func (j *WalletJournal) Replay(fn func(event JournalEvent) error) error {
+	return j.ReplayFrom(0, fn)
+}
+
+// ReplayFrom replays the events of the journal after a sequence number in order,
+// for consumers resuming from the last event they applied,
+// seq: the sequence number of the last applied event (0 for all),
+// fn: called with every event, an error stops the replay,
+// returns an error.
+func (j *WalletJournal) ReplayFrom(seq uint64, fn func(event JournalEvent) error) error {
+	return ReplayEvents(j.Log, func(e JournalEvent) error {
+		if e.Seq <= seq {
+			return nil
+		}
+
+		return fn(e)
+	})
+}
+
+// journal records an event of an account of the wallet if the wallet has a journal.
+func (w *Wallet) journal(event JournalEvent) error {
+	if w.Journal == nil {
+		return nil
+	}
+
+	return w.Journal.Record(event)
+}
@@ -0,0 +1,108 @@
+package nanogo
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// SendLimit is a cap of the sends to one destination in a sliding time window,
+// Window: the time window,
+// MaxSends: the maximum number of sends in the window (optional),
+// MaxAmount: the maximum total amount sent in the window (optional).
+type SendLimit struct {
+	Window    time.Duration
+	MaxSends  int // optional
+	MaxAmount Raw // optional
+}
+
+// SendLimiter caps how often and how much is sent to a single destination, protecting
+// against runaway retry loops paying the same wallet repeatedly,
+// Default: the limit of every destination,
+// Destinations: the limits of specific destinations, overriding Default (optional),
+// which must not be changed after the first send.
+// Addresses match regardless of their nano_ or xrb_ prefix.
+type SendLimiter struct {
+	Default      SendLimit
+	Destinations map[string]SendLimit // optional
+
+	once         sync.Once
+	destinations map[string]SendLimit
+	mu           sync.Mutex
+	sends        map[string][]limitedSend
+}
+
+type limitedSend struct {
+	at     time.Time
+	amount *big.Int
+}
+
+// Allow records a send attempt in the window of a destination, attempts that fail
+// count too since a failed request may still have been published,
+// address: the destination wallet address,
+// raw: the amount to send in raw,
+// returns ErrSendLimited if the send exceeds the limit, or an error.
+func (l *SendLimiter) Allow(address, raw string) error {
+	amount, ok := new(big.Int).SetString(raw, 10)
+
+	if !ok {
+		return fmt.Errorf("could not convert string to big int")
+	}
+
+	key := counterpartyKey(address)
+	limit := l.limit(key)
+
+	if limit.Window <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	var kept []limitedSend
+	total := new(big.Int).Set(amount)
+
+	for _, s := range l.sends[key] {
+		if now.Sub(s.at) < limit.Window {
+			kept = append(kept, s)
+			total.Add(total, s.amount)
+		}
+	}
+
+	if limit.MaxSends > 0 && len(kept) >= limit.MaxSends {
+		return fmt.Errorf("%w: %d sends to %s in %s", ErrSendLimited, len(kept), address, limit.Window)
+	}
+
+	if !limit.MaxAmount.IsZero() && total.Cmp(limit.MaxAmount.BigInt()) > 0 {
+		return fmt.Errorf("%w: %s raw to %s in %s", ErrSendLimited, total, address, limit.Window)
+	}
+
+	if l.sends == nil {
+		l.sends = map[string][]limitedSend{}
+	}
+
+	l.sends[key] = append(kept, limitedSend{at: now, amount: amount})
+
+	return nil
+}
+
+func (l *SendLimiter) limit(key string) SendLimit {
+	l.once.Do(func() {
+		l.destinations = make(map[string]SendLimit, len(l.Destinations))
+
+		// the first address in order wins if a destination is listed under both prefixes
+		for _, addr := range sortedKeys(l.Destinations) {
+			if _, ok := l.destinations[counterpartyKey(addr)]; !ok {
+				l.destinations[counterpartyKey(addr)] = l.Destinations[addr]
+			}
+		}
+	})
+
+	if limit, ok := l.destinations[key]; ok {
+		return limit
+	}
+
+	return l.Default
+}
@@ -0,0 +1,17 @@
+//go:build nanogo_lowmem
+
+package nanogo
+
+// LowMemory reports whether the package is built with the nanogo_lowmem build tag for
+// constrained devices: no account state, block and confirmation caches, smaller buffers,
+// bounded RPC responses and histories read page by page.
+const LowMemory = true
+
+const (
+	defaultBlockCacheSize = 64
+	defaultRecentSize     = 256
+	confirmationsBuffer   = 16
+	maxResponseSize       = 1 << 20
+	historyPageSize       = 100
+	caching               = false
+)
@@ -0,0 +1,17 @@
+//go:build !nanogo_lowmem
+
+package nanogo
+
+// LowMemory reports whether the package is built with the nanogo_lowmem build tag for
+// constrained devices: no account state, block and confirmation caches, smaller buffers,
+// bounded RPC responses and histories read page by page.
+const LowMemory = false
+
+const (
+	defaultBlockCacheSize = 1024
+	defaultRecentSize     = 4096
+	confirmationsBuffer   = 128
+	maxResponseSize       = 0
+	historyPageSize       = -1
+	caching               = true
+)
@@ -0,0 +1,82 @@
+package nanogo_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zenitria/nanogo"
+)
+
+// newBenchmarkClient returns a client of a sandbox ledger served over HTTP, so the
+// responses go through the HTTP transport, and an account with a history of sends.
+func newBenchmarkClient(b *testing.B, sends int) (*nanogo.Client, string) {
+	b.Helper()
+
+	l, c, addresses := newTestLedger(b, 2, "1000000")
+
+	for i := 0; i < sends; i++ {
+		if _, err := c.Send(addresses[1], "1", testSeed, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	server := httptest.NewServer(l)
+	b.Cleanup(server.Close)
+
+	return &nanogo.Client{Url: server.URL}, addresses[0]
+}
+
+func BenchmarkGetAccountHistory(b *testing.B) {
+	c, address := newBenchmarkClient(b, 500)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetAccountHistory(address, -1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkScanDestinations(b *testing.B) {
+	c, address := newBenchmarkClient(b, 500)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := c.ScanDestinations(address); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSummarizeAccount(b *testing.B) {
+	c, address := newBenchmarkClient(b, 500)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := c.SummarizeAccount(address); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBlockCache(b *testing.B) {
+	c, address := newBenchmarkClient(b, 1)
+	c.Blocks = &nanogo.BlockCache{}
+	info, err := c.GetAccountInfo(address)
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetBlockInfo(info.Frontier); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
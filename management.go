@@ -0,0 +1,134 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// ManagementClient wraps node lifecycle actions, kept apart from Client so
+// they are only available to tooling that explicitly asks for them,
+// Client: the client of the managed node (usually with enable_control).
+type ManagementClient struct {
+	Client *Client
+}
+
+// NodeID is the identity of a node,
+// Public: the public key of the node,
+// AsAccount: the public key of the node as a wallet address,
+// NodeID: the node id (node_ prefixed),
+// Error: the error of the request.
+type NodeID struct {
+	Public    string `json:"public"`
+	AsAccount string `json:"as_account"`
+	NodeID    string `json:"node_id"`
+
+	Error any `json:"error"`
+}
+
+// TxnTracking is a database transaction held open longer than the requested minimum,
+// Thread: the name of the thread holding the transaction,
+// TimeHeldOpen: the time the transaction is held open in milliseconds,
+// Write: true for a write transaction,
+// Stacktrace: the stacktrace of the thread.
+type TxnTracking struct {
+	Thread       string `json:"thread"`
+	TimeHeldOpen string `json:"time_held_open"`
+	Write        string `json:"write"`
+	Stacktrace   []struct {
+		Name       string `json:"name"`
+		Address    string `json:"address"`
+		SourceFile string `json:"source_file"`
+		SourceLine string `json:"source_line"`
+	} `json:"stacktrace"`
+}
+
+// NodeID gets the identity of the node,
+// returns the node identity or an error.
+func (m *ManagementClient) NodeID() (NodeID, error) {
+	data := map[string]any{
+		"action": "node_id",
+	}
+
+	res, err := m.Client.RPC(data)
+
+	if err != nil {
+		return NodeID{}, err
+	}
+
+	var id NodeID
+	json.Unmarshal(res, &id)
+
+	if id.Error != nil {
+		return NodeID{}, rpcError(id.Error)
+	}
+
+	return id, nil
+}
+
+// Stop stops the node,
+// returns an error.
+func (m *ManagementClient) Stop() error {
+	return m.success(map[string]any{
+		"action": "stop",
+	})
+}
+
+// PopulateBacklog queues unconfirmed frontiers of the node for confirmation,
+// returns an error.
+func (m *ManagementClient) PopulateBacklog() error {
+	return m.success(map[string]any{
+		"action": "populate_backlog",
+	})
+}
+
+// DatabaseTxnTracker gets the database transactions held open longer than the minimums,
+// it requires txn tracking to be enabled in the node config,
+// minReadTime: the minimum time read transactions are held open,
+// minWriteTime: the minimum time write transactions are held open,
+// returns the tracked transactions or an error.
+func (m *ManagementClient) DatabaseTxnTracker(minReadTime, minWriteTime time.Duration) ([]TxnTracking, error) {
+	data := map[string]any{
+		"action":         "database_txn_tracker",
+		"min_read_time":  strconv.FormatInt(minReadTime.Milliseconds(), 10),
+		"min_write_time": strconv.FormatInt(minWriteTime.Milliseconds(), 10),
+	}
+
+	res, err := m.Client.RPC(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		TxnTracking []TxnTracking `json:"txn_tracking"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return nil, rpcError(body.Error)
+	}
+
+	return body.TxnTracking, nil
+}
+
+func (m *ManagementClient) success(data map[string]any) error {
+	res, err := m.Client.RPC(data)
+
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return rpcError(body.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package nanogo
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// MatchMode is the mode a Matcher compares amounts with.
+type MatchMode int
+
+const (
+	// MatchExact matches payments with exactly the expected amount.
+	MatchExact MatchMode = iota
+	// MatchTolerance matches payments within the tolerance of the expected amount.
+	MatchTolerance
+)
+
+// Expectation is an expected incoming payment,
+// ID: the identifier of the expectation (e.g. the invoice id),
+// Account: the wallet address receiving the payment (empty matches any account),
+// Amount: the expected amount in raw.
+type Expectation struct {
+	ID      string
+	Account string
+	Amount  string
+}
+
+// Payment is an incoming confirmed block,
+// Hash: the hash of the block,
+// Account: the wallet address receiving the payment,
+// Source: the wallet address sending the payment,
+// Amount: the amount of the payment in raw.
+type Payment struct {
+	Hash    string
+	Account string
+	Source  string
+	Amount  string
+}
+
+// Matcher matches incoming payments to expected amounts,
+// expectations are kept sorted by amount and looked up with a binary search.
+type Matcher struct {
+	mode      MatchMode
+	tolerance *big.Int
+
+	mu      sync.Mutex
+	seq     uint64
+	entries []matcherEntry
+}
+
+type matcherEntry struct {
+	amount      *big.Int
+	seq         uint64
+	expectation Expectation
+}
+
+// NewMatcher creates a new matcher,
+// mode: the mode to compare amounts with,
+// tolerance: the maximum difference in raw between the paid and expected amount (ignored in exact mode),
+// returns the matcher or an error.
+func NewMatcher(mode MatchMode, tolerance string) (*Matcher, error) {
+	tol := big.NewInt(0)
+
+	if mode == MatchTolerance {
+		var ok bool
+		tol, ok = new(big.Int).SetString(tolerance, 10)
+
+		if !ok || tol.Sign() < 0 {
+			return nil, fmt.Errorf("could not parse tolerance (%s)", tolerance)
+		}
+	}
+
+	return &Matcher{mode: mode, tolerance: tol}, nil
+}
+
+// Expect adds an expected payment,
+// expectation: the expected payment,
+// returns an error if the amount is invalid.
+func (m *Matcher) Expect(expectation Expectation) error {
+	amount, ok := new(big.Int).SetString(expectation.Amount, 10)
+
+	if !ok || amount.Sign() < 0 {
+		return fmt.Errorf("could not parse amount (%s)", expectation.Amount)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.seq++
+	entry := matcherEntry{amount: amount, seq: m.seq, expectation: expectation}
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].amount.Cmp(amount) > 0
+	})
+
+	m.entries = append(m.entries, matcherEntry{})
+	copy(m.entries[i+1:], m.entries[i:])
+	m.entries[i] = entry
+
+	return nil
+}
+
+// Cancel removes an expected payment,
+// id: the identifier of the expectation,
+// returns true if the expectation was removed, false otherwise.
+func (m *Matcher) Cancel(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, e := range m.entries {
+		if e.expectation.ID == id {
+			m.entries = append(m.entries[:i], m.entries[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Len returns the number of pending expectations.
+func (m *Matcher) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.entries)
+}
+
+// Match matches a payment to the closest pending expectation and removes it,
+// the oldest expectation wins when several are equally close,
+// payment: the incoming payment,
+// returns the matched expectation and true, false if nothing matched, or an error.
+func (m *Matcher) Match(payment Payment) (Expectation, bool, error) {
+	paid, ok := new(big.Int).SetString(payment.Amount, 10)
+
+	if !ok {
+		return Expectation{}, false, fmt.Errorf("could not parse amount (%s)", payment.Amount)
+	}
+
+	lo := new(big.Int).Sub(paid, m.tolerance)
+	hi := new(big.Int).Add(paid, m.tolerance)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	start := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].amount.Cmp(lo) >= 0
+	})
+
+	best := -1
+	var bestDiff *big.Int
+
+	for i := start; i < len(m.entries) && m.entries[i].amount.Cmp(hi) <= 0; i++ {
+		e := m.entries[i]
+
+		if e.expectation.Account != "" && e.expectation.Account != payment.Account {
+			continue
+		}
+
+		diff := new(big.Int).Sub(e.amount, paid)
+		diff.Abs(diff)
+
+		if best == -1 || diff.Cmp(bestDiff) < 0 || (diff.Cmp(bestDiff) == 0 && e.seq < m.entries[best].seq) {
+			best = i
+			bestDiff = diff
+		}
+	}
+
+	if best == -1 {
+		return Expectation{}, false, nil
+	}
+
+	matched := m.entries[best].expectation
+	m.entries = append(m.entries[:best], m.entries[best+1:]...)
+
+	return matched, true, nil
+}
@@ -0,0 +1,143 @@
+package nanogo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AccountHealth exports the health of watched wallets as Prometheus gauges, so alerting
+// rules can be written directly against wallet state,
+// Client: the client of the node,
+// Accounts: the watched wallet addresses,
+// Namespace: the prefix of the metric names (default nanogo).
+type AccountHealth struct {
+	Client    AccountReader
+	Accounts  []string
+	Namespace string
+}
+
+// AccountGauges is the health of a watched wallet,
+// Account: the wallet address,
+// ConfirmedBalance: the confirmed balance of the wallet,
+// Receivable: the count of receivable blocks of the wallet,
+// BlocksBehind: the count of blocks of the wallet not confirmed yet.
+type AccountGauges struct {
+	Account          string `json:"account"`
+	ConfirmedBalance Raw    `json:"confirmed_balance"`
+	Receivable       int    `json:"receivable"`
+	BlocksBehind     uint64 `json:"blocks_behind"`
+}
+
+// Collect reads the health of the watched wallets, unopened wallets have empty gauges,
+// returns the gauges in the order of Accounts or an error.
+func (h *AccountHealth) Collect() ([]AccountGauges, error) {
+	gauges := make([]AccountGauges, len(h.Accounts))
+
+	for i, addr := range h.Accounts {
+		g := AccountGauges{Account: addr}
+		info, err := h.Client.GetAccountInfo(addr)
+
+		switch {
+		case errors.Is(err, ErrAccountNotFound):
+		case err != nil:
+			return nil, err
+		default:
+			if g.ConfirmedBalance, err = ParseAmount(info.ConfirmedBalance); err != nil {
+				// nodes without include_confirmed
+				if g.ConfirmedBalance, err = ParseAmount(info.Balance); err != nil {
+					return nil, err
+				}
+			}
+
+			count, err := strconv.ParseUint(info.BlockCount, 10, 64)
+
+			if err != nil {
+				return nil, fmt.Errorf("could not parse block count (%s)", info.BlockCount)
+			}
+
+			confirmed, err := strconv.ParseUint(info.ConfirmationHeight, 10, 64)
+
+			if err == nil && count > confirmed {
+				g.BlocksBehind = count - confirmed
+			}
+		}
+
+		receivable, err := h.Client.GetReceivable(addr)
+
+		if err != nil {
+			return nil, err
+		}
+
+		g.Receivable = len(receivable.Blocks)
+		gauges[i] = g
+	}
+
+	return gauges, nil
+}
+
+// WriteMetrics collects the health of the watched wallets and writes it in the
+// Prometheus text format,
+// w: the writer of the metrics,
+// returns an error.
+func (h *AccountHealth) WriteMetrics(w io.Writer) error {
+	gauges, err := h.Collect()
+
+	if err != nil {
+		return err
+	}
+
+	ns := h.Namespace
+
+	if ns == "" {
+		ns = "nanogo"
+	}
+
+	metrics := []struct {
+		name  string
+		help  string
+		value func(g AccountGauges) string
+	}{
+		{"account_confirmed_balance_nano", "Confirmed balance of the account in Nano.", func(g AccountGauges) string { return g.ConfirmedBalance.Nano() }},
+		{"account_receivable_blocks", "Count of receivable blocks of the account.", func(g AccountGauges) string { return strconv.Itoa(g.Receivable) }},
+		{"account_blocks_behind_confirmation", "Count of blocks of the account not confirmed yet.", func(g AccountGauges) string { return strconv.FormatUint(g.BlocksBehind, 10) }},
+	}
+
+	bw := bufio.NewWriter(w)
+
+	for _, m := range metrics {
+		name := ns + "_" + m.name
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s gauge\n", name, m.help, name)
+
+		for _, g := range gauges {
+			fmt.Fprintf(bw, "%s{account=\"%s\"} %s\n", name, escapeLabel(g.Account), m.value(g))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Handler returns an HTTP handler serving the metrics of the watched wallets, for a
+// Prometheus scrape target.
+func (h *AccountHealth) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf strings.Builder
+
+		if err := h.WriteMetrics(&buf); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		io.WriteString(w, buf.String())
+	})
+}
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,38 @@
+package nanogo
+
+import (
+	"context"
+	"fmt"
+)
+
+// RPCHandler sends a JSON-RPC request and returns the raw response.
+type RPCHandler func(ctx context.Context, data map[string]any) ([]byte, error)
+
+// Middleware wraps the RPC layer of a client, e.g. for logging or metrics.
+type Middleware func(next RPCHandler) RPCHandler
+
+// Recover is a Middleware converting panics of the wrapped handlers into ErrPanic,
+// it is always the outermost middleware of a client.
+func Recover(next RPCHandler) RPCHandler {
+	return func(ctx context.Context, data map[string]any) (res []byte, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				res = nil
+				err = fmt.Errorf("%w: %v", ErrPanic, r)
+			}
+		}()
+
+		return next(ctx, data)
+	}
+}
+
+// handler returns the RPC handler of the client wrapped in its middlewares.
+func (c *Client) handler() RPCHandler {
+	h := RPCHandler(c.do)
+
+	for i := len(c.Middlewares) - 1; i >= 0; i-- {
+		h = c.Middlewares[i](h)
+	}
+
+	return Recover(h)
+}
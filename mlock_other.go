@@ -0,0 +1,8 @@
+//go:build !unix
+
+package nanogo
+
+// lockMemory is a no-op on platforms without mlock.
+func lockMemory(b []byte) func() {
+	return func() {}
+}
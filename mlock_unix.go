@@ -0,0 +1,14 @@
+//go:build unix
+
+package nanogo
+
+import "syscall"
+
+// lockMemory keeps secret material out of swap where the platform permits it.
+func lockMemory(b []byte) func() {
+	if len(b) == 0 || syscall.Mlock(b) != nil {
+		return func() {}
+	}
+
+	return func() { syscall.Munlock(b) }
+}
@@ -0,0 +1,190 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// NodeWallet wraps the wallet actions of a node, whose keys are held by the node,
+// kept apart from Client like ManagementClient since they need enable_control,
+// Client: the client of the node,
+// ID: the id of the wallet on the node.
+type NodeWallet struct {
+	Client *Client
+	ID     string
+}
+
+// ReceiveMinimum gets the receive_minimum setting of the node, the minimum amount its
+// wallets receive automatically,
+// returns the minimum or an error.
+func (w *NodeWallet) ReceiveMinimum() (Raw, error) {
+	data := map[string]any{
+		"action": "receive_minimum",
+	}
+
+	res, err := w.Client.RPC(data)
+
+	if err != nil {
+		return Raw{}, err
+	}
+
+	var body struct {
+		Amount string `json:"amount"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return Raw{}, rpcError(body.Error)
+	}
+
+	return ParseAmount(body.Amount)
+}
+
+// SetReceiveMinimum sets the receive_minimum setting of the node,
+// minimum: the minimum amount in raw,
+// returns an error.
+func (w *NodeWallet) SetReceiveMinimum(minimum Raw) error {
+	data := map[string]any{
+		"action": "receive_minimum_set",
+		"amount": minimum.String(),
+	}
+
+	return (&ManagementClient{Client: w.Client}).success(data)
+}
+
+// Receivable gets the receivable blocks of the accounts of the wallet at or above the
+// receive_minimum of the node,
+// returns the receivable blocks by account or an error.
+func (w *NodeWallet) Receivable() (map[string]Receivable, error) {
+	minimum, err := w.ReceiveMinimum()
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{
+		"action":    "wallet_receivable",
+		"wallet":    w.ID,
+		"threshold": minimum.String(),
+		"source":    "true",
+	}
+
+	res, err := w.Client.RPC(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Blocks map[string]json.RawMessage `json:"blocks"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return nil, rpcError(body.Error)
+	}
+
+	receivable := map[string]Receivable{}
+
+	for account, raw := range body.Blocks {
+		var r Receivable
+
+		// accounts without blocks are an empty string
+		if json.Unmarshal(raw, &r.Blocks) != nil || r.Blocks == nil {
+			continue
+		}
+
+		for hash, b := range r.Blocks {
+			if amount, err := ParseAmount(b.Amount); err == nil && amount.Cmp(minimum) < 0 {
+				delete(r.Blocks, hash)
+			}
+		}
+
+		if len(r.Blocks) > 0 {
+			receivable[account] = r
+		}
+	}
+
+	return receivable, nil
+}
+
+// Receive receives a block with the keys of the wallet on the node,
+// account: the receiving account of the wallet,
+// hash: the hash of the receivable block,
+// returns the hash of the receive block or an error.
+func (w *NodeWallet) Receive(account, hash string) (string, error) {
+	data := map[string]any{
+		"action":  "receive",
+		"wallet":  w.ID,
+		"account": account,
+		"block":   hash,
+	}
+
+	res, err := w.Client.RPC(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Block string `json:"block"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return "", rpcError(body.Error)
+	}
+
+	return body.Block, nil
+}
+
+// ReceiveAll receives the receivable blocks of the accounts of the wallet at or above
+// the receive_minimum of the node, smaller blocks stay receivable,
+// returns the result or an error if the receivable blocks could not be listed.
+func (w *NodeWallet) ReceiveAll() (ReceiveResult, error) {
+	receivable, err := w.Receivable()
+
+	if err != nil {
+		return ReceiveResult{}, err
+	}
+
+	accounts := make([]string, 0, len(receivable))
+
+	for account := range receivable {
+		accounts = append(accounts, account)
+	}
+
+	sort.Strings(accounts)
+
+	var result ReceiveResult
+
+	for _, account := range accounts {
+		hashes := make([]string, 0, len(receivable[account].Blocks))
+
+		for h := range receivable[account].Blocks {
+			hashes = append(hashes, h)
+		}
+
+		sort.Strings(hashes)
+
+		for _, h := range hashes {
+			b := receivable[account].Blocks[h]
+			hash, err := w.Receive(account, h)
+
+			if err != nil {
+				result.Failed = append(result.Failed, ReceiveFailure{Hash: h, Source: b.Source, Amount: b.Amount, Err: err})
+				continue
+			}
+
+			result.Hashes = append(result.Hashes, hash)
+		}
+	}
+
+	return result, nil
+}
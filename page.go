@@ -0,0 +1,295 @@
+package nanogo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// PageToken is an opaque pagination token of HistoryPage, LedgerPage and FrontiersPage,
+// the empty token is the first page and an empty next token means there are no more pages,
+// it can be persisted to resume the pagination after a restart.
+type PageToken string
+
+// pageState is the content of a PageToken.
+type pageState struct {
+	Kind    string `json:"k"`
+	Account string `json:"a,omitempty"`
+	Head    string `json:"h,omitempty"`
+}
+
+func encodePageToken(state pageState) PageToken {
+	data, _ := json.Marshal(state)
+
+	return PageToken(base64.RawURLEncoding.EncodeToString(data))
+}
+
+func (t PageToken) decode(kind string) (pageState, error) {
+	if t == "" {
+		return pageState{Kind: kind}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(string(t))
+
+	if err != nil {
+		return pageState{}, fmt.Errorf("invalid page token: %v", err)
+	}
+
+	var state pageState
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return pageState{}, fmt.Errorf("invalid page token: %v", err)
+	}
+
+	if state.Kind != kind {
+		return pageState{}, fmt.Errorf("invalid page token: token of %s used for %s", state.Kind, kind)
+	}
+
+	return state, nil
+}
+
+// LedgerAccount is an account of the ledger,
+// Frontier: the frontier block of the account,
+// OpenBlock: the open block of the account,
+// RepresentativeBlock: the block which set the representative,
+// Balance: the balance of the account in raw,
+// ModifiedTimestamp: the time of the last block,
+// BlockCount: the count of blocks of the account.
+type LedgerAccount struct {
+	Frontier            string `json:"frontier"`
+	OpenBlock           string `json:"open_block"`
+	RepresentativeBlock string `json:"representative_block"`
+	Balance             string `json:"balance"`
+	ModifiedTimestamp   string `json:"modified_timestamp"`
+	BlockCount          string `json:"block_count"`
+}
+
+// HistoryPage gets a page of the history of a wallet, newest first,
+// address: the wallet address to get the history of,
+// count: the count of blocks per page,
+// token: the token of the page (empty for the first page),
+// returns the page, the token of the next page (empty if there are no more blocks) or an error.
+func (c *Client) HistoryPage(address string, count int, token PageToken) (AccountHistory, PageToken, error) {
+	return c.historyPage(address, count, token, false)
+}
+
+// historyPage gets a page of the history of a wallet like HistoryPage, with every block
+// if raw is true, including the change and epoch blocks, typed by their subtype.
+func (c *Client) historyPage(address string, count int, token PageToken, raw bool) (AccountHistory, PageToken, error) {
+	state, err := token.decode("history")
+
+	if err != nil {
+		return AccountHistory{}, "", err
+	}
+
+	if state.Account != "" && state.Account != address {
+		return AccountHistory{}, "", fmt.Errorf("invalid page token: token of %s used for %s", state.Account, address)
+	}
+
+	data := map[string]any{
+		"action":  "account_history",
+		"account": address,
+		"count":   count,
+	}
+
+	if state.Head != "" {
+		data["head"] = state.Head
+	}
+
+	if raw {
+		data["raw"] = "true"
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return AccountHistory{}, "", err
+	}
+
+	var history AccountHistory
+	json.Unmarshal(res, &history)
+
+	if history.Error != nil {
+		return AccountHistory{}, "", rpcError(history.Error)
+	}
+
+	for i, h := range history.History {
+		if h.Type == "state" && h.Subtype != "" {
+			history.History[i].Type = h.Subtype
+		}
+	}
+
+	if history.Previous == "" {
+		return history, "", nil
+	}
+
+	return history, encodePageToken(pageState{Kind: "history", Account: address, Head: history.Previous}), nil
+}
+
+// historyPager reads the whole history of a wallet newest first, in one request or page by page with LowMemory.
+type historyPager struct {
+	client  *Client
+	address string
+	token   PageToken
+	page    AccountHistory
+	err     error
+	done    bool
+}
+
+func (c *Client) historyPages(address string) *historyPager {
+	return &historyPager{client: c, address: address}
+}
+
+// next reads the next page into page, returns false when the history is read or on error.
+func (p *historyPager) next() bool {
+	if p.done {
+		return false
+	}
+
+	if historyPageSize <= 0 {
+		p.page, p.err = p.client.GetAccountHistory(p.address, -1)
+		p.done = true
+
+		return p.err == nil
+	}
+
+	p.page, p.token, p.err = p.client.HistoryPage(p.address, historyPageSize, p.token)
+	p.done = p.err != nil || p.token == ""
+
+	return p.err == nil
+}
+
+// LedgerPage gets a page of the accounts of the ledger, ordered by public key,
+// count: the count of accounts per page,
+// token: the token of the page (empty for the first page),
+// returns the accounts by wallet address, the token of the next page or an error.
+func (c *Client) LedgerPage(count int, token PageToken) (map[string]LedgerAccount, PageToken, error) {
+	state, err := token.decode("ledger")
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	data := map[string]any{
+		"action": "ledger",
+		"count":  count + 1,
+	}
+
+	if state.Account != "" {
+		data["account"] = state.Account
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	var body struct {
+		Accounts map[string]LedgerAccount `json:"accounts"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return nil, "", rpcError(body.Error)
+	}
+
+	next, err := nextPage(body.Accounts, count)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if next == "" {
+		return body.Accounts, "", nil
+	}
+
+	return body.Accounts, encodePageToken(pageState{Kind: "ledger", Account: next}), nil
+}
+
+// FrontiersPage gets a page of the frontiers of the ledger, ordered by public key,
+// count: the count of accounts per page,
+// token: the token of the page (empty for the first page),
+// returns the frontiers by wallet address, the token of the next page or an error.
+func (c *Client) FrontiersPage(count int, token PageToken) (map[string]string, PageToken, error) {
+	state, err := token.decode("frontiers")
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	start := state.Account
+
+	if start == "" {
+		start = "nano_1111111111111111111111111111111111111111111111111111hifc8npp"
+	}
+
+	data := map[string]any{
+		"action":  "frontiers",
+		"account": start,
+		"count":   count + 1,
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	var body struct {
+		Frontiers map[string]string `json:"frontiers"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return nil, "", rpcError(body.Error)
+	}
+
+	next, err := nextPage(body.Frontiers, count)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if next == "" {
+		return body.Frontiers, "", nil
+	}
+
+	return body.Frontiers, encodePageToken(pageState{Kind: "frontiers", Account: next}), nil
+}
+
+// nextPage removes the extra account fetched beyond count from a page,
+// the base32 alphabet of addresses is ordered, so addresses sort like their public keys,
+// returns the extra account (the start of the next page), an empty string or an error
+// if the node returned an invalid address.
+func nextPage[T any](accounts map[string]T, count int) (string, error) {
+	if len(accounts) <= count {
+		return "", nil
+	}
+
+	addrs := make([]string, 0, len(accounts))
+
+	for a := range accounts {
+		if len(a) < 60 {
+			return "", fmt.Errorf("could not parse address (%s)", a)
+		}
+
+		addrs = append(addrs, a[len(a)-60:])
+	}
+
+	sort.Strings(addrs)
+
+	for a := range accounts {
+		if a[len(a)-60:] == addrs[count] {
+			delete(accounts, a)
+			return a, nil
+		}
+	}
+
+	return "", nil
+}
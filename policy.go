@@ -0,0 +1,101 @@
+package nanogo
+
+import "fmt"
+
+// ControlActions are the RPC actions a node only accepts with enable_control set in its rpcconfig.
+var ControlActions = []string{
+	"account_create",
+	"account_move",
+	"account_remove",
+	"account_representative_set",
+	"accounts_create",
+	"bootstrap",
+	"bootstrap_any",
+	"bootstrap_lazy",
+	"confirmation_height_currently_processing",
+	"keepalive",
+	"ledger",
+	"node_id",
+	"node_id_delete",
+	"password_change",
+	"populate_backlog",
+	"receive",
+	"receive_minimum",
+	"receive_minimum_set",
+	"search_receivable",
+	"search_receivable_all",
+	"send",
+	"stats_clear",
+	"stop",
+	"unchecked_clear",
+	"unopened",
+	"wallet_add",
+	"wallet_add_watch",
+	"wallet_change_seed",
+	"wallet_create",
+	"wallet_destroy",
+	"wallet_lock",
+	"wallet_representative_set",
+	"wallet_work_get",
+	"work_cancel",
+	"work_get",
+	"work_peer_add",
+	"work_peers",
+	"work_peers_clear",
+	"work_set",
+}
+
+// ActionPolicy is the set of RPC actions a client may send, checked before the HTTP call,
+// Allowed: the only permitted actions (empty permits every action),
+// Denied: the actions that are never permitted,
+// DisableControl: true to deny the ControlActions, like a node without enable_control.
+type ActionPolicy struct {
+	Allowed        []string
+	Denied         []string
+	DisableControl bool
+}
+
+// Permits checks if the policy permits an action,
+// action: the RPC action to check,
+// returns true if the action is permitted, false otherwise.
+func (p *ActionPolicy) Permits(action string) bool {
+	if p == nil {
+		return true
+	}
+
+	for _, a := range p.Denied {
+		if a == action {
+			return false
+		}
+	}
+
+	if p.DisableControl {
+		for _, a := range ControlActions {
+			if a == action {
+				return false
+			}
+		}
+	}
+
+	if len(p.Allowed) == 0 {
+		return true
+	}
+
+	for _, a := range p.Allowed {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Client) checkAction(data map[string]any) error {
+	action, _ := data["action"].(string)
+
+	if !c.Actions.Permits(action) {
+		return fmt.Errorf("%w: %s", ErrActionDisabled, action)
+	}
+
+	return nil
+}
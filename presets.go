@@ -0,0 +1,232 @@
+package nanogo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NodePreset is a well-known public node with its quirks,
+// Name: the name of the preset,
+// RPCUrl: the url of the RPC server,
+// WSUrl: the url of the WebSocket server (optional),
+// AuthHeader: the header of the API key, empty if the node takes no key (optional),
+// RequestsPerMinute: the rate limit of the node (optional, unlimited by default),
+// DisabledActions: the RPC actions the node refuses besides the control actions (optional),
+// WorkGenerate: whether the node generates work, LocalWorkProvider is used otherwise.
+type NodePreset struct {
+	Name              string   `json:"name" yaml:"name"`
+	RPCUrl            string   `json:"rpc_url" yaml:"rpc_url"`
+	WSUrl             string   `json:"ws_url" yaml:"ws_url"`
+	AuthHeader        string   `json:"auth_header" yaml:"auth_header"`
+	RequestsPerMinute int      `json:"requests_per_minute" yaml:"requests_per_minute"`
+	DisabledActions   []string `json:"disabled_actions" yaml:"disabled_actions"`
+	WorkGenerate      bool     `json:"work_generate" yaml:"work_generate"`
+}
+
+var (
+	presetsMu sync.RWMutex
+	presets   = map[string]NodePreset{
+		"somenano": {
+			Name:              "somenano",
+			RPCUrl:            "https://node.somenano.com/proxy",
+			WSUrl:             "wss://node.somenano.com/websocket",
+			RequestsPerMinute: 60,
+		},
+		"nanoslo": {
+			Name:              "nanoslo",
+			RPCUrl:            "https://nanoslo.0x.no/proxy",
+			WSUrl:             "wss://nanoslo.0x.no/websocket",
+			RequestsPerMinute: 60,
+		},
+		"rainstorm": {
+			Name:              "rainstorm",
+			RPCUrl:            "https://rainstorm.city/api",
+			WSUrl:             "wss://rainstorm.city/websocket",
+			RequestsPerMinute: 60,
+		},
+		"nanos": {
+			Name:              "nanos",
+			RPCUrl:            "https://proxy.nanos.cc/proxy",
+			WSUrl:             "wss://socket.nanos.cc",
+			RequestsPerMinute: 60,
+			WorkGenerate:      true,
+		},
+		"nanoto": {
+			Name:              "nanoto",
+			RPCUrl:            "https://rpc.nano.to",
+			AuthHeader:        "Authorization",
+			RequestsPerMinute: 30,
+			WorkGenerate:      true,
+		},
+	}
+)
+
+// NodePresets returns the registered public node presets sorted by name,
+// returns the presets.
+func NodePresets() []NodePreset {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+
+	list := make([]NodePreset, 0, len(presets))
+
+	for _, p := range presets {
+		list = append(list, p)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list
+}
+
+// GetNodePreset gets a registered public node preset,
+// name: the name of the preset,
+// returns the preset and true, or false if there is no preset with the name.
+func GetNodePreset(name string) (NodePreset, bool) {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+
+	p, ok := presets[name]
+
+	return p, ok
+}
+
+// RegisterNodePreset registers a node preset, replacing the preset with the same name,
+// e.g. to fix an endpoint that moved or add a private node,
+// preset: the preset,
+// returns an error if the preset has no name or RPC url.
+func RegisterNodePreset(preset NodePreset) error {
+	if preset.Name == "" || preset.RPCUrl == "" {
+		return fmt.Errorf("node preset requires a name and an rpc url")
+	}
+
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+
+	presets[preset.Name] = preset
+
+	return nil
+}
+
+// Policy returns the ActionPolicy denying the actions refused by the node,
+// returns the policy.
+func (p NodePreset) Policy() *ActionPolicy {
+	return &ActionPolicy{Denied: append([]string{}, p.DisabledActions...), DisableControl: true}
+}
+
+// NewClient creates a client of the node, throttled to its rate limit,
+// token: the API key of the node (optional),
+// returns the client.
+func (p NodePreset) NewClient(token string) *Client {
+	client := &Client{Url: p.RPCUrl, Actions: p.Policy()}
+
+	if p.AuthHeader != "" && token != "" {
+		client.AuthHeader = p.AuthHeader
+		client.AuthToken = token
+	}
+
+	if p.RequestsPerMinute > 0 {
+		client.Middlewares = []Middleware{Throttle(p.RequestsPerMinute)}
+	}
+
+	if !p.WorkGenerate {
+		client.Work = LocalWorkProvider{}
+	}
+
+	return client
+}
+
+// NewWSClient creates a WebSocket client of the node,
+// token: the API key of the node (optional),
+// returns the WebSocket client or an error if the node has no WebSocket server.
+func (p NodePreset) NewWSClient(token string) (*WSClient, error) {
+	if p.WSUrl == "" {
+		return nil, fmt.Errorf("node preset %s has no websocket", p.Name)
+	}
+
+	ws := &WSClient{Url: p.WSUrl}
+
+	if p.AuthHeader != "" && token != "" {
+		ws.Header = http.Header{p.AuthHeader: []string{token}}
+	}
+
+	return ws, nil
+}
+
+// NewPresetClient creates a client of public nodes, the first one serving every request
+// and the others used as failover servers of read actions, with the quirks of the first one
+// and the disabled actions of all of them,
+// names: the names of the presets,
+// returns the client or an error if a preset is unknown.
+func NewPresetClient(names ...string) (*Client, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no node preset")
+	}
+
+	var nodes []NodePreset
+
+	for _, name := range names {
+		p, ok := GetNodePreset(name)
+
+		if !ok {
+			return nil, fmt.Errorf("unknown node preset (%s)", name)
+		}
+
+		nodes = append(nodes, p)
+	}
+
+	client := nodes[0].NewClient("")
+
+	for _, p := range nodes[1:] {
+		client.Urls = append(client.Urls, p.RPCUrl)
+		client.Actions.Denied = append(client.Actions.Denied, p.DisabledActions...)
+	}
+
+	return client, nil
+}
+
+// Throttle is a Middleware spacing the RPC requests of a client evenly to a rate limit,
+// requests wait for their turn or fail with the error of their context,
+// perMinute: the maximum count of requests per minute (unlimited if not positive),
+// returns the middleware.
+func Throttle(perMinute int) Middleware {
+	if perMinute <= 0 {
+		return func(h RPCHandler) RPCHandler { return h }
+	}
+
+	interval := time.Minute / time.Duration(perMinute)
+
+	var mu sync.Mutex
+	var next time.Time
+
+	return func(h RPCHandler) RPCHandler {
+		return func(ctx context.Context, data map[string]any) ([]byte, error) {
+			mu.Lock()
+			now := time.Now()
+
+			if next.Before(now) {
+				next = now
+			}
+
+			at := next
+			next = next.Add(interval)
+			mu.Unlock()
+
+			if wait := time.Until(at); wait > 0 {
+				timer := time.NewTimer(wait)
+				defer timer.Stop()
+
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-timer.C:
+				}
+			}
+
+			return h(ctx, data)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package nanogo
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Payout is a payment of a pre-signed batch,
+// Address: the destination wallet address (or a handle resolved with the AliasResolver),
+// Amount: the amount to send in raw.
+type Payout struct {
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+}
+
+// PresignedBatch is a chain of send blocks built, signed and given work ahead of a
+// scheduled payout run on top of a predicted frontier, re-signed automatically when the
+// frontier of the account diverges, the signer is kept for re-signing,
+// Account: the wallet address of the paying account,
+// Base: the predicted state the chain builds on,
+// Payouts: the payouts of the batch,
+// Blocks: the signed blocks of the payouts, in order,
+// Hashes: the hashes of the blocks,
+// Published: the count of blocks already published.
+type PresignedBatch struct {
+	Account   string
+	Base      FrontierState
+	Payouts   []Payout
+	Blocks    []Block
+	Hashes    []string
+	Published int
+
+	client *Client
+	signer Signer
+	mu     sync.Mutex
+}
+
+// PresignBatch builds, signs and generates the work of a batch of sends on top of the
+// current frontier of a wallet, to be published later with Publish,
+// seed: the seed of the paying wallet,
+// index: the index of the paying wallet (usually 0),
+// payouts: the payouts, in the order they are published,
+// returns the batch or an error.
+func (c *Client) PresignBatch(seed string, index int, payouts []Payout) (*PresignedBatch, error) {
+	privKey, err := SeedToPrivateKey(seed, index)
+
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := privateKeyToAddress(privKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.PresignBatchWithSigner(addr, PrivateKeySigner{privKey}, payouts)
+}
+
+// PresignBatchWithSigner pre-signs a batch of sends like PresignBatch with a Signer,
+// account: the wallet address of the paying account,
+// signer: the signer of the blocks of the account,
+// payouts: the payouts, in the order they are published,
+// returns the batch or an error.
+func (c *Client) PresignBatchWithSigner(account string, signer Signer, payouts []Payout) (*PresignedBatch, error) {
+	resolved := make([]Payout, len(payouts))
+
+	for i, p := range payouts {
+		addr, err := c.resolveDestination(p.Address)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.checkCounterparty(addr, Outgoing); err != nil {
+			return nil, err
+		}
+
+		resolved[i] = Payout{Address: addr, Amount: p.Amount}
+	}
+
+	state, err := c.sendState(account)
+
+	if err != nil {
+		return nil, err
+	}
+
+	b := &PresignedBatch{Account: account, Payouts: resolved, client: c, signer: signer}
+
+	if err := b.Resign(state); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Resign rebuilds and re-signs the blocks not published yet on top of a state,
+// state: the state of the account the remaining blocks build on,
+// returns an error.
+func (b *PresignedBatch) Resign(state FrontierState) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.resign(state)
+}
+
+func (b *PresignedBatch) resign(state FrontierState) error {
+	blocks := append([]Block{}, b.Blocks[:b.Published]...)
+	hashes := append([]string{}, b.Hashes[:b.Published]...)
+	next := state
+
+	for _, p := range b.Payouts[b.Published:] {
+		balance, err := BalanceAfterSend(next.Balance, p.Amount)
+
+		if err != nil {
+			return err
+		}
+
+		rcptPubKey, err := AddressToPublicKey(p.Address)
+
+		if err != nil {
+			return err
+		}
+
+		block := Block{
+			Type:           "state",
+			Account:        b.Account,
+			Previous:       next.Frontier,
+			Representative: next.Representative,
+			Balance:        balance,
+			Link:           fmt.Sprintf("%064X", rcptPubKey),
+			LinkAsAccount:  p.Address,
+		}
+
+		if err := b.signer.SignBlock(&block); err != nil {
+			return err
+		}
+
+		work, err := b.client.generateWork(block, "send")
+
+		if err != nil {
+			return err
+		}
+
+		block.AddWork(work)
+
+		hash, err := block.Hash()
+
+		if err != nil {
+			return err
+		}
+
+		blocks = append(blocks, block)
+		hashes = append(hashes, hash)
+		next = FrontierState{Frontier: hash, Balance: balance, Representative: next.Representative}
+	}
+
+	if b.Published == 0 {
+		b.Base = state
+	}
+
+	b.Blocks = blocks
+	b.Hashes = hashes
+
+	return nil
+}
+
+// expected returns the frontier the next block builds on.
+func (b *PresignedBatch) expected() string {
+	if b.Published == 0 {
+		return b.Base.Frontier
+	}
+
+	return b.Hashes[b.Published-1]
+}
+
+// Valid checks the next block of the batch still builds on the frontier of the account,
+// returns true if the batch can be published as signed, false otherwise, or an error.
+func (b *PresignedBatch) Valid() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	frontiers, err := b.client.GetAccountsFrontiers([]string{b.Account})
+
+	if err != nil {
+		return false, err
+	}
+
+	return strings.EqualFold(frontierOf(frontiers, b.Account), b.expected()), nil
+}
+
+// Publish publishes the blocks not published yet in order, skipping the blocks the
+// frontier of the account shows were already accepted, e.g. when a previous call failed
+// after the node accepted a block, and re-signing the others first if the frontier
+// diverged from the predicted one,
+// returns the hashes of the blocks published by the call or an error.
+func (b *PresignedBatch) Publish() ([]string, error) {
+	c := b.client
+
+	defer c.lockAccount(b.Account)()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hashes := []string{}
+
+	if b.Published == len(b.Blocks) {
+		return hashes, nil
+	}
+
+	frontiers, err := c.GetAccountsFrontiers([]string{b.Account})
+
+	if err != nil {
+		return hashes, err
+	}
+
+	frontier := frontierOf(frontiers, b.Account)
+
+	if !strings.EqualFold(frontier, b.expected()) {
+		// blocks accepted by the node whose publish still failed, e.g. on a timeout, or
+		// approved after being held by the ApprovalGate, are skipped, re-signing them
+		// would pay the payouts twice
+		for i := b.Published; i < len(b.Hashes); i++ {
+			if strings.EqualFold(frontier, b.Hashes[i]) {
+				b.Published = i + 1
+				c.cacheAccount(b.Account, FrontierState{
+					Frontier:       b.Hashes[i],
+					Balance:        b.Blocks[i].Balance,
+					Representative: b.Blocks[i].Representative,
+				})
+				break
+			}
+		}
+	}
+
+	if b.Published == len(b.Blocks) {
+		return hashes, nil
+	}
+
+	if !strings.EqualFold(frontier, b.expected()) {
+		state, err := c.sendState(b.Account)
+
+		if err != nil {
+			return hashes, err
+		}
+
+		if err := b.resign(state); err != nil {
+			return hashes, err
+		}
+	}
+
+	for b.Published < len(b.Blocks) {
+		block := b.Blocks[b.Published]
+		p := b.Payouts[b.Published]
+
+		if c.Limits != nil {
+			if err := c.Limits.Allow(p.Address, p.Amount); err != nil {
+				return hashes, err
+			}
+		}
+
+		hash, err := c.publishSigned("send", block, true)
+
+		if err != nil {
+			return hashes, err
+		}
+
+		b.Published++
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, nil
+}
+
+// sendState returns the state of an account from the node to build sends on, the
+// balance at the frontier (not the confirmed balance) so the pair always matches.
+func (c *Client) sendState(address string) (FrontierState, error) {
+	info, err := c.GetAccountInfo(address)
+
+	if err != nil {
+		return FrontierState{}, err
+	}
+
+	return FrontierState{
+		Frontier:       info.Frontier,
+		Balance:        info.Balance,
+		Representative: info.Representative,
+	}, nil
+}
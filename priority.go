@@ -0,0 +1,83 @@
+package nanogo
+
+import (
+	"encoding/json"
+)
+
+// Priority hints of ProcessOptions, no released node supports them yet.
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+// ProcessOptions is the node hints of a process request, for bulk background payouts to
+// deprioritize themselves versus interactive transactions, nodes ignore the hints they
+// don't support,
+// Async: return as soon as the node queued the block, without waiting for the result,
+// ignored in the ProcessHints of a client,
+// DisableWatchWork: ask the node not to regenerate the work of the block when its election stalls (watch_work=false),
+// Priority: the priority of the block (PriorityLow, PriorityNormal or PriorityHigh, optional), sent as the
+// priority field for nodes supporting it, a no-op with the released nodes, which have no such option,
+// Hints: additional node specific fields of the request (optional).
+type ProcessOptions struct {
+	Async            bool
+	DisableWatchWork bool
+	Priority         string         // optional
+	Hints            map[string]any // optional
+}
+
+// ProcessWithOptions processes a block with node hints,
+// subtype: the subtype of the block,
+// block: the block to process,
+// options: the hints of the request,
+// returns the block hash or an error.
+func (c *Client) ProcessWithOptions(subtype string, block Block, options ProcessOptions) (string, error) {
+	data := map[string]any{}
+
+	for k, v := range options.Hints {
+		data[k] = v
+	}
+
+	data["action"] = "process"
+	data["subtype"] = subtype
+	data["json_block"] = "true"
+	data["block"] = block
+
+	if options.Async {
+		data["async"] = "true"
+	}
+
+	if options.DisableWatchWork {
+		data["watch_work"] = "false"
+	}
+
+	if options.Priority != "" {
+		data["priority"] = options.Priority
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Hash    string `json:"hash"`
+		Started string `json:"started"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return "", rpcError(body.Error)
+	}
+
+	if body.Hash == "" && options.Async {
+		// async requests only acknowledge the block
+		return block.Hash()
+	}
+
+	return body.Hash, nil
+}
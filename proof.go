@@ -0,0 +1,187 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Quorum is the confirmation quorum of the node,
+// QuorumDelta: the weight needed to confirm a block in raw,
+// OnlineWeightQuorumPercent: the percentage of the online weight needed for the quorum,
+// OnlineStakeTotal: the online voting weight in raw,
+// PeersStakeTotal: the voting weight of the peers in raw,
+// TrendedStakeTotal: the trended online voting weight in raw,
+// Error: the error of the request.
+type Quorum struct {
+	QuorumDelta               string `json:"quorum_delta"`
+	OnlineWeightQuorumPercent string `json:"online_weight_quorum_percent"`
+	OnlineStakeTotal          string `json:"online_stake_total"`
+	PeersStakeTotal           string `json:"peers_stake_total"`
+	TrendedStakeTotal         string `json:"trended_stake_total"`
+
+	Error any `json:"error"`
+}
+
+// SendProof is a portable proof that a send was confirmed, verifiable offline,
+// Hash: the hash of the send block,
+// Block: the send block,
+// Previous: the previous block of the account (proving the amount),
+// Amount: the amount sent in raw,
+// Destination: the destination wallet address,
+// Confirmed: whether the node reported the block as confirmed,
+// Height: the height of the block in the account chain,
+// Quorum: the confirmation quorum of the node at the time of the query,
+// QueriedAt: the time of the query.
+type SendProof struct {
+	Hash        string    `json:"hash"`
+	Block       Block     `json:"block"`
+	Previous    Block     `json:"previous"`
+	Amount      string    `json:"amount"`
+	Destination string    `json:"destination"`
+	Confirmed   bool      `json:"confirmed"`
+	Height      string    `json:"height"`
+	Quorum      Quorum    `json:"quorum"`
+	QueriedAt   time.Time `json:"queried_at"`
+}
+
+// GetConfirmationQuorum gets the confirmation quorum of the node,
+// returns the quorum or an error.
+func (c *Client) GetConfirmationQuorum() (Quorum, error) {
+	data := map[string]any{
+		"action": "confirmation_quorum",
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return Quorum{}, err
+	}
+
+	var quorum Quorum
+	json.Unmarshal(res, &quorum)
+
+	if quorum.Error != nil {
+		return Quorum{}, rpcError(quorum.Error)
+	}
+
+	return quorum, nil
+}
+
+// ProveSend builds a proof bundle of a confirmed state send block,
+// hash: the hash of the send block,
+// returns the proof, an error wrapping ErrInvalidProof if the block is not a confirmed send, or an error.
+func (c *Client) ProveSend(hash string) (SendProof, error) {
+	infos, err := c.GetBlocksInfo([]string{hash})
+
+	if err != nil {
+		return SendProof{}, err
+	}
+
+	var info BlockInfo
+
+	for _, i := range infos {
+		info = i
+	}
+
+	if info.Subtype != "send" {
+		return SendProof{}, fmt.Errorf("%w: block is not a send (%s)", ErrInvalidProof, info.Subtype)
+	}
+
+	if info.Confirmed != "true" {
+		return SendProof{}, fmt.Errorf("%w: block is not confirmed", ErrInvalidProof)
+	}
+
+	prev, err := c.GetBlockInfo(info.Contents.Previous)
+
+	if err != nil {
+		return SendProof{}, err
+	}
+
+	quorum, err := c.GetConfirmationQuorum()
+
+	if err != nil {
+		return SendProof{}, err
+	}
+
+	quorum.Error = nil
+
+	proof := SendProof{
+		Hash:        strings.ToUpper(hash),
+		Block:       info.Contents,
+		Previous:    prev.Contents,
+		Amount:      info.Amount,
+		Destination: info.Contents.LinkAsAccount,
+		Confirmed:   true,
+		Height:      info.Height,
+		Quorum:      quorum,
+		QueriedAt:   time.Now().UTC(),
+	}
+
+	return proof, proof.Verify()
+}
+
+// Verify checks the proof offline: the hashes and signatures of the blocks, that the
+// previous block precedes the send, and the amount and destination,
+// returns an error wrapping ErrInvalidProof if the proof is invalid.
+func (p SendProof) Verify() error {
+	invalid := func(format string, a ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidProof, fmt.Sprintf(format, a...))
+	}
+
+	hash, err := p.Block.Hash()
+
+	if err != nil || !strings.EqualFold(hash, p.Hash) {
+		return invalid("hash of the block does not match")
+	}
+
+	if ok, err := p.Block.VerifySignature(); err != nil || !ok {
+		return invalid("invalid signature of the block")
+	}
+
+	prevHash, err := p.Previous.Hash()
+
+	if err != nil || !strings.EqualFold(prevHash, p.Block.Previous) {
+		return invalid("previous block does not precede the block")
+	}
+
+	if ok, err := p.Previous.VerifySignature(); err != nil || !ok {
+		return invalid("invalid signature of the previous block")
+	}
+
+	if canonicalAddress(p.Previous.Account) != canonicalAddress(p.Block.Account) {
+		return invalid("previous block is of another account")
+	}
+
+	bal, ok := new(big.Int).SetString(p.Block.Balance, 10)
+	prevBal, ok2 := new(big.Int).SetString(p.Previous.Balance, 10)
+	amount, ok3 := new(big.Int).SetString(p.Amount, 10)
+
+	if !ok || !ok2 || !ok3 {
+		return invalid("could not parse the balances")
+	}
+
+	if new(big.Int).Sub(prevBal, bal).Cmp(amount) != 0 || amount.Sign() <= 0 {
+		return invalid("amount does not match the balances")
+	}
+
+	link, err := decodeHash(p.Block.Link)
+
+	if err != nil {
+		return invalid("could not decode the link")
+	}
+
+	dest, err := PublicKeyToAddress([32]byte(link))
+
+	if err != nil || canonicalAddress(dest) != canonicalAddress(p.Destination) {
+		return invalid("destination does not match the link")
+	}
+
+	if !p.Confirmed {
+		return invalid("block is not confirmed")
+	}
+
+	return nil
+}
@@ -0,0 +1,506 @@
+package nanogo
+
+import (
+	"encoding"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protowire"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The events without a generated protobuf type are encoded as protobuf messages
+// described by the protobuf tags of their fields, so any protobuf library can decode
+// them with the schema returned by ProtobufSchema:
+//   - every exported field has a protobuf:"N" tag with its field number or protobuf:"-"
+//     to skip it, a missing tag is an error so the numbers never depend on the order
+//     of the fields and new fields never renumber the existing ones,
+//   - the fields keep their json names and embedded structs are nested messages,
+//   - amounts and the other types implementing encoding.TextMarshaler (e.g. Amount,
+//     time.Time) are strings of their text, so big amounts keep their precision,
+//   - integers are int64 and uint64, floats are double,
+//   - structs are nested messages, slices of strings and structs are repeated fields,
+//   - slices of events are wrapped in a message with the slice as field 1.
+
+// protoField is a field of the message of a struct type.
+type protoField struct {
+	name     string
+	goName   string
+	number   protowire.Number
+	index    int
+	typ      reflect.Type
+	repeated bool
+}
+
+// protoMessage is the message of a struct type.
+type protoMessage struct {
+	fields  []protoField
+	numbers map[protowire.Number]int
+	err     error
+}
+
+var (
+	protoMessages sync.Map // reflect.Type -> *protoMessage
+
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// protoMessageOf returns the message of a struct type,
+// returns an error if a field has no protobuf tag, a duplicate number or an unsupported type.
+func protoMessageOf(t reflect.Type) (*protoMessage, error) {
+	if m, ok := protoMessages.Load(t); ok {
+		return m.(*protoMessage), m.(*protoMessage).err
+	}
+
+	m := &protoMessage{numbers: map[protowire.Number]int{}}
+
+	for i := 0; i < t.NumField() && m.err == nil; i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("protobuf")
+
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+
+		n, err := strconv.Atoi(tag)
+		_, dup := m.numbers[protowire.Number(n)]
+
+		switch {
+		case !ok:
+			m.err = fmt.Errorf("%s.%s has no protobuf tag", t, f.Name)
+		case err != nil || n < 1 || n > int(protowire.MaxValidNumber):
+			m.err = fmt.Errorf("%s.%s has the invalid protobuf tag %q", t, f.Name, tag)
+		case dup:
+			m.err = fmt.Errorf("%s.%s reuses the protobuf field number %d", t, f.Name, n)
+		case !isProtoType(f.Type, false):
+			m.err = fmt.Errorf("%s.%s has the unsupported type %s", t, f.Name, f.Type)
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+
+		if name == "" || name == "-" {
+			name = f.Name
+		}
+
+		m.numbers[protowire.Number(n)] = len(m.fields)
+		m.fields = append(m.fields, protoField{
+			name:     name,
+			goName:   f.Name,
+			number:   protowire.Number(n),
+			index:    i,
+			typ:      f.Type,
+			repeated: f.Type.Kind() == reflect.Slice && !f.Type.Implements(textMarshalerType),
+		})
+	}
+
+	protoMessages.Store(t, m)
+
+	return m, m.err
+}
+
+// isProtoType reports whether a type can be encoded: strings, booleans, integers,
+// doubles, text marshalers, structs and pointers to them, and slices of strings, text
+// marshalers and structs (other encoders pack repeated numbers).
+func isProtoType(t reflect.Type, repeated bool) bool {
+	if t.Implements(textMarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return t.Elem().Kind() == reflect.Struct
+	case reflect.Slice:
+		return !repeated && isProtoType(t.Elem(), true)
+	case reflect.String, reflect.Struct:
+		return true
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float64:
+		return !repeated
+	}
+
+	return false
+}
+
+// marshalProto encodes a struct as its message and a slice as a message wrapping it.
+func marshalProto(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch {
+	case rv.Kind() == reflect.Struct:
+		return appendProtoMessage(nil, rv)
+	case rv.Kind() == reflect.Slice && isProtoType(rv.Type(), false):
+		var b []byte
+		var err error
+
+		for i := 0; i < rv.Len() && err == nil; i++ {
+			b, err = appendProtoValue(b, 1, rv.Index(i))
+		}
+
+		return b, err
+	}
+
+	return nil, fmt.Errorf("%T is no struct or slice", v)
+}
+
+// appendProtoMessage appends the fields of a struct, omitting the zero values like proto3.
+func appendProtoMessage(b []byte, v reflect.Value) ([]byte, error) {
+	m, err := protoMessageOf(v.Type())
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range m.fields {
+		fv := v.Field(f.index)
+
+		if fv.IsZero() {
+			continue
+		}
+
+		if !f.repeated {
+			b, err = appendProtoValue(b, f.number, fv)
+		}
+
+		for i := 0; f.repeated && i < fv.Len() && err == nil; i++ {
+			b, err = appendProtoValue(b, f.number, fv.Index(i))
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("could not encode %s: %w", f.name, err)
+		}
+	}
+
+	return b, nil
+}
+
+func appendProtoValue(b []byte, num protowire.Number, v reflect.Value) ([]byte, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			return protowire.AppendBytes(b, nil), nil
+		}
+
+		v = v.Elem()
+	}
+
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		return protowire.AppendBytes(b, text), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		return protowire.AppendString(b, v.String()), nil
+	case reflect.Bool:
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeBool(v.Bool())), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		return protowire.AppendVarint(b, uint64(v.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		return protowire.AppendVarint(b, v.Uint()), nil
+	case reflect.Float64:
+		b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+		return protowire.AppendFixed64(b, math.Float64bits(v.Float())), nil
+	case reflect.Struct:
+		inner, err := appendProtoMessage(nil, v)
+
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		return protowire.AppendBytes(b, inner), nil
+	}
+
+	return nil, fmt.Errorf("could not encode %s as protobuf", v.Type())
+}
+
+// unmarshalProto decodes a message into a pointer to a struct or a slice.
+func unmarshalProto(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("could not decode protobuf into %T", v)
+	}
+
+	rv = rv.Elem()
+
+	switch {
+	case rv.Kind() == reflect.Struct:
+		return decodeProtoMessage(data, rv)
+	case rv.Kind() == reflect.Slice && isProtoType(rv.Type(), false):
+		return rangeProtoFields(data, func(num protowire.Number, typ protowire.Type, x uint64, b []byte) error {
+			if num != 1 {
+				return nil
+			}
+
+			return appendProtoElem(rv, typ, x, b)
+		})
+	}
+
+	return fmt.Errorf("could not decode protobuf into %T", v)
+}
+
+// rangeProtoFields calls fn with the number, wire type and value of every field of a
+// message, x being the value of numeric fields and b the bytes of length-delimited ones.
+func rangeProtoFields(data []byte, fn func(num protowire.Number, typ protowire.Type, x uint64, b []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+
+		data = data[n:]
+		var x uint64
+		var b []byte
+
+		switch typ {
+		case protowire.VarintType:
+			x, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed64Type:
+			x, n = protowire.ConsumeFixed64(data)
+		case protowire.BytesType:
+			b, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+
+		data = data[n:]
+
+		if err := fn(num, typ, x, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeProtoMessage decodes the fields of a message into a struct, skipping unknown numbers.
+func decodeProtoMessage(data []byte, v reflect.Value) error {
+	m, err := protoMessageOf(v.Type())
+
+	if err != nil {
+		return err
+	}
+
+	return rangeProtoFields(data, func(num protowire.Number, typ protowire.Type, x uint64, b []byte) error {
+		i, ok := m.numbers[num]
+
+		if !ok {
+			return nil
+		}
+
+		f := m.fields[i]
+		var err error
+
+		if f.repeated {
+			err = appendProtoElem(v.Field(f.index), typ, x, b)
+		} else {
+			err = decodeProtoValue(v.Field(f.index), typ, x, b)
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not decode %s: %w", f.name, err)
+		}
+
+		return nil
+	})
+}
+
+// appendProtoElem decodes an element of a repeated field and appends it to a slice.
+func appendProtoElem(v reflect.Value, typ protowire.Type, x uint64, b []byte) error {
+	e := reflect.New(v.Type().Elem()).Elem()
+
+	if err := decodeProtoValue(e, typ, x, b); err != nil {
+		return err
+	}
+
+	v.Set(reflect.Append(v, e))
+
+	return nil
+}
+
+func decodeProtoValue(v reflect.Value, typ protowire.Type, x uint64, b []byte) error {
+	t := v.Type()
+
+	if t.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+
+		return decodeProtoValue(v.Elem(), typ, x, b)
+	}
+
+	want := protowire.BytesType
+	text := t.Implements(textMarshalerType)
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !text {
+			want = protowire.VarintType
+		}
+	case reflect.Float64:
+		if !text {
+			want = protowire.Fixed64Type
+		}
+	}
+
+	if typ != want {
+		return fmt.Errorf("wrong wire type for %s", t)
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok && text {
+		return u.UnmarshalText(b)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(string(b))
+	case reflect.Struct:
+		return decodeProtoMessage(b, v)
+	case reflect.Bool:
+		v.SetBool(x != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(x))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(x)
+	case reflect.Float64:
+		v.SetFloat(math.Float64frombits(x))
+	default:
+		return fmt.Errorf("could not decode protobuf into %s", t)
+	}
+
+	return nil
+}
+
+// ProtobufSchema returns the proto3 schema of the messages ProtobufCodec encodes a
+// value as, e.g. to decode persisted events in other languages,
+// v: a value of the type, e.g. ConfirmationMessage{},
+// returns the schema or an error if the type has fields protobuf cannot encode.
+func ProtobufSchema(v any) (string, error) {
+	t := reflect.TypeOf(v)
+
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	s := &protoSchema{names: map[reflect.Type]string{}}
+
+	switch {
+	case t == nil:
+		return "", fmt.Errorf("could not describe nil")
+	case t.Kind() == reflect.Struct:
+		if _, err := s.message(t, t.Name()); err != nil {
+			return "", err
+		}
+	case t.Kind() == reflect.Slice && isProtoType(t, false):
+		elem, err := s.typeName(t.Elem(), "Item")
+
+		if err != nil {
+			return "", err
+		}
+
+		s.sb.WriteString("\nmessage Value {\n  repeated " + elem + " value = 1;\n}\n")
+	default:
+		return "", fmt.Errorf("could not describe %s", t)
+	}
+
+	return "syntax = \"proto3\";\n" + s.sb.String(), nil
+}
+
+// protoSchema collects the message definitions of a schema.
+type protoSchema struct {
+	sb    strings.Builder
+	names map[reflect.Type]string
+}
+
+// message defines the message of a struct type once, anonymous structs being named
+// after their field,
+// returns the name of the message.
+func (s *protoSchema) message(t reflect.Type, name string) (string, error) {
+	if existing, ok := s.names[t]; ok {
+		return existing, nil
+	}
+
+	if t.Name() != "" {
+		name = t.Name()
+	}
+
+	m, err := protoMessageOf(t)
+
+	if err != nil {
+		return "", err
+	}
+
+	s.names[t] = name
+	var lines []string
+
+	for _, f := range m.fields {
+		ft, label := f.typ, ""
+
+		if f.repeated {
+			ft, label = f.typ.Elem(), "repeated "
+		}
+
+		typ, err := s.typeName(ft, name+f.goName)
+
+		if err != nil {
+			return "", err
+		}
+
+		lines = append(lines, fmt.Sprintf("  %s%s %s = %d;\n", label, typ, f.name, f.number))
+	}
+
+	s.sb.WriteString("\nmessage " + name + " {\n" + strings.Join(lines, "") + "}\n")
+
+	return name, nil
+}
+
+// typeName returns the protobuf type of a single value, defining the messages it needs.
+func (s *protoSchema) typeName(t reflect.Type, name string) (string, error) {
+	if t.Kind() == reflect.Pointer && !t.Implements(textMarshalerType) {
+		t = t.Elem()
+	}
+
+	if t.Implements(textMarshalerType) {
+		return "string", nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string", nil
+	case reflect.Bool:
+		return "bool", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int64", nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint64", nil
+	case reflect.Float64:
+		return "double", nil
+	case reflect.Struct:
+		return s.message(t, name)
+	}
+
+	return "", fmt.Errorf("could not describe %s", t)
+}
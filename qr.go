@@ -0,0 +1,131 @@
+package nanogo
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var addressPattern = regexp.MustCompile(`(?:nano|xrb)_[13][13456789abcdefghijkmnopqrstuwxyz]{59}`)
+
+// PaymentURI is a payment request of a nano: URI, as encoded in QR codes,
+// Address: the destination wallet address,
+// Amount: the requested amount in raw (zero if not requested),
+// Label: the label of the destination (optional),
+// Message: the message of the payment (optional).
+type PaymentURI struct {
+	Address Address
+	Amount  Raw
+	Label   string // optional
+	Message string // optional
+}
+
+// String returns the nano: URI of the payment request.
+func (p PaymentURI) String() string {
+	query := url.Values{}
+
+	if !p.Amount.IsZero() {
+		query.Set("amount", p.Amount.String())
+	}
+
+	if p.Label != "" {
+		query.Set("label", p.Label)
+	}
+
+	if p.Message != "" {
+		query.Set("message", p.Message)
+	}
+
+	if len(query) == 0 {
+		return "nano:" + p.Address.String()
+	}
+
+	return "nano:" + p.Address.String() + "?" + query.Encode()
+}
+
+// ParseQR extracts and validates the address of the text decoded from a QR code, either
+// a bare address or a nano: URI, tolerating surrounding whitespace and URL wrappers such
+// as https://example.com/pay?uri=nano:...,
+// text: the decoded QR text,
+// returns the payment request or an error wrapping ErrInvalidPaymentURI.
+func ParseQR(text string) (PaymentURI, error) {
+	text = strings.Trim(strings.TrimSpace(text), `"'<>`)
+
+	if u, err := url.Parse(text); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		for _, values := range u.Query() {
+			for _, v := range values {
+				if p, err := parsePaymentURI(v); err == nil {
+					return p, nil
+				}
+			}
+		}
+	}
+
+	lower := strings.ToLower(text)
+
+	for _, scheme := range []string{"nano:", "xrb:"} {
+		if i := strings.Index(lower, scheme); i >= 0 {
+			uri := text[i:]
+
+			if end := strings.IndexAny(uri, " \t\r\n"); end >= 0 {
+				uri = uri[:end]
+			}
+
+			return parsePaymentURI(uri)
+		}
+	}
+
+	matches := addressPattern.FindAllString(lower, -1)
+
+	if len(matches) == 0 {
+		return PaymentURI{}, fmt.Errorf("%w: no address in %q", ErrInvalidPaymentURI, text)
+	}
+
+	for _, m := range matches[1:] {
+		if canonicalAddress(m) != canonicalAddress(matches[0]) {
+			return PaymentURI{}, fmt.Errorf("%w: multiple addresses in %q", ErrInvalidPaymentURI, text)
+		}
+	}
+
+	address, err := ParseAddress(matches[0])
+
+	if err != nil {
+		return PaymentURI{}, fmt.Errorf("%w: %v", ErrInvalidPaymentURI, err)
+	}
+
+	return PaymentURI{Address: address}, nil
+}
+
+// parsePaymentURI parses a nano: (or legacy xrb:) URI.
+func parsePaymentURI(uri string) (PaymentURI, error) {
+	scheme, rest, ok := strings.Cut(uri, ":")
+
+	if !ok || (!strings.EqualFold(scheme, "nano") && !strings.EqualFold(scheme, "xrb")) {
+		return PaymentURI{}, fmt.Errorf("%w: %q is not a nano: uri", ErrInvalidPaymentURI, uri)
+	}
+
+	rest = strings.TrimPrefix(rest, "//")
+	addr, rawQuery, _ := strings.Cut(rest, "?")
+	address, err := ParseAddress(strings.ToLower(strings.TrimSuffix(addr, "/")))
+
+	if err != nil {
+		return PaymentURI{}, fmt.Errorf("%w: %v", ErrInvalidPaymentURI, err)
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+
+	if err != nil {
+		return PaymentURI{}, fmt.Errorf("%w: %v", ErrInvalidPaymentURI, err)
+	}
+
+	p := PaymentURI{Address: address, Label: query.Get("label"), Message: query.Get("message")}
+
+	if amount := query.Get("amount"); amount != "" {
+		if p.Amount, err = ParseAmount(amount); err != nil {
+			return PaymentURI{}, fmt.Errorf("%w: %v", ErrInvalidPaymentURI, err)
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,320 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReceiveOptions is the options of ReceiveAllWithOptions,
+// Confirm: wait for the confirmation of every receive block (optional),
+// ConfirmTimeout: the maximum time to wait for a confirmation (default 1 minute),
+// PollInterval: the interval between confirmation checks (default 1 second),
+// Threshold: the minimum amount of the received blocks in raw (optional),
+// Limit: the maximum count of blocks to receive, the others stay receivable (optional).
+type ReceiveOptions struct {
+	Confirm        bool
+	ConfirmTimeout time.Duration
+	PollInterval   time.Duration
+	Threshold      string
+	Limit          int
+}
+
+// ReceiveFailure is a receivable block that could not be received,
+// Hash: the hash of the receivable block,
+// Source: the source wallet address,
+// Amount: the amount of the block in raw,
+// Block: the hash of the receive block if it was published,
+// Err: the error of the receive.
+type ReceiveFailure struct {
+	Hash   string
+	Source string
+	Amount string
+	Block  string
+	Err    error
+}
+
+// ReceiveResult is the result of ReceiveAllWithOptions,
+// Hashes: the hashes of the received (and confirmed if requested) blocks,
+// Failed: the blocks that could not be received,
+// Skipped: the hashes of the blocks left receivable for denied counterparties,
+// Deferred: the hashes of the blocks left receivable past the limit.
+type ReceiveResult struct {
+	Hashes   []string
+	Failed   []ReceiveFailure
+	Skipped  []string
+	Deferred []string
+}
+
+// Err returns the errors of the failed blocks joined, or nil if no block failed.
+func (r ReceiveResult) Err() error {
+	errs := make([]error, len(r.Failed))
+
+	for i, f := range r.Failed {
+		errs[i] = fmt.Errorf("%s: %w", f.Hash, f.Err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ReceiveAllWithOptions receives all receivable blocks of a wallet, chaining the receive
+// blocks off the locally tracked frontier and continuing past failed blocks,
+// seed: the seed of the receiving wallet,
+// index: the index of the receiving wallet (usually 0),
+// options: the confirmation and threshold options,
+// returns the result or an error if the receivable blocks could not be listed.
+func (c *Client) ReceiveAllWithOptions(seed string, index int, options ReceiveOptions) (ReceiveResult, error) {
+	privKey, err := SeedToPrivateKey(seed, index)
+
+	if err != nil {
+		return ReceiveResult{}, err
+	}
+
+	return c.receiveAllKey(privKey, options, nil)
+}
+
+// receiveAllKey receives the receivable blocks of an account, calling received (optional)
+// with every received block and the hash of its receive block.
+func (c *Client) receiveAllKey(privKey [32]byte, options ReceiveOptions, received func(b ReceiveFailure, block string)) (ReceiveResult, error) {
+	addr, err := privateKeyToAddress(privKey)
+
+	if err != nil {
+		return ReceiveResult{}, err
+	}
+
+	defer c.lockAccount(addr)()
+
+	threshold, err := c.receiveThreshold(options.Threshold)
+
+	if err != nil {
+		return ReceiveResult{}, err
+	}
+
+	listOptions := ReceivableOptions{}
+
+	if !threshold.IsZero() {
+		listOptions.Threshold = threshold.String()
+	}
+
+	receivable, err := c.GetReceivableWithOptions(addr, listOptions)
+
+	if err != nil {
+		return ReceiveResult{}, err
+	}
+
+	hashes := make([]string, 0, len(receivable.Blocks))
+
+	for h, b := range receivable.Blocks {
+		// nodes without threshold support list every block
+		if amount, err := ParseAmount(b.Amount); err == nil && amount.Cmp(threshold) < 0 {
+			continue
+		}
+
+		hashes = append(hashes, h)
+	}
+
+	sort.Strings(hashes)
+
+	var result ReceiveResult
+	var state FrontierState
+	known := false
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	attempted := 0
+
+	for _, h := range hashes {
+		if c.Context().Err() != nil {
+			break
+		}
+
+		b := receivable.Blocks[h]
+		failure := ReceiveFailure{Hash: h, Source: b.Source, Amount: b.Amount}
+
+		if err := c.checkCounterparty(b.Source, Incoming); err != nil {
+			if errors.Is(err, ErrCounterpartyDenied) {
+				result.Skipped = append(result.Skipped, h)
+				continue
+			}
+
+			failure.Err = err
+			result.Failed = append(result.Failed, failure)
+			continue
+		}
+
+		if options.Limit > 0 && attempted >= options.Limit {
+			result.Deferred = append(result.Deferred, h)
+			continue
+		}
+
+		attempted++
+
+		if !known {
+			state, err = c.receiveState(addr)
+
+			if err != nil {
+				failure.Err = err
+				result.Failed = append(result.Failed, failure)
+				continue
+			}
+
+			known = true
+		}
+
+		hash, next, err := c.receive(privKey, addr, state, h, b.Source, b.Amount)
+
+		if err != nil {
+			// the frontier may have moved, re-read it before the next block
+			known = false
+			failure.Err = err
+			result.Failed = append(result.Failed, failure)
+			continue
+		}
+
+		state = next
+
+		if !options.Confirm {
+			result.Hashes = append(result.Hashes, hash)
+
+			if received != nil {
+				received(failure, hash)
+			}
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(hash string, failure ReceiveFailure) {
+			defer wg.Done()
+
+			err := c.WaitConfirmed(hash, options.ConfirmTimeout, options.PollInterval)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				failure.Block = hash
+				failure.Err = err
+				result.Failed = append(result.Failed, failure)
+				return
+			}
+
+			result.Hashes = append(result.Hashes, hash)
+
+			if received != nil {
+				received(failure, hash)
+			}
+		}(hash, failure)
+	}
+
+	wg.Wait()
+
+	return result, c.Context().Err()
+}
+
+// receiveThreshold returns the larger of a threshold and the ReceiveMinimum of the client.
+func (c *Client) receiveThreshold(threshold string) (Raw, error) {
+	t := Raw{}
+
+	if threshold != "" {
+		var err error
+
+		if t, err = ParseAmount(threshold); err != nil {
+			return Raw{}, err
+		}
+	}
+
+	if c.ReceiveMinimum.Cmp(t) > 0 {
+		return c.ReceiveMinimum, nil
+	}
+
+	return t, nil
+}
+
+// WaitConfirmed requests the confirmation of a block with block_confirm and polls
+// block_info until the block is confirmed, or until it is among the RecentConfirmations of the client,
+// hash: the hash of the block,
+// timeout: the maximum time to wait (default 1 minute),
+// interval: the interval between checks (default 1 second),
+// returns ErrNotConfirmed if the block was not confirmed in time, or an error.
+func (c *Client) WaitConfirmed(hash string, timeout, interval time.Duration) error {
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	if c.Recent.WasRecentlyConfirmed(hash) {
+		return nil
+	}
+
+	if err := c.ConfirmBlock(hash); err != nil {
+		return err
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if c.Recent.WasRecentlyConfirmed(hash) {
+			return nil
+		}
+
+		// not cached as unknown, the block is expected to appear
+		info, err := c.blockInfo(hash, false)
+
+		if err != nil && !errors.Is(err, ErrBlockNotFound) {
+			return err
+		}
+
+		if err == nil && info.Confirmed == "true" {
+			return nil
+		}
+
+		select {
+		case <-c.Context().Done():
+			return c.Context().Err()
+		case <-deadline.C:
+			return fmt.Errorf("%w: %s", ErrNotConfirmed, hash)
+		case <-ticker.C:
+		}
+	}
+}
+
+// ConfirmBlock requests the confirmation of a block with block_confirm,
+// hash: the hash of the block,
+// returns an error.
+func (c *Client) ConfirmBlock(hash string) error {
+	data := map[string]any{
+		"action": "block_confirm",
+		"hash":   hash,
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Started string `json:"started"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return rpcError(body.Error)
+	}
+
+	return nil
+}
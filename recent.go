@@ -0,0 +1,163 @@
+package nanogo
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// RecentConfirmations is a size-bounded ring buffer of the blocks recently confirmed,
+// fed with the confirmations observed over WebSocket, so hot paths can check a
+// confirmation without a block_info request, WaitConfirmed consults it when set on the client,
+// a nil RecentConfirmations remembers nothing,
+// Size: the maximum count of remembered confirmations, the oldest are forgotten first (default 4096, nothing is remembered with LowMemory).
+type RecentConfirmations struct {
+	Size int
+
+	mu       sync.Mutex
+	ring     []ConfirmationMessage
+	next     int
+	hashes   map[string]int
+	accounts map[string]int
+}
+
+// Observe remembers a confirmation,
+// m: the confirmation.
+func (r *RecentConfirmations) Observe(m ConfirmationMessage) {
+	if r == nil || !caching {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hash := strings.ToUpper(m.Hash)
+
+	if _, ok := r.hashes[hash]; ok {
+		return
+	}
+
+	if r.ring == nil {
+		size := r.Size
+
+		if size <= 0 {
+			size = defaultRecentSize
+		}
+
+		r.ring = make([]ConfirmationMessage, 0, size)
+		r.hashes = map[string]int{}
+		r.accounts = map[string]int{}
+	}
+
+	if len(r.ring) < cap(r.ring) {
+		r.ring = append(r.ring, m)
+	} else {
+		r.forget(r.ring[r.next])
+		r.ring[r.next] = m
+	}
+
+	r.hashes[hash] = r.next
+	r.accounts[canonicalAddress(m.Account)]++
+	r.next = (r.next + 1) % cap(r.ring)
+}
+
+func (r *RecentConfirmations) forget(m ConfirmationMessage) {
+	delete(r.hashes, strings.ToUpper(m.Hash))
+
+	account := canonicalAddress(m.Account)
+	r.accounts[account]--
+
+	if r.accounts[account] <= 0 {
+		delete(r.accounts, account)
+	}
+}
+
+// Track remembers the confirmations of a channel and forwards them, e.g. the
+// confirmations of a WSClient or an AccountWatcher,
+// ctx: the context stopping the tracking,
+// in: the confirmations,
+// returns the channel of the forwarded confirmations, closed when the context is done or in is closed.
+func (r *RecentConfirmations) Track(ctx context.Context, in <-chan ConfirmationMessage) <-chan ConfirmationMessage {
+	out := make(chan ConfirmationMessage)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case m, ok := <-in:
+				if !ok {
+					return
+				}
+
+				r.Observe(m)
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- m:
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// WasRecentlyConfirmed checks if a block is among the recent confirmations,
+// a false result means the block is unknown, not unconfirmed,
+// hash: the hash of the block,
+// returns true if the block was confirmed recently, false otherwise.
+func (r *RecentConfirmations) WasRecentlyConfirmed(hash string) bool {
+	_, ok := r.Confirmation(hash)
+
+	return ok
+}
+
+// Confirmation gets a recent confirmation,
+// hash: the hash of the block,
+// returns the confirmation and true, or false if the block is not among the recent confirmations.
+func (r *RecentConfirmations) Confirmation(hash string) (ConfirmationMessage, bool) {
+	if r == nil {
+		return ConfirmationMessage{}, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.hashes[strings.ToUpper(hash)]
+
+	if !ok {
+		return ConfirmationMessage{}, false
+	}
+
+	return r.ring[i], true
+}
+
+// AccountRecentlyConfirmed checks if a block of a wallet is among the recent confirmations,
+// address: the wallet address,
+// returns true if a block of the wallet was confirmed recently, false otherwise.
+func (r *RecentConfirmations) AccountRecentlyConfirmed(address string) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.accounts[canonicalAddress(address)] > 0
+}
+
+// Len returns the count of remembered confirmations.
+func (r *RecentConfirmations) Len() int {
+	if r == nil {
+		return 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.ring)
+}
@@ -0,0 +1,119 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RepresentativeChange is a change of the representative of a wallet,
+// Hash: the hash of the block setting the representative,
+// Height: the height of the block,
+// Subtype: the subtype of the block (open, change, send or receive),
+// Representative: the new representative,
+// Previous: the previous representative (empty for the open block),
+// Time: the time the node saw the block (zero if unknown).
+type RepresentativeChange struct {
+	Hash           string    `json:"hash"`
+	Height         uint64    `json:"height"`
+	Subtype        string    `json:"subtype"`
+	Representative string    `json:"representative"`
+	Previous       string    `json:"previous"`
+	Time           time.Time `json:"time"`
+}
+
+// GetRepresentativeHistory extracts every representative change of a wallet from its raw history,
+// to audit when and to which representatives the wallet delegated over time,
+// address: the wallet address,
+// returns the changes oldest first or an error.
+func (c *Client) GetRepresentativeHistory(address string) ([]RepresentativeChange, error) {
+	type entry struct {
+		Type           string `json:"type"`
+		Subtype        string `json:"subtype"`
+		Representative string `json:"representative"`
+		LocalTimestamp string `json:"local_timestamp"`
+		Hash           string `json:"hash"`
+		Height         string `json:"height"`
+	}
+
+	var blocks []entry
+	head := ""
+
+	for {
+		data := map[string]any{
+			"action":  "account_history",
+			"account": address,
+			"count":   1000,
+			"raw":     "true",
+		}
+
+		if head != "" {
+			data["head"] = head
+		}
+
+		res, err := c.RPC(data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var body struct {
+			History  []entry `json:"history"`
+			Previous string  `json:"previous"`
+
+			Error any `json:"error"`
+		}
+		json.Unmarshal(res, &body)
+
+		if body.Error != nil {
+			return nil, rpcError(body.Error)
+		}
+
+		blocks = append(blocks, body.History...)
+
+		if body.Previous == "" || len(body.History) == 0 {
+			break
+		}
+
+		head = body.Previous
+	}
+
+	changes := []RepresentativeChange{}
+	current := ""
+
+	for i := len(blocks) - 1; i >= 0; i-- {
+		b := blocks[i]
+
+		if b.Representative == "" || b.Representative == current {
+			continue
+		}
+
+		height, err := strconv.ParseUint(b.Height, 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not parse height (%s)", b.Height)
+		}
+
+		change := RepresentativeChange{
+			Hash:           b.Hash,
+			Height:         height,
+			Subtype:        b.Subtype,
+			Representative: b.Representative,
+			Previous:       current,
+		}
+
+		if change.Subtype == "" {
+			change.Subtype = b.Type
+		}
+
+		if ts, err := strconv.ParseInt(b.LocalTimestamp, 10, 64); err == nil && ts > 0 {
+			change.Time = time.Unix(ts, 0)
+		}
+
+		changes = append(changes, change)
+		current = b.Representative
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,401 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BlockInfo is the info of a block,
+// BlockAccount: the wallet address of the block,
+// Amount: the amount of the block in raw,
+// Balance: the balance of the account after the block in raw,
+// Height: the height of the block in the account chain,
+// LocalTimestamp: the time the node saw the block,
+// Successor: the next block in the account chain,
+// Confirmed: whether the block is confirmed,
+// Contents: the block,
+// Subtype: the subtype of the block,
+// Error: the error of the request.
+type BlockInfo struct {
+	BlockAccount   string `json:"block_account"`
+	Amount         string `json:"amount"`
+	Balance        string `json:"balance"`
+	Height         string `json:"height"`
+	LocalTimestamp string `json:"local_timestamp"`
+	Successor      string `json:"successor"`
+	Confirmed      string `json:"confirmed"`
+	Contents       Block  `json:"contents"`
+	Subtype        string `json:"subtype"`
+
+	Error any `json:"error"`
+}
+
+// BlockCount is the block count of the node,
+// Count: the count of blocks in the ledger,
+// Unchecked: the count of unchecked blocks,
+// Cemented: the count of cemented blocks,
+// Error: the error of the request.
+type BlockCount struct {
+	Count     string `json:"count"`
+	Unchecked string `json:"unchecked"`
+	Cemented  string `json:"cemented"`
+
+	Error any `json:"error"`
+}
+
+// Telemetry is the telemetry of the network,
+// the fields are the averages of the peers reported by the node,
+// Error: the error of the request.
+type Telemetry struct {
+	BlockCount        string `json:"block_count"`
+	CementedCount     string `json:"cemented_count"`
+	UncheckedCount    string `json:"unchecked_count"`
+	AccountCount      string `json:"account_count"`
+	BandwidthCap      string `json:"bandwidth_cap"`
+	PeerCount         string `json:"peer_count"`
+	ProtocolVersion   string `json:"protocol_version"`
+	Uptime            string `json:"uptime"`
+	GenesisBlock      string `json:"genesis_block"`
+	MajorVersion      string `json:"major_version"`
+	MinorVersion      string `json:"minor_version"`
+	PatchVersion      string `json:"patch_version"`
+	PreReleaseVersion string `json:"pre_release_version"`
+	Maker             string `json:"maker"`
+	Timestamp         string `json:"timestamp"`
+	ActiveDifficulty  string `json:"active_difficulty"`
+
+	Error any `json:"error"`
+}
+
+// GetBlockInfo gets the info of a block, through the BlockCache of the client if set,
+// hash: the hash of the block,
+// returns the block info, ErrBlockNotFound or an error.
+func (c *Client) GetBlockInfo(hash string) (BlockInfo, error) {
+	return c.blockInfo(hash, true)
+}
+
+// blockInfo gets the info of a block like GetBlockInfo, asking the node about hashes
+// cached as unknown if notFound is false, e.g. for blocks expected to appear.
+func (c *Client) blockInfo(hash string, notFound bool) (BlockInfo, error) {
+	if c.Blocks != nil {
+		if info, found, ok := c.Blocks.get(hash); ok && (found || notFound) {
+			if !found {
+				return BlockInfo{}, ErrBlockNotFound
+			}
+
+			return info, nil
+		}
+	}
+
+	data := map[string]any{
+		"action":     "block_info",
+		"hash":       hash,
+		"json_block": "true",
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return BlockInfo{}, err
+	}
+
+	var info BlockInfo
+	json.Unmarshal(res, &info)
+
+	if info.Error != nil {
+		err := rpcError(info.Error)
+
+		if c.Blocks != nil && errors.Is(err, ErrBlockNotFound) {
+			c.Blocks.putNotFound(hash)
+		}
+
+		return BlockInfo{}, err
+	}
+
+	if c.Blocks != nil {
+		c.Blocks.put(hash, info)
+	}
+
+	return info, nil
+}
+
+// GetBlocksInfo gets the info of multiple blocks, through the BlockCache of the client if set,
+// hashes: the hashes of the blocks,
+// returns the block infos by uppercase hash, or ErrBlockNotFound naming the unknown hashes.
+func (c *Client) GetBlocksInfo(hashes []string) (map[string]BlockInfo, error) {
+	blocks, notFound, err := c.lookupBlocks(hashes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(notFound) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrBlockNotFound, strings.Join(notFound, ", "))
+	}
+
+	return blocks, nil
+}
+
+func (c *Client) lookupBlocks(hashes []string) (map[string]BlockInfo, []string, error) {
+	blocks := map[string]BlockInfo{}
+	var missing, notFound []string
+
+	for _, h := range hashes {
+		// keyed by the uppercase hash like the response of the node, whether cached or not
+		h = strings.ToUpper(h)
+
+		if c.Blocks == nil {
+			missing = append(missing, h)
+			continue
+		}
+
+		info, found, ok := c.Blocks.get(h)
+
+		switch {
+		case !ok:
+			missing = append(missing, h)
+		case !found:
+			notFound = append(notFound, h)
+		default:
+			blocks[h] = info
+		}
+	}
+
+	if len(missing) > 0 {
+		data := map[string]any{
+			"action":            "blocks_info",
+			"hashes":            missing,
+			"json_block":        "true",
+			"include_not_found": "true",
+		}
+
+		res, err := c.RPC(data)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var body struct {
+			Blocks         map[string]BlockInfo `json:"blocks"`
+			BlocksNotFound []string             `json:"blocks_not_found"`
+
+			Error any `json:"error"`
+		}
+		json.Unmarshal(res, &body)
+
+		if body.Error != nil {
+			return nil, nil, rpcError(body.Error)
+		}
+
+		for h, info := range body.Blocks {
+			h = strings.ToUpper(h)
+			blocks[h] = info
+
+			if c.Blocks != nil {
+				c.Blocks.put(h, info)
+			}
+		}
+
+		for _, h := range body.BlocksNotFound {
+			h = strings.ToUpper(h)
+			notFound = append(notFound, h)
+
+			if c.Blocks != nil {
+				c.Blocks.putNotFound(h)
+			}
+		}
+	}
+
+	return blocks, notFound, nil
+}
+
+// GetBlockCount gets the block count of the node,
+// returns the block count or an error.
+func (c *Client) GetBlockCount() (BlockCount, error) {
+	data := map[string]any{
+		"action": "block_count",
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return BlockCount{}, err
+	}
+
+	var count BlockCount
+	json.Unmarshal(res, &count)
+
+	if count.Error != nil {
+		return BlockCount{}, rpcError(count.Error)
+	}
+
+	return count, nil
+}
+
+// NodeInfo is the version information of a node,
+// RPCVersion: the version of the RPC protocol,
+// StoreVersion: the version of the ledger store,
+// ProtocolVersion: the version of the network protocol,
+// NodeVendor: the vendor and version of the node (e.g. Nano V25.1),
+// StoreVendor: the vendor of the ledger store,
+// Network: the network of the node (live, beta or test),
+// BuildInfo: the build information of the node,
+// Error: the error of the request.
+type NodeInfo struct {
+	RPCVersion      string `json:"rpc_version"`
+	StoreVersion    string `json:"store_version"`
+	ProtocolVersion string `json:"protocol_version"`
+	NodeVendor      string `json:"node_vendor"`
+	StoreVendor     string `json:"store_vendor"`
+	Network         string `json:"network"`
+	BuildInfo       string `json:"build_info"`
+
+	Error any `json:"error"`
+}
+
+// GetVersion gets the version information of the node,
+// returns the version information or an error.
+func (c *Client) GetVersion() (NodeInfo, error) {
+	data := map[string]any{
+		"action": "version",
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return NodeInfo{}, err
+	}
+
+	var info NodeInfo
+	json.Unmarshal(res, &info)
+
+	if info.Error != nil {
+		return NodeInfo{}, rpcError(info.Error)
+	}
+
+	return info, nil
+}
+
+// GetAccountKey gets the public key of a wallet from the node,
+// address: the wallet address,
+// returns the public key in hex or an error.
+func (c *Client) GetAccountKey(address string) (string, error) {
+	data := map[string]any{
+		"action":  "account_key",
+		"account": address,
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Key string `json:"key"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return "", rpcError(body.Error)
+	}
+
+	return body.Key, nil
+}
+
+// GetDelegators gets the delegators of a representative,
+// representative: the wallet address of the representative,
+// returns the balances in raw by delegator address or an error.
+func (c *Client) GetDelegators(representative string) (map[string]string, error) {
+	data := map[string]any{
+		"action":  "delegators",
+		"account": representative,
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Delegators map[string]string `json:"delegators"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return nil, rpcError(body.Error)
+	}
+
+	if body.Delegators == nil {
+		body.Delegators = map[string]string{}
+	}
+
+	return body.Delegators, nil
+}
+
+// GetRepresentativesWeights gets the representatives of the network with their voting weight,
+// count: the maximum count of representatives, sorted by weight (0 for all),
+// returns the weights in raw by representative address or an error.
+func (c *Client) GetRepresentativesWeights(count int) (map[string]string, error) {
+	data := map[string]any{
+		"action":  "representatives",
+		"sorting": "true",
+	}
+
+	if count > 0 {
+		data["count"] = count
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Representatives map[string]string `json:"representatives"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return nil, rpcError(body.Error)
+	}
+
+	if body.Representatives == nil {
+		body.Representatives = map[string]string{}
+	}
+
+	return body.Representatives, nil
+}
+
+// GetTelemetry gets the average telemetry of the peers of the node,
+// returns the telemetry or an error.
+func (c *Client) GetTelemetry() (Telemetry, error) {
+	data := map[string]any{
+		"action": "telemetry",
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return Telemetry{}, err
+	}
+
+	var telemetry Telemetry
+	json.Unmarshal(res, &telemetry)
+
+	if telemetry.Error != nil {
+		return Telemetry{}, rpcError(telemetry.Error)
+	}
+
+	return telemetry, nil
+}
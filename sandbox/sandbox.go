@@ -0,0 +1,789 @@
+// Package sandbox simulates a tiny Nano ledger in memory, for demos and tests of
+// payment flows without a node.
+package sandbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/zenitria/nanogo"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const zeroHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// Ledger is an in-memory Nano ledger answering the RPC actions used by the nanogo client
+// (accounts, balances, receivables, history, blocks, work and publishing), blocks are
+// validated like a node does, except the work which is not checked,
+// ConfirmDelay: the delay before published blocks are confirmed (optional, immediate by default),
+// OnConfirmed: called when a block is confirmed (optional).
+type Ledger struct {
+	ConfirmDelay time.Duration                         // optional
+	OnConfirmed  func(hash string, block nanogo.Block) // optional
+
+	mu       sync.Mutex
+	genesis  [32]byte
+	address  string
+	accounts map[string]*account
+	blocks   map[string]*entry
+	count    int
+}
+
+type account struct {
+	chain     []*entry
+	confirmed int
+}
+
+type entry struct {
+	hash         string
+	account      string
+	block        nanogo.Block
+	subtype      string
+	amount       *big.Int
+	counterparty string
+	height       int
+	confirmed    bool
+	received     bool
+	timestamp    int64
+}
+
+var _ nanogo.RPCCaller = (*Ledger)(nil)
+
+// New creates a ledger with a genesis account holding the whole supply, which is also
+// the only online representative,
+// returns the ledger or an error.
+func New() (*Ledger, error) {
+	seed := make([]byte, 32)
+
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+
+	privKey, err := nanogo.SeedToPrivateKey(hex.EncodeToString(seed), 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := nanogo.PrivateKeyToPublicKey(privKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := nanogo.PublicKeyToAddress(pubKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	block := nanogo.Block{
+		Type:           "state",
+		Account:        address,
+		Previous:       zeroHash,
+		Representative: address,
+		Balance:        nanogo.GenesisAmount,
+		Link:           fmt.Sprintf("%064X", pubKey),
+		LinkAsAccount:  address,
+	}
+
+	if err := block.Sign(privKey); err != nil {
+		return nil, err
+	}
+
+	hash, err := block.Hash()
+
+	if err != nil {
+		return nil, err
+	}
+
+	amount, _ := new(big.Int).SetString(nanogo.GenesisAmount, 10)
+	genesis := &entry{
+		hash:         hash,
+		account:      address,
+		block:        block,
+		subtype:      "open",
+		amount:       amount,
+		counterparty: address,
+		height:       1,
+		confirmed:    true,
+		received:     true,
+		timestamp:    time.Now().Unix(),
+	}
+
+	return &Ledger{
+		genesis:  privKey,
+		address:  address,
+		accounts: map[string]*account{address: {chain: []*entry{genesis}, confirmed: 1}},
+		blocks:   map[string]*entry{hash: genesis},
+		count:    1,
+	}, nil
+}
+
+// Genesis returns the address of the genesis account.
+func (l *Ledger) Genesis() string {
+	return l.address
+}
+
+// Fund sends an amount from the genesis account to a wallet and confirms it immediately,
+// the wallet has to receive it like any other send,
+// address: the wallet address,
+// raw: the amount in raw,
+// returns the hash of the send block or an error.
+func (l *Ledger) Fund(address, raw string) (string, error) {
+	pubKey, err := nanogo.AddressToPublicKey(address)
+
+	if err != nil {
+		return "", err
+	}
+
+	amount, ok := new(big.Int).SetString(raw, 10)
+
+	if !ok || amount.Sign() <= 0 {
+		return "", fmt.Errorf("invalid amount (%s)", raw)
+	}
+
+	l.mu.Lock()
+	head := l.accounts[l.address].chain[len(l.accounts[l.address].chain)-1]
+	l.mu.Unlock()
+
+	balance, err := nanogo.BalanceAfterSend(head.block.Balance, raw)
+
+	if err != nil {
+		return "", err
+	}
+
+	block := nanogo.Block{
+		Type:           "state",
+		Account:        l.address,
+		Previous:       head.hash,
+		Representative: l.address,
+		Balance:        balance,
+		Link:           fmt.Sprintf("%064X", pubKey),
+		LinkAsAccount:  address,
+	}
+
+	if err := block.Sign(l.genesis); err != nil {
+		return "", err
+	}
+
+	hash, err := l.process("send", block)
+
+	if err != nil {
+		return "", err
+	}
+
+	l.confirm(hash)
+
+	return hash, nil
+}
+
+// Confirm confirms a block and the blocks below it without waiting for ConfirmDelay,
+// hash: the hash of the block,
+// returns an error if the block is unknown.
+func (l *Ledger) Confirm(hash string) error {
+	l.mu.Lock()
+	_, ok := l.blocks[strings.ToUpper(hash)]
+	l.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("block not found (%s)", hash)
+	}
+
+	l.confirm(hash)
+
+	return nil
+}
+
+// Client returns a client whose requests are answered by the ledger.
+func (l *Ledger) Client() *nanogo.Client {
+	return &nanogo.Client{Url: "sandbox", Middlewares: []nanogo.Middleware{l.Middleware()}}
+}
+
+// Middleware returns a Middleware answering the requests of a client with the ledger
+// instead of sending them to a node.
+func (l *Ledger) Middleware() nanogo.Middleware {
+	return func(next nanogo.RPCHandler) nanogo.RPCHandler {
+		return func(ctx context.Context, data map[string]any) ([]byte, error) {
+			return l.RPC(data)
+		}
+	}
+}
+
+// ServeHTTP answers JSON-RPC requests over HTTP, to use the ledger from other languages.
+func (l *Ledger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var data map[string]any
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, err := l.RPC(data)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(res)
+}
+
+// RPC answers a JSON-RPC request,
+// data: the body of the request,
+// returns the response or an error.
+func (l *Ledger) RPC(data map[string]any) ([]byte, error) {
+	raw, err := json.Marshal(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var req request
+
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(l.handle(req))
+}
+
+type request struct {
+	Action          string          `json:"action"`
+	Account         string          `json:"account"`
+	Accounts        []string        `json:"accounts"`
+	Hash            string          `json:"hash"`
+	Hashes          []string        `json:"hashes"`
+	Head            string          `json:"head"`
+	Count           json.Number     `json:"count"`
+	Offset          json.Number     `json:"offset"`
+	Raw             string          `json:"raw"`
+	Threshold       string          `json:"threshold"`
+	Subtype         string          `json:"subtype"`
+	Block           json.RawMessage `json:"block"`
+	IncludeNotFound string          `json:"include_not_found"`
+}
+
+type failure struct {
+	Error string `json:"error"`
+}
+
+func (l *Ledger) handle(req request) any {
+	switch req.Action {
+	case "process":
+		var block nanogo.Block
+
+		if err := json.Unmarshal(req.Block, &block); err != nil {
+			return failure{"Invalid block"}
+		}
+
+		hash, err := l.process(req.Subtype, block)
+
+		if err != nil {
+			return failure{err.Error()}
+		}
+
+		if l.ConfirmDelay > 0 {
+			time.AfterFunc(l.ConfirmDelay, func() { l.confirm(hash) })
+		} else {
+			l.confirm(hash)
+		}
+
+		return map[string]string{"hash": hash}
+	case "work_generate":
+		work := make([]byte, 8)
+		rand.Read(work)
+
+		return map[string]string{"work": hex.EncodeToString(work), "hash": req.Hash}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch req.Action {
+	case "account_balance":
+		return l.balance(req.Account)
+	case "accounts_balances":
+		balances := map[string]nanogo.AccountBalance{}
+
+		for _, a := range req.Accounts {
+			balances[a] = l.balance(a)
+		}
+
+		return map[string]any{"balances": balances}
+	case "account_info":
+		return l.info(req.Account)
+	case "account_block_count":
+		acc := l.account(req.Account)
+
+		if acc == nil {
+			return failure{"Account not found"}
+		}
+
+		return map[string]string{"block_count": strconv.Itoa(len(acc.chain))}
+	case "account_history":
+		return l.history(req)
+	case "account_key":
+		key, err := nanogo.AddressToPublicKey(req.Account)
+
+		if err != nil {
+			return failure{"Bad account number"}
+		}
+
+		return map[string]string{"key": fmt.Sprintf("%064X", key)}
+	case "accounts_frontiers":
+		frontiers := map[string]string{}
+
+		for _, a := range req.Accounts {
+			if acc := l.account(a); acc != nil {
+				frontiers[a] = acc.chain[len(acc.chain)-1].hash
+			}
+		}
+
+		return map[string]any{"frontiers": frontiers}
+	case "receivable", "pending":
+		return l.receivable(req)
+	case "representatives_online":
+		return map[string]any{"representatives": []string{l.address}}
+	case "block_info":
+		e, ok := l.blocks[strings.ToUpper(req.Hash)]
+
+		if !ok {
+			return failure{"Block not found"}
+		}
+
+		return l.blockInfo(e)
+	case "blocks_info":
+		blocks := map[string]nanogo.BlockInfo{}
+		notFound := []string{}
+
+		for _, h := range req.Hashes {
+			if e, ok := l.blocks[strings.ToUpper(h)]; ok {
+				blocks[h] = l.blockInfo(e)
+			} else {
+				notFound = append(notFound, h)
+			}
+		}
+
+		if req.IncludeNotFound != "true" && len(notFound) > 0 {
+			return failure{"Block not found"}
+		}
+
+		return map[string]any{"blocks": blocks, "blocks_not_found": notFound}
+	case "block_confirm":
+		if _, ok := l.blocks[strings.ToUpper(req.Hash)]; !ok {
+			return failure{"Block not found"}
+		}
+
+		return map[string]string{"started": "1"}
+	case "block_count":
+		cemented := 0
+
+		for _, acc := range l.accounts {
+			cemented += acc.confirmed
+		}
+
+		return nanogo.BlockCount{Count: strconv.Itoa(l.count), Unchecked: "0", Cemented: strconv.Itoa(cemented)}
+	case "version":
+		versions := nanogo.SupportedNodeVersions()
+
+		return nanogo.NodeInfo{RPCVersion: "1", NodeVendor: "Nano " + versions[len(versions)-1].String(), Network: "sandbox"}
+	}
+
+	return failure{"Unknown command"}
+}
+
+// account returns the account of an address, nil if it is not opened.
+func (l *Ledger) account(address string) *account {
+	a, err := nanogo.ParseAddress(address)
+
+	if err != nil {
+		return nil
+	}
+
+	return l.accounts[a.String()]
+}
+
+func (l *Ledger) balance(address string) nanogo.AccountBalance {
+	balance := "0"
+
+	if acc := l.account(address); acc != nil {
+		balance = acc.chain[len(acc.chain)-1].block.Balance
+	}
+
+	receivable := new(big.Int)
+
+	for _, e := range l.receivableOf(address) {
+		receivable.Add(receivable, e.amount)
+	}
+
+	return nanogo.AccountBalance{Balance: balance, Pending: receivable.String(), Receivable: receivable.String()}
+}
+
+func (l *Ledger) info(address string) any {
+	acc := l.account(address)
+
+	if acc == nil {
+		return failure{"Account not found"}
+	}
+
+	head := acc.chain[len(acc.chain)-1]
+	confirmed := acc.chain[acc.confirmed-1]
+
+	return nanogo.AccountInfo{
+		Frontier:                   head.hash,
+		ConfirmedFrontier:          confirmed.hash,
+		OpenBlock:                  acc.chain[0].hash,
+		RepresentativeBlock:        head.hash,
+		Balance:                    head.block.Balance,
+		ConfirmedBalance:           confirmed.block.Balance,
+		Representative:             head.block.Representative,
+		ConfirmedRepresentative:    confirmed.block.Representative,
+		ModifiedTimestamp:          strconv.FormatInt(head.timestamp, 10),
+		BlockCount:                 strconv.Itoa(len(acc.chain)),
+		ConfirmedHeight:            strconv.Itoa(acc.confirmed),
+		AccountVersion:             "2",
+		ConfirmationHeight:         strconv.Itoa(acc.confirmed),
+		ConfirmationHeightFrontier: confirmed.hash,
+	}
+}
+
+func (l *Ledger) history(req request) any {
+	acc := l.account(req.Account)
+
+	if acc == nil {
+		return map[string]any{"account": req.Account, "history": []any{}}
+	}
+
+	count, err := req.Count.Int64()
+
+	if err != nil || count < 0 {
+		count = int64(len(acc.chain))
+	}
+
+	start := len(acc.chain) - 1
+
+	if req.Head != "" {
+		e, ok := l.blocks[strings.ToUpper(req.Head)]
+
+		if !ok || acc.chain[e.height-1] != e {
+			return failure{"Invalid block hash"}
+		}
+
+		start = e.height - 1
+	}
+
+	type item struct {
+		Type           string `json:"type"`
+		Account        string `json:"account"`
+		Amount         string `json:"amount"`
+		LocalTimestamp string `json:"local_timestamp"`
+		Hash           string `json:"hash"`
+		Height         string `json:"height"`
+		Confirmed      string `json:"confirmed"`
+		Subtype        string `json:"subtype,omitempty"`
+		Representative string `json:"representative,omitempty"`
+	}
+
+	offset, _ := req.Offset.Int64()
+	history := []item{}
+	previous := ""
+	i := start
+
+	for ; i >= 0 && int64(len(history)) < count; i-- {
+		e := acc.chain[i]
+
+		if e.subtype == "change" && req.Raw != "true" {
+			continue
+		}
+
+		if offset > 0 {
+			offset--
+			continue
+		}
+
+		kind := e.subtype
+
+		if kind == "open" {
+			kind = "receive"
+		}
+
+		h := item{
+			Type:           kind,
+			Account:        e.counterparty,
+			Amount:         e.amount.String(),
+			LocalTimestamp: strconv.FormatInt(e.timestamp, 10),
+			Hash:           e.hash,
+			Height:         strconv.Itoa(e.height),
+			Confirmed:      strconv.FormatBool(e.confirmed),
+		}
+
+		if req.Raw == "true" {
+			h.Type = "state"
+			h.Subtype = e.subtype
+			h.Representative = e.block.Representative
+		}
+
+		history = append(history, h)
+	}
+
+	if i >= 0 {
+		previous = acc.chain[i].hash
+	}
+
+	return map[string]any{"account": req.Account, "history": history, "previous": previous}
+}
+
+func (l *Ledger) receivable(req request) any {
+	threshold := new(big.Int)
+
+	if req.Threshold != "" {
+		if _, ok := threshold.SetString(req.Threshold, 10); !ok {
+			return failure{"Bad threshold number"}
+		}
+	}
+
+	count, err := req.Count.Int64()
+
+	if err != nil || count <= 0 {
+		count = -1
+	}
+
+	blocks := map[string]map[string]string{}
+
+	for _, e := range l.receivableOf(req.Account) {
+		if int64(len(blocks)) == count {
+			break
+		}
+
+		if e.amount.Cmp(threshold) >= 0 {
+			blocks[e.hash] = map[string]string{"amount": e.amount.String(), "source": e.account}
+		}
+	}
+
+	return map[string]any{"blocks": blocks}
+}
+
+// receivableOf returns the confirmed sends to an address not received yet, oldest first.
+func (l *Ledger) receivableOf(address string) []*entry {
+	a, err := nanogo.ParseAddress(address)
+
+	if err != nil {
+		return nil
+	}
+
+	var sends []*entry
+
+	for _, e := range l.blocks {
+		if e.subtype == "send" && e.confirmed && !e.received && e.counterparty == a.String() {
+			sends = append(sends, e)
+		}
+	}
+
+	sort.Slice(sends, func(i, j int) bool {
+		if sends[i].timestamp != sends[j].timestamp {
+			return sends[i].timestamp < sends[j].timestamp
+		}
+
+		return sends[i].hash < sends[j].hash
+	})
+
+	return sends
+}
+
+func (l *Ledger) blockInfo(e *entry) nanogo.BlockInfo {
+	acc := l.accounts[e.account]
+	successor := zeroHash
+
+	if e.height < len(acc.chain) {
+		successor = acc.chain[e.height].hash
+	}
+
+	return nanogo.BlockInfo{
+		BlockAccount:   e.account,
+		Amount:         e.amount.String(),
+		Balance:        e.block.Balance,
+		Height:         strconv.Itoa(e.height),
+		LocalTimestamp: strconv.FormatInt(e.timestamp, 10),
+		Successor:      successor,
+		Confirmed:      strconv.FormatBool(e.confirmed),
+		Contents:       e.block,
+		Subtype:        e.subtype,
+	}
+}
+
+// process validates a block and adds it to the ledger, the node error messages are
+// returned for invalid blocks.
+func (l *Ledger) process(subtype string, block nanogo.Block) (string, error) {
+	hash, err := block.Hash()
+
+	if err != nil {
+		return "", errors.New("Invalid block")
+	}
+
+	if ok, err := block.VerifySignature(); err != nil || !ok {
+		return "", errors.New("Bad signature")
+	}
+
+	address, err := nanogo.ParseAddress(block.Account)
+
+	if err != nil {
+		return "", errors.New("Bad account number")
+	}
+
+	balance, ok := new(big.Int).SetString(block.Balance, 10)
+
+	if !ok || balance.Sign() < 0 {
+		return "", errors.New("Invalid balance")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.blocks[hash]; ok {
+		return "", errors.New("Old block")
+	}
+
+	acc := l.accounts[address.String()]
+	previous := new(big.Int)
+
+	if acc == nil {
+		if strings.Trim(block.Previous, "0") != "" {
+			return "", errors.New("Gap previous block")
+		}
+	} else {
+		head := acc.chain[len(acc.chain)-1]
+
+		if !strings.EqualFold(block.Previous, head.hash) {
+			if prev, ok := l.blocks[strings.ToUpper(block.Previous)]; ok && prev.account == head.account {
+				return "", errors.New("Fork")
+			}
+
+			return "", errors.New("Gap previous block")
+		}
+
+		previous.SetString(head.block.Balance, 10)
+	}
+
+	e := &entry{hash: hash, account: address.String(), block: block, timestamp: time.Now().Unix()}
+	var source *entry
+
+	switch balance.Cmp(previous) {
+	case -1:
+		pubKey, err := hex.DecodeString(block.Link)
+
+		if err != nil || len(pubKey) != 32 {
+			return "", errors.New("Invalid link")
+		}
+
+		destination, err := nanogo.PublicKeyToAddress([32]byte(pubKey))
+
+		if err != nil {
+			return "", errors.New("Invalid link")
+		}
+
+		e.subtype = "send"
+		e.amount = new(big.Int).Sub(previous, balance)
+		e.counterparty = destination
+	case 1:
+		source = l.blocks[strings.ToUpper(block.Link)]
+
+		if source == nil || source.subtype != "send" || source.counterparty != address.String() {
+			return "", errors.New("Gap source block")
+		}
+
+		if source.received {
+			return "", errors.New("Unreceivable")
+		}
+
+		// like the node, which keeps receives of unconfirmed sends unchecked
+		if !source.confirmed {
+			return "", errors.New("Gap source block")
+		}
+
+		e.amount = new(big.Int).Sub(balance, previous)
+
+		if e.amount.Cmp(source.amount) != 0 {
+			return "", errors.New("Balance and amount delta do not match")
+		}
+
+		e.subtype = "receive"
+		e.counterparty = source.account
+
+		if acc == nil {
+			e.subtype = "open"
+		}
+	default:
+		if acc == nil {
+			return "", errors.New("Gap source block")
+		}
+
+		if strings.Trim(block.Link, "0") != "" {
+			return "", errors.New("Balance and amount delta do not match")
+		}
+
+		e.subtype = "change"
+		e.amount = new(big.Int)
+		e.counterparty = block.Representative
+	}
+
+	if subtype != "" && subtype != e.subtype && !(subtype == "receive" && e.subtype == "open") {
+		return "", errors.New("Invalid block balance for given subtype")
+	}
+
+	if acc == nil {
+		acc = &account{}
+		l.accounts[address.String()] = acc
+	}
+
+	if source != nil {
+		source.received = true
+	}
+
+	e.height = len(acc.chain) + 1
+	acc.chain = append(acc.chain, e)
+	l.blocks[hash] = e
+	l.count++
+
+	return hash, nil
+}
+
+// confirm confirms a block and the blocks below it, and calls OnConfirmed for
+// the newly confirmed blocks in height order.
+func (l *Ledger) confirm(hash string) {
+	l.mu.Lock()
+
+	e, ok := l.blocks[strings.ToUpper(hash)]
+
+	if !ok {
+		l.mu.Unlock()
+		return
+	}
+
+	acc := l.accounts[e.account]
+	var confirmed []*entry
+
+	for ; acc.confirmed < e.height; acc.confirmed++ {
+		b := acc.chain[acc.confirmed]
+		b.confirmed = true
+		confirmed = append(confirmed, b)
+	}
+
+	l.mu.Unlock()
+
+	if l.OnConfirmed != nil {
+		for _, b := range confirmed {
+			l.OnConfirmed(b.hash, b.block)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package nanogo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CatchUpPolicy decides what a Scheduler does with runs missed while it was not running.
+type CatchUpPolicy int
+
+const (
+	// CatchUpOnce pays a missed standing order once and continues with the next future run.
+	CatchUpOnce CatchUpPolicy = iota
+	// CatchUpAll pays every missed run of a standing order.
+	CatchUpAll
+	// CatchUpSkip skips missed runs and continues with the next future run.
+	CatchUpSkip
+)
+
+// StandingOrder is a recurring payment,
+// ID: the unique identifier of the order,
+// Destination: the destination wallet address,
+// Amount: the amount to send in raw,
+// Interval: the interval between payments,
+// NextRun: the time of the next payment,
+// LastRun: the time of the last payment (zero if never paid),
+// LastHash: the block hash of the last payment.
+type StandingOrder struct {
+	ID          string        `json:"id" protobuf:"1"`
+	Destination string        `json:"destination" protobuf:"2"`
+	Amount      string        `json:"amount" protobuf:"3"`
+	Interval    time.Duration `json:"interval" protobuf:"4"`
+	NextRun     time.Time     `json:"next_run" protobuf:"5"`
+	LastRun     time.Time     `json:"last_run" protobuf:"6"`
+	LastHash    string        `json:"last_hash" protobuf:"7"`
+}
+
+// ScheduledRun is the result of a standing order payment,
+// Order: the standing order (after the run),
+// Due: the time the payment was due,
+// Hash: the block hash of the payment,
+// Err: the error of the payment.
+type ScheduledRun struct {
+	Order StandingOrder
+	Due   time.Time
+	Hash  string
+	Err   error
+}
+
+// ScheduleStore persists the standing orders of a Scheduler.
+type ScheduleStore interface {
+	Load() ([]StandingOrder, error)
+	Save(orders []StandingOrder) error
+}
+
+// FileScheduleStore is a ScheduleStore keeping standing orders in a file,
+// Path: the path of the file,
+// Codec: the codec of the file (optional, indented JSON by default).
+type FileScheduleStore struct {
+	Path  string
+	Codec Codec // optional
+}
+
+// Load loads the standing orders from the file, a missing file has no orders,
+// returns the orders or an error.
+func (s FileScheduleStore) Load() ([]StandingOrder, error) {
+	data, err := os.ReadFile(s.Path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return []StandingOrder{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []StandingOrder
+	codec := s.Codec
+
+	if codec == nil {
+		codec = JSONCodec
+	}
+
+	if err := codec.Unmarshal(data, &orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// Save saves the standing orders to the file,
+// orders: the orders to save,
+// returns an error.
+func (s FileScheduleStore) Save(orders []StandingOrder) error {
+	var data []byte
+	var err error
+
+	if s.Codec == nil {
+		data, err = json.MarshalIndent(orders, "", "  ")
+	} else {
+		data, err = s.Codec.Marshal(orders)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	tmp := s.Path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.Path)
+}
+
+// Scheduler executes standing orders from a wallet,
+// Client: the client used to send the payments,
+// Seed: the seed of the paying wallet,
+// Index: the index of the paying wallet (usually 0),
+// Store: the store persisting the standing orders (optional),
+// CatchUp: the policy for runs missed while the scheduler was not running,
+// Tick: the interval Run checks for due orders at (default 1 second),
+// BeforeRun: called before a payment, returning false skips the run (optional),
+// OnRun: called after every payment attempt (optional).
+type Scheduler struct {
+	Client    *Client
+	Seed      string
+	Index     int
+	Store     ScheduleStore
+	CatchUp   CatchUpPolicy
+	Tick      time.Duration
+	BeforeRun func(order StandingOrder, due time.Time) bool
+	OnRun     func(run ScheduledRun)
+
+	mu     sync.Mutex
+	loaded bool
+	orders []StandingOrder
+}
+
+// Add adds a standing order, a zero NextRun starts it now,
+// order: the standing order to add,
+// returns an error.
+func (s *Scheduler) Add(order StandingOrder) error {
+	if order.ID == "" {
+		return fmt.Errorf("standing order has no id")
+	}
+
+	if order.Interval <= 0 {
+		return fmt.Errorf("standing order interval must be positive")
+	}
+
+	if _, err := ParseAmount(order.Amount); err != nil {
+		return err
+	}
+
+	if order.NextRun.IsZero() {
+		order.NextRun = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	for _, o := range s.orders {
+		if o.ID == order.ID {
+			return fmt.Errorf("standing order %s already exists", order.ID)
+		}
+	}
+
+	s.orders = append(s.orders, order)
+
+	return s.save()
+}
+
+// Remove removes a standing order,
+// id: the identifier of the order,
+// returns an error.
+func (s *Scheduler) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	for i, o := range s.orders {
+		if o.ID == id {
+			s.orders = append(s.orders[:i], s.orders[i+1:]...)
+			return s.save()
+		}
+	}
+
+	return fmt.Errorf("standing order %s not found", id)
+}
+
+// Orders returns the standing orders,
+// returns the orders or an error.
+func (s *Scheduler) Orders() ([]StandingOrder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return append([]StandingOrder{}, s.orders...), nil
+}
+
+// RunDue pays every standing order due at a time, failed payments are reported
+// in the runs and OnRun but not retried, the advanced NextRun of an order being saved
+// before its payment is sent so a crash during the send never pays a run twice, and
+// the orders not being locked during the send so Add, Remove and Orders never wait
+// for the node,
+// now: the current time,
+// returns the runs or an error if the orders could not be loaded or saved.
+func (s *Scheduler) RunDue(now time.Time) ([]ScheduledRun, error) {
+	s.mu.Lock()
+	err := s.load()
+	ids := make([]string, len(s.orders))
+
+	for i, o := range s.orders {
+		ids[i] = o.ID
+	}
+
+	s.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []ScheduledRun
+
+	for _, id := range ids {
+		for {
+			o, due, ok, err := s.advance(id, now)
+
+			if err != nil {
+				return runs, err
+			}
+
+			if !ok {
+				break
+			}
+
+			if s.BeforeRun != nil && !s.BeforeRun(o, due) {
+				continue
+			}
+
+			run := ScheduledRun{Due: due}
+			run.Hash, run.Err = s.Client.Send(o.Destination, o.Amount, s.Seed, s.Index)
+			run.Order, err = s.record(o, now, run)
+			runs = append(runs, run)
+
+			if err != nil {
+				return runs, err
+			}
+
+			if s.OnRun != nil {
+				s.OnRun(run)
+			}
+		}
+	}
+
+	return runs, nil
+}
+
+// advance advances the NextRun of an order past its next due run at a time and saves it,
+// returns the order, the time the run was due and true if a run is due and not skipped
+// by the CatchUp policy, or an error.
+func (s *Scheduler) advance(id string, now time.Time) (StandingOrder, time.Time, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := s.order(id)
+
+	if o == nil || o.NextRun.After(now) {
+		return StandingOrder{}, time.Time{}, false, nil
+	}
+
+	due := o.NextRun
+	missed := !now.Before(due.Add(o.Interval))
+	next := due.Add(o.Interval)
+
+	for missed && s.CatchUp != CatchUpAll && !next.After(now) {
+		next = next.Add(o.Interval)
+	}
+
+	// the run stays due if the advanced order could not be saved
+	o.NextRun = next
+
+	if err := s.save(); err != nil {
+		o.NextRun = due
+		return StandingOrder{}, time.Time{}, false, err
+	}
+
+	if missed && s.CatchUp == CatchUpSkip {
+		return StandingOrder{}, time.Time{}, false, nil
+	}
+
+	return *o, due, true, nil
+}
+
+// record records a successful payment of an order and saves it, an order removed
+// during the payment is only updated in the run,
+// returns the order after the run or an error.
+func (s *Scheduler) record(order StandingOrder, now time.Time, run ScheduledRun) (StandingOrder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := s.order(order.ID)
+
+	if o == nil {
+		o = &order
+	}
+
+	if run.Err != nil {
+		return *o, nil
+	}
+
+	o.LastRun = now
+	o.LastHash = run.Hash
+
+	if o == &order {
+		return *o, nil
+	}
+
+	return *o, s.save()
+}
+
+// order returns the order with an id, nil if there is none.
+func (s *Scheduler) order(id string) *StandingOrder {
+	for i := range s.orders {
+		if s.orders[i].ID == id {
+			return &s.orders[i]
+		}
+	}
+
+	return nil
+}
+
+// Run pays due standing orders every Tick until the context is done,
+// ctx: the context stopping the scheduler,
+// returns the context error or an error if the orders could not be loaded or saved.
+func (s *Scheduler) Run(ctx context.Context) error {
+	tick := s.Tick
+
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunDue(time.Now()); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) load() error {
+	if s.loaded {
+		return nil
+	}
+
+	if s.Store != nil {
+		orders, err := s.Store.Load()
+
+		if err != nil {
+			return err
+		}
+
+		s.orders = orders
+	}
+
+	s.loaded = true
+
+	return nil
+}
+
+func (s *Scheduler) save() error {
+	if s.Store == nil {
+		return nil
+	}
+
+	return s.Store.Save(s.orders)
+}
@@ -0,0 +1,241 @@
+package nanogo
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Share is a Shamir share of a secret,
+// Index: the x coordinate of the share (1 to 255),
+// Value: the y coordinates of the share, one per byte of the secret.
+type Share struct {
+	Index byte
+	Value []byte
+}
+
+// String encodes the share as "index-value" with the value in hex.
+func (s Share) String() string {
+	return fmt.Sprintf("%d-%X", s.Index, s.Value)
+}
+
+// ParseShare parses a share encoded with String,
+// share: the encoded share,
+// returns the share or an error.
+func ParseShare(share string) (Share, error) {
+	index, value, ok := strings.Cut(strings.TrimSpace(share), "-")
+
+	if !ok {
+		return Share{}, fmt.Errorf("could not parse share")
+	}
+
+	i, err := strconv.ParseUint(index, 10, 8)
+
+	if err != nil || i == 0 {
+		return Share{}, fmt.Errorf("could not parse share index (%s)", index)
+	}
+
+	v, err := hex.DecodeString(value)
+
+	if err != nil {
+		return Share{}, fmt.Errorf("could not decode share: %v", err)
+	}
+
+	return Share{Index: byte(i), Value: v}, nil
+}
+
+// SplitSecret splits a secret into n Shamir shares over GF(256), any k of which reconstruct it,
+// secret: the secret to split,
+// n: the count of shares (at most 255),
+// k: the count of shares needed to reconstruct the secret,
+// returns the shares or an error.
+func SplitSecret(secret []byte, n, k int) ([]Share, error) {
+	if k < 2 || n < k || n > 255 {
+		return nil, fmt.Errorf("invalid share counts (%d of %d)", k, n)
+	}
+
+	coeffs := make([]byte, k)
+	defer wipe(coeffs)
+
+	shares := make([]Share, n)
+
+	for i := range shares {
+		shares[i] = Share{Index: byte(i + 1), Value: make([]byte, len(secret))}
+	}
+
+	for b, s := range secret {
+		coeffs[0] = s
+
+		if _, err := rand.Read(coeffs[1:]); err != nil {
+			return nil, err
+		}
+
+		for i := range shares {
+			shares[i].Value[b] = gfEval(coeffs, shares[i].Index)
+		}
+	}
+
+	return shares, nil
+}
+
+// CombineShares reconstructs a secret from at least k of its shares,
+// a wrong or insufficient set of shares reconstructs a wrong secret without error,
+// shares: the shares,
+// returns the secret or an error.
+func CombineShares(shares []Share) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("at least 2 shares are needed")
+	}
+
+	size := len(shares[0].Value)
+	seen := map[byte]bool{}
+
+	for _, s := range shares {
+		if len(s.Value) != size {
+			return nil, fmt.Errorf("shares have different lengths")
+		}
+
+		if s.Index == 0 || seen[s.Index] {
+			return nil, fmt.Errorf("invalid or duplicate share index (%d)", s.Index)
+		}
+
+		seen[s.Index] = true
+	}
+
+	secret := make([]byte, size)
+
+	for b := range secret {
+		var value byte
+
+		for i, si := range shares {
+			// Lagrange basis polynomial at x = 0
+			basis := byte(1)
+
+			for j, sj := range shares {
+				if i != j {
+					basis = gfMul(basis, gfDiv(sj.Index, sj.Index^si.Index))
+				}
+			}
+
+			value ^= gfMul(si.Value[b], basis)
+		}
+
+		secret[b] = value
+	}
+
+	return secret, nil
+}
+
+// SplitSeed splits a hex seed into n encoded shares, any k of which reconstruct it,
+// seed: the seed in hex,
+// n: the count of shares,
+// k: the count of shares needed to reconstruct the seed,
+// returns the encoded shares or an error.
+func SplitSeed(seed string, n, k int) ([]string, error) {
+	seedBytes, err := hex.DecodeString(seed)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not decode seed: %v", err)
+	}
+
+	defer wipe(seedBytes)
+
+	if len(seedBytes) != 32 {
+		return nil, fmt.Errorf("seed length is not 32 bytes")
+	}
+
+	shares, err := SplitSecret(seedBytes, n, k)
+
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := make([]string, len(shares))
+
+	for i, s := range shares {
+		encoded[i] = s.String()
+	}
+
+	return encoded, nil
+}
+
+// CombineSeed reconstructs a hex seed from encoded shares,
+// shares: the encoded shares,
+// returns the seed in hex or an error.
+func CombineSeed(shares []string) (string, error) {
+	parsed := make([]Share, len(shares))
+
+	for i, s := range shares {
+		share, err := ParseShare(s)
+
+		if err != nil {
+			return "", err
+		}
+
+		parsed[i] = share
+	}
+
+	secret, err := CombineShares(parsed)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer wipe(secret)
+
+	return fmt.Sprintf("%064X", secret), nil
+}
+
+// wipe overwrites secret material with zeros.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// gfEval evaluates a polynomial over GF(256) at x with Horner's method.
+func gfEval(coeffs []byte, x byte) byte {
+	var y byte
+
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ coeffs[i]
+	}
+
+	return y
+}
+
+// gfMul multiplies in GF(256) with the AES polynomial x^8 + x^4 + x^3 + x + 1.
+func gfMul(a, b byte) byte {
+	var p byte
+
+	for b > 0 {
+		if b&1 != 0 {
+			p ^= a
+		}
+
+		carry := a & 0x80
+		a <<= 1
+
+		if carry != 0 {
+			a ^= 0x1b
+		}
+
+		b >>= 1
+	}
+
+	return p
+}
+
+// gfDiv divides in GF(256), b must not be 0.
+func gfDiv(a, b byte) byte {
+	// b^254 is the inverse of b
+	inv := byte(1)
+
+	for i := 0; i < 254; i++ {
+		inv = gfMul(inv, b)
+	}
+
+	return gfMul(a, inv)
+}
@@ -0,0 +1,118 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Signer signs blocks on behalf of an account.
+type Signer interface {
+	SignBlock(block *Block) error
+}
+
+// PrivateKeySigner is a Signer signing blocks locally with a private key,
+// PrivateKey: the private key of the account.
+type PrivateKeySigner struct {
+	PrivateKey [32]byte
+}
+
+// SignBlock signs a block with the private key,
+// block: the block to sign,
+// returns an error.
+func (s PrivateKeySigner) SignBlock(block *Block) error {
+	return block.Sign(s.PrivateKey)
+}
+
+// RPCSigner is a Signer backed by the sign RPC of a node, for deployments
+// keeping keys on a hardened co-located node,
+// Client: the client of the node,
+// Wallet: the node wallet containing the account of the block (optional),
+// Key: the private key in hex, used when Wallet is empty (optional).
+type RPCSigner struct {
+	Client *Client
+	Wallet string
+	Key    string
+}
+
+// SignBlock signs a block with the sign RPC,
+// block: the block to sign,
+// returns an error.
+func (s RPCSigner) SignBlock(block *Block) error {
+	var sig string
+	var err error
+
+	if s.Wallet != "" {
+		sig, err = s.Client.SignWithWallet(*block, s.Wallet, block.Account)
+	} else {
+		sig, err = s.Client.SignWithKey(*block, s.Key)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	block.Signature = strings.ToUpper(sig)
+
+	return nil
+}
+
+// SignWithKey signs a block with the sign RPC using a private key,
+// block: the block to sign,
+// key: the private key in hex,
+// returns the signature or an error.
+func (c *Client) SignWithKey(block Block, key string) (string, error) {
+	data := map[string]any{
+		"action": "sign",
+		"key":    key,
+	}
+
+	return c.sign(data, block)
+}
+
+// SignWithWallet signs a block with the sign RPC using an account of a node wallet,
+// block: the block to sign,
+// wallet: the id of the node wallet,
+// account: the wallet address of the account in the node wallet,
+// returns the signature or an error.
+func (c *Client) SignWithWallet(block Block, wallet, account string) (string, error) {
+	data := map[string]any{
+		"action":  "sign",
+		"wallet":  wallet,
+		"account": account,
+	}
+
+	return c.sign(data, block)
+}
+
+func (c *Client) sign(data map[string]any, block Block) (string, error) {
+	// the node only parses complete blocks
+	if block.Signature == "" {
+		block.Signature = strings.Repeat("0", 128)
+	}
+
+	if block.Work == "" {
+		block.Work = strings.Repeat("0", 16)
+	}
+
+	data["json_block"] = "true"
+	data["block"] = block
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Signature string `json:"signature"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return "", rpcError(body.Error)
+	}
+
+	return body.Signature, nil
+}
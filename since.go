@@ -0,0 +1,93 @@
+package nanogo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// HistorySince gets the blocks added to the history of a wallet after a checkpoint,
+// for incremental sync jobs, newest first like GetAccountHistory,
+// address: the wallet address,
+// lastKnownHash: the hash of the last block already synced (empty for the whole history),
+// returns the new blocks, an error wrapping ErrCheckpointNotOnChain if the checkpoint is
+// no longer on the chain of the wallet (fork or rollback), or an error.
+func (c *Client) HistorySince(address, lastKnownHash string) (AccountHistory, error) {
+	return c.historySince(address, lastKnownHash, false)
+}
+
+// historySince gets the blocks of a wallet after a checkpoint like HistorySince,
+// with every block if raw is true, including the change and epoch blocks.
+func (c *Client) historySince(address, lastKnownHash string, raw bool) (AccountHistory, error) {
+	height := uint64(0)
+
+	if lastKnownHash != "" {
+		info, err := c.GetBlockInfo(lastKnownHash)
+
+		if errors.Is(err, ErrBlockNotFound) {
+			return AccountHistory{}, fmt.Errorf("%w: %s", ErrCheckpointNotOnChain, lastKnownHash)
+		}
+
+		if err != nil {
+			return AccountHistory{}, err
+		}
+
+		if canonicalAddress(info.BlockAccount) != canonicalAddress(address) {
+			return AccountHistory{}, fmt.Errorf("%w: %s is a block of %s", ErrCheckpointNotOnChain, lastKnownHash, info.BlockAccount)
+		}
+
+		height, err = strconv.ParseUint(info.Height, 10, 64)
+
+		if err != nil {
+			return AccountHistory{}, fmt.Errorf("could not parse height (%s)", info.Height)
+		}
+	}
+
+	return c.historySinceHeight(address, height, raw)
+}
+
+// HistorySinceHeight gets the blocks added to the history of a wallet above a height,
+// for incremental sync jobs checkpointing heights instead of hashes, newest first like GetAccountHistory,
+// address: the wallet address,
+// height: the height of the last block already synced (0 for the whole history),
+// returns the new blocks or an error.
+func (c *Client) HistorySinceHeight(address string, height uint64) (AccountHistory, error) {
+	return c.historySinceHeight(address, height, false)
+}
+
+// historySinceHeight gets the blocks of a wallet above a height like HistorySinceHeight,
+// with every block if raw is true, including the change and epoch blocks.
+func (c *Client) historySinceHeight(address string, height uint64, raw bool) (AccountHistory, error) {
+	var since AccountHistory
+	var token PageToken
+
+	for {
+		page, next, err := c.historyPage(address, 100, token, raw)
+
+		if err != nil {
+			return AccountHistory{}, err
+		}
+
+		since.Account = page.Account
+
+		for _, h := range page.History {
+			n, err := strconv.ParseUint(h.Height, 10, 64)
+
+			if err != nil {
+				return AccountHistory{}, fmt.Errorf("could not parse height (%s)", h.Height)
+			}
+
+			if n <= height {
+				return since, nil
+			}
+
+			since.History = append(since.History, h)
+		}
+
+		if next == "" {
+			return since, nil
+		}
+
+		token = next
+	}
+}
@@ -0,0 +1,106 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// snapshotAttempts is the number of reads ReadConsistent tries before giving up.
+const snapshotAttempts = 10
+
+// AccountSnapshot is the state of a wallet in a consistent snapshot,
+// Frontier: the frontier of the wallet (empty if the account isn't opened),
+// Balance: the balance of the wallet in raw,
+// Receivable: the receivable balance of the wallet in raw.
+type AccountSnapshot struct {
+	Frontier   string
+	Balance    string
+	Receivable string
+}
+
+// GetAccountsBalances gets the balances of multiple wallets,
+// addresses: the wallet addresses to get the balances of,
+// returns the balances by wallet address or an error.
+func (c *Client) GetAccountsBalances(addresses []string) (map[string]AccountBalance, error) {
+	data := map[string]any{
+		"action":   "accounts_balances",
+		"accounts": addresses,
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Balances map[string]AccountBalance `json:"balances"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return nil, rpcError(body.Error)
+	}
+
+	if body.Balances == nil {
+		body.Balances = map[string]AccountBalance{}
+	}
+
+	return body.Balances, nil
+}
+
+// ReadConsistent reads the frontiers and balances of multiple wallets and re-checks
+// the frontiers afterwards, retrying until no account changed during the read,
+// addresses: the wallet addresses to read,
+// returns the snapshot by wallet address, ErrSnapshotUnstable if the accounts kept changing, or an error.
+func (c *Client) ReadConsistent(addresses []string) (map[string]AccountSnapshot, error) {
+	before, err := c.GetAccountsFrontiers(addresses)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < snapshotAttempts; attempt++ {
+		balances, err := c.GetAccountsBalances(addresses)
+
+		if err != nil {
+			return nil, err
+		}
+
+		after, err := c.GetAccountsFrontiers(addresses)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if sameFrontiers(addresses, before, after) {
+			snapshot := make(map[string]AccountSnapshot, len(addresses))
+
+			for _, addr := range addresses {
+				snapshot[addr] = AccountSnapshot{
+					Frontier:   after[addr],
+					Balance:    balances[addr].Balance,
+					Receivable: balances[addr].Receivable,
+				}
+			}
+
+			return snapshot, nil
+		}
+
+		before = after
+	}
+
+	return nil, ErrSnapshotUnstable
+}
+
+func sameFrontiers(addresses []string, a, b map[string]string) bool {
+	for _, addr := range addresses {
+		if !strings.EqualFold(a[addr], b[addr]) {
+			return false
+		}
+	}
+
+	return true
+}
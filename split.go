@@ -0,0 +1,168 @@
+package nanogo
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// RemainderPolicy decides who gets the raw left over after splitting an amount by weight.
+type RemainderPolicy int
+
+const (
+	// RemainderFirst gives the remainder to the first recipient.
+	RemainderFirst RemainderPolicy = iota
+	// RemainderLast gives the remainder to the last recipient.
+	RemainderLast
+	// RemainderLargest gives the remainder to the recipient with the largest weight.
+	RemainderLargest
+	// RemainderKeep keeps the remainder in the sending wallet.
+	RemainderKeep
+)
+
+// Weighted is a recipient of a split payment,
+// Address: the wallet address of the recipient,
+// Weight: the share of the recipient relative to the other recipients.
+type Weighted struct {
+	Address string
+	Weight  uint64
+}
+
+// SplitAmount splits an amount between recipients by weight with exact raw precision,
+// total: the amount to split,
+// recipients: the recipients and their weights,
+// policy: the policy for the raw left over by the integer division,
+// returns the amount of every recipient (in the order of recipients) or an error.
+func SplitAmount(total Amount, recipients []Weighted, policy RemainderPolicy) ([]Amount, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no recipients")
+	}
+
+	sum := new(big.Int)
+	largest := 0
+
+	for i, r := range recipients {
+		sum.Add(sum, new(big.Int).SetUint64(r.Weight))
+
+		if r.Weight > recipients[largest].Weight {
+			largest = i
+		}
+	}
+
+	if sum.Sign() == 0 {
+		return nil, fmt.Errorf("sum of weights is zero")
+	}
+
+	t := total.BigInt()
+	shares := make([]*big.Int, len(recipients))
+	remainder := new(big.Int).Set(t)
+
+	for i, r := range recipients {
+		shares[i] = new(big.Int).Mul(t, new(big.Int).SetUint64(r.Weight))
+		shares[i].Quo(shares[i], sum)
+		remainder.Sub(remainder, shares[i])
+	}
+
+	switch policy {
+	case RemainderFirst:
+		shares[0].Add(shares[0], remainder)
+	case RemainderLast:
+		shares[len(shares)-1].Add(shares[len(shares)-1], remainder)
+	case RemainderLargest:
+		shares[largest].Add(shares[largest], remainder)
+	case RemainderKeep:
+	default:
+		return nil, fmt.Errorf("unknown remainder policy (%d)", policy)
+	}
+
+	amounts := make([]Amount, len(shares))
+
+	for i, s := range shares {
+		amounts[i] = Amount{raw: s}
+	}
+
+	return amounts, nil
+}
+
+// SplitSend splits an amount between recipients by weight and sends the shares
+// with chained send blocks, recipients with a zero share are skipped,
+// total: the amount to split,
+// recipients: the recipients (wallet addresses or handles) and their weights,
+// policy: the policy for the raw left over by the integer division,
+// seed: the seed of the sending wallet,
+// index: the index of the sending wallet (usually 0),
+// returns the block hashes of the published sends (also on error) or an error.
+func (c *Client) SplitSend(total Amount, recipients []Weighted, policy RemainderPolicy, seed string, index int) ([]string, error) {
+	amounts, err := SplitAmount(total, recipients, policy)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	resolved := make([]Weighted, len(recipients))
+
+	for i, r := range recipients {
+		addr, err := c.resolveDestination(r.Address)
+
+		if err != nil {
+			return []string{}, err
+		}
+
+		if err := c.checkCounterparty(addr, Outgoing); err != nil {
+			return []string{}, err
+		}
+
+		resolved[i] = Weighted{Address: addr, Weight: r.Weight}
+	}
+
+	privKey, err := SeedToPrivateKey(seed, index)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	pubKey, err := PrivateKeyToPublicKey(privKey)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	addr, err := PublicKeyToAddress(pubKey)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	defer c.lockAccount(addr)()
+
+	state, err := c.sendState(addr)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	var hashes []string
+
+	for i, r := range resolved {
+		if amounts[i].BigInt().Sign() == 0 {
+			continue
+		}
+
+		hash, err := c.send(privKey, addr, state, r.Address, amounts[i].String())
+
+		if err != nil {
+			return hashes, err
+		}
+
+		hashes = append(hashes, hash)
+		// built from the send, the account state cache is disabled with LowMemory
+		balance, err := BalanceAfterSend(state.Balance, amounts[i].String())
+
+		if err != nil {
+			return hashes, err
+		}
+
+		state = FrontierState{Frontier: hash, Balance: balance, Representative: state.Representative}
+	}
+
+	return hashes, nil
+}
@@ -0,0 +1,166 @@
+package nanogo
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/zenitria/nanogo/ed25519"
+	"golang.org/x/crypto/blake2b"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// clientStats is the internal counters of a client, shared with the clients derived with WithContext.
+type clientStats struct {
+	rpcRequests       atomic.Int64
+	rpcErrors         atomic.Int64
+	blocksPublished   atomic.Int64
+	receivesProcessed atomic.Int64
+	cacheHits         atomic.Int64
+	cacheMisses       atomic.Int64
+	workGenerated     atomic.Int64
+	workErrors        atomic.Int64
+	workNanos         atomic.Int64
+}
+
+// ClientStats is the self-telemetry of a client,
+// RPCRequests: the count of RPC requests,
+// RPCErrors: the count of RPC requests that failed,
+// BlocksPublished: the count of blocks published,
+// ReceivesProcessed: the count of receive blocks published,
+// CacheHits: the count of lookups of the account cache that found the account,
+// CacheMisses: the count of lookups of the account cache that did not find the account,
+// CacheHitRate: the share of cache lookups that found the account (0 to 1),
+// WorkGenerated: the count of work generated,
+// WorkErrors: the count of failed work generations,
+// WorkLatency: the average latency of the work provider,
+// Timestamp: the time of the snapshot.
+type ClientStats struct {
+	RPCRequests       int64         `json:"rpc_requests"`
+	RPCErrors         int64         `json:"rpc_errors"`
+	BlocksPublished   int64         `json:"blocks_published"`
+	ReceivesProcessed int64         `json:"receives_processed"`
+	CacheHits         int64         `json:"cache_hits"`
+	CacheMisses       int64         `json:"cache_misses"`
+	CacheHitRate      float64       `json:"cache_hit_rate"`
+	WorkGenerated     int64         `json:"work_generated"`
+	WorkErrors        int64         `json:"work_errors"`
+	WorkLatency       time.Duration `json:"work_latency"`
+	Timestamp         time.Time     `json:"timestamp"`
+}
+
+// SignedClientStats is the self-telemetry of a client signed by an account,
+// Stats: the self-telemetry,
+// Account: the wallet address of the signing account,
+// Signature: the signature of the blake2b hash of the JSON encoded stats in hex.
+type SignedClientStats struct {
+	Stats     ClientStats `json:"stats"`
+	Account   string      `json:"account"`
+	Signature string      `json:"signature"`
+}
+
+// Stats returns a snapshot of the self-telemetry of the client.
+func (c *Client) Stats() ClientStats {
+	s := c.counters()
+
+	stats := ClientStats{
+		RPCRequests:       s.rpcRequests.Load(),
+		RPCErrors:         s.rpcErrors.Load(),
+		BlocksPublished:   s.blocksPublished.Load(),
+		ReceivesProcessed: s.receivesProcessed.Load(),
+		CacheHits:         s.cacheHits.Load(),
+		CacheMisses:       s.cacheMisses.Load(),
+		WorkGenerated:     s.workGenerated.Load(),
+		WorkErrors:        s.workErrors.Load(),
+		Timestamp:         time.Now().UTC(),
+	}
+
+	if lookups := stats.CacheHits + stats.CacheMisses; lookups > 0 {
+		stats.CacheHitRate = float64(stats.CacheHits) / float64(lookups)
+	}
+
+	if stats.WorkGenerated > 0 {
+		stats.WorkLatency = time.Duration(s.workNanos.Load() / stats.WorkGenerated)
+	}
+
+	return stats
+}
+
+// Sign signs the self-telemetry, so a collector can check which client reported it,
+// privateKey: the private key of the signing account,
+// returns the signed self-telemetry or an error.
+func (s ClientStats) Sign(privateKey [32]byte) (SignedClientStats, error) {
+	pubKey, err := PrivateKeyToPublicKey(privateKey)
+
+	if err != nil {
+		return SignedClientStats{}, err
+	}
+
+	addr, err := PublicKeyToAddress(pubKey)
+
+	if err != nil {
+		return SignedClientStats{}, err
+	}
+
+	hash, err := s.digest()
+
+	if err != nil {
+		return SignedClientStats{}, err
+	}
+
+	sig, err := ed25519.Sign(pubKey, privateKey, hash[:])
+
+	if err != nil {
+		return SignedClientStats{}, err
+	}
+
+	return SignedClientStats{Stats: s, Account: addr, Signature: hex.EncodeToString(sig)}, nil
+}
+
+// Verify checks the signature of the self-telemetry,
+// returns true if the signature is valid, false otherwise.
+func (s SignedClientStats) Verify() bool {
+	pubKey, err := AddressToPublicKey(s.Account)
+
+	if err != nil {
+		return false
+	}
+
+	sig, err := hex.DecodeString(s.Signature)
+
+	if err != nil {
+		return false
+	}
+
+	hash, err := s.Stats.digest()
+
+	if err != nil {
+		return false
+	}
+
+	return ed25519.Verify(pubKey, hash[:], sig)
+}
+
+// statsContext separates the digests of the self-telemetry from block hashes and other
+// messages signed with the same key, so a signed report can never pass as one of them.
+const statsContext = "nanogo-stats\x00"
+
+// digest returns the signed digest of the self-telemetry, blake2b of the context and the JSON.
+func (s ClientStats) digest() ([32]byte, error) {
+	data, err := json.Marshal(s)
+
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return blake2b.Sum256(append([]byte(statsContext), data...)), nil
+}
+
+// StatsHandler returns an HTTP handler serving the self-telemetry of the client as JSON,
+// for embedding in the health endpoint of the host application.
+func (c *Client) StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(c.Stats())
+	})
+}
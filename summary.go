@@ -0,0 +1,115 @@
+package nanogo
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// AccountSummary is the aggregated activity of a wallet,
+// Account: the wallet address,
+// Received: the total received amount in raw,
+// Sent: the total sent amount in raw,
+// ReceiveCount: the number of receive blocks,
+// SendCount: the number of send blocks,
+// Counterparties: the number of distinct wallets sent to or received from,
+// FirstActivity: the local timestamp of the oldest block (zero if unknown),
+// LastActivity: the local timestamp of the newest block (zero if unknown),
+// Labels: the labels of the labeled counterparties by wallet address (with an address book only),
+// Unlabeled: the counterparties missing from the address book, flagged for review (with an address book only).
+type AccountSummary struct {
+	Account        string
+	Received       string
+	Sent           string
+	ReceiveCount   int
+	SendCount      int
+	Counterparties int
+	FirstActivity  time.Time
+	LastActivity   time.Time
+	Labels         map[string]string
+	Unlabeled      []string
+}
+
+// SummarizeAccount aggregates the whole history of a wallet,
+// address: the wallet address to summarize,
+// returns the account summary or an error.
+func (c *Client) SummarizeAccount(address string) (AccountSummary, error) {
+	return c.SummarizeAccountWithBook(address, nil)
+}
+
+// SummarizeAccountWithBook aggregates the whole history of a wallet like SummarizeAccount
+// and labels its counterparties,
+// address: the wallet address to summarize,
+// book: the address book (optional),
+// returns the account summary or an error.
+func (c *Client) SummarizeAccountWithBook(address string, book *AddressBook) (AccountSummary, error) {
+	received := big.NewInt(0)
+	sent := big.NewInt(0)
+	counterparties := map[string]bool{}
+	summary := AccountSummary{Account: address}
+	pages := c.historyPages(address)
+
+	for pages.next() {
+		for _, h := range pages.page.History {
+			amount, ok := new(big.Int).SetString(h.Amount, 10)
+
+			if !ok {
+				return AccountSummary{}, fmt.Errorf("could not convert string to big int")
+			}
+
+			switch h.Type {
+			case "receive":
+				received.Add(received, amount)
+				summary.ReceiveCount++
+			case "send":
+				sent.Add(sent, amount)
+				summary.SendCount++
+			default:
+				continue
+			}
+
+			if h.Account != "" {
+				counterparties[h.Account] = true
+			}
+
+			ts, err := strconv.ParseInt(h.LocalTimestamp, 10, 64)
+
+			if err != nil || ts == 0 {
+				continue
+			}
+
+			t := time.Unix(ts, 0)
+
+			if summary.FirstActivity.IsZero() || t.Before(summary.FirstActivity) {
+				summary.FirstActivity = t
+			}
+
+			if t.After(summary.LastActivity) {
+				summary.LastActivity = t
+			}
+		}
+	}
+
+	if pages.err != nil {
+		return AccountSummary{}, pages.err
+	}
+
+	summary.Received = received.String()
+	summary.Sent = sent.String()
+	summary.Counterparties = len(counterparties)
+
+	if book != nil {
+		summary.Labels = map[string]string{}
+
+		for a := range counterparties {
+			if label, ok := book.Label(a); ok {
+				summary.Labels[a] = label
+			}
+		}
+
+		summary.Unlabeled = book.unlabeled(counterparties)
+	}
+
+	return summary, nil
+}
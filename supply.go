@@ -0,0 +1,119 @@
+package nanogo
+
+import (
+	"encoding/json"
+	"github.com/zenitria/nanogo/constants"
+	"math/big"
+)
+
+// Well-known accounts of the supply.
+const (
+	GenesisAddress = constants.LiveGenesisAccount
+	BurnAddress    = constants.BurnAddress
+	LandingAddress = "nano_13ezf4od79h1tgj9aiu4djzcmmguendtjfuhwfukhuucboua8cpoihmh8byo"
+	FaucetAddress  = "nano_35jjmmmh81kydepzeuf9oec8hzkay7msr6yxagzxpcht7thwa5bus5tomgz9"
+)
+
+// GenesisAmount is the total supply created by the genesis block in raw (2^128 - 1).
+const GenesisAmount = constants.GenesisAmount
+
+// Supply is the supply of Nano,
+// Total: the supply created by the genesis block,
+// Genesis: the balance left on the genesis account,
+// Burned: the balance of the burn account,
+// Landrush: the balance left on the landing and faucet accounts of the distribution,
+// Circulating: the total minus the genesis, burned and landrush balances.
+type Supply struct {
+	Total       Raw `json:"total"`
+	Genesis     Raw `json:"genesis"`
+	Burned      Raw `json:"burned"`
+	Landrush    Raw `json:"landrush"`
+	Circulating Raw `json:"circulating"`
+}
+
+// GetSupply computes the supply of Nano from the balances of the well-known accounts,
+// returns the supply or an error.
+func (c *Client) GetSupply() (Supply, error) {
+	balances, err := c.GetAccountsBalances([]string{GenesisAddress, BurnAddress, LandingAddress, FaucetAddress})
+
+	if err != nil {
+		return Supply{}, err
+	}
+
+	parse := func(addr string) (Raw, error) {
+		b, ok := balances[addr]
+
+		if !ok || b.Balance == "" {
+			return Raw{}, nil
+		}
+
+		return ParseAmount(b.Balance)
+	}
+
+	var s Supply
+	var landing, faucet Raw
+
+	s.Total, _ = ParseAmount(GenesisAmount)
+
+	if s.Genesis, err = parse(GenesisAddress); err != nil {
+		return Supply{}, err
+	}
+
+	if s.Burned, err = parse(BurnAddress); err != nil {
+		return Supply{}, err
+	}
+
+	if landing, err = parse(LandingAddress); err != nil {
+		return Supply{}, err
+	}
+
+	if faucet, err = parse(FaucetAddress); err != nil {
+		return Supply{}, err
+	}
+
+	s.Landrush = landing.Add(faucet)
+
+	if s.Circulating, err = s.Total.Sub(s.Genesis.Add(s.Burned).Add(s.Landrush)); err != nil {
+		return Supply{}, err
+	}
+
+	return s, nil
+}
+
+// GetAvailableSupply gets the available supply reported by the node (available_supply),
+// returns the available supply or an error.
+func (c *Client) GetAvailableSupply() (Raw, error) {
+	data := map[string]any{
+		"action": "available_supply",
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return Raw{}, err
+	}
+
+	var body struct {
+		Available string `json:"available"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return Raw{}, rpcError(body.Error)
+	}
+
+	return ParseAmount(body.Available)
+}
+
+// BurnedShare returns the burned balance as a share of the total supply (0 to 1).
+func (s Supply) BurnedShare() float64 {
+	if s.Total.IsZero() {
+		return 0
+	}
+
+	share, _ := new(big.Rat).SetFrac(s.Burned.BigInt(), s.Total.BigInt()).Float64()
+
+	return share
+}
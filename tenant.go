@@ -0,0 +1,331 @@
+package nanogo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Tenancy partitions one Client and WSClient between logical tenants, for platforms
+// embedding the package for many customers: tenants share the configuration, the
+// connections and the account state, so the operations of tenants on the same account
+// are serialized, but get isolated send limits, request rates, stats and event streams,
+// Client: the shared client,
+// WS: the shared WebSocket client (optional),
+// Buffer: the size of the event buffer of every tenant, events are dropped for tenants
+// not keeping up instead of blocking the others (default 100),
+// RequestsPerMinute: the maximum count of RPC requests per minute of every tenant, their
+// requests waiting for their turn like with Throttle (optional, unlimited by default).
+type Tenancy struct {
+	Client            *Client
+	WS                *WSClient // optional
+	Buffer            int
+	RequestsPerMinute int // optional
+
+	mu         sync.Mutex
+	tenants    map[string]*Tenant
+	owners     map[string]string
+	connected  bool
+	subscribed bool
+}
+
+// Tenant is a logical tenant of a Tenancy,
+// ID: the id of the tenant,
+// Client: the client of the tenant, with the shared configuration, connections and account
+// state and the send limits, request rate and stats of the tenant.
+type Tenant struct {
+	ID     string
+	Client *Client
+
+	accounts map[string]bool
+	events   chan ConfirmationMessage
+	dropped  atomic.Int64
+}
+
+// Events returns the confirmations of the accounts of the tenant, closed when the tenant is removed.
+func (t *Tenant) Events() <-chan ConfirmationMessage {
+	return t.events
+}
+
+// Dropped returns the count of events dropped because the tenant did not keep up.
+func (t *Tenant) Dropped() int64 {
+	return t.dropped.Load()
+}
+
+// Add adds a tenant,
+// id: the id of the tenant,
+// limits: the send limits of the tenant (optional),
+// returns the tenant or an error if the id is taken.
+func (t *Tenancy) Add(id string, limits *SendLimiter) (*Tenant, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.tenants == nil {
+		t.tenants = map[string]*Tenant{}
+		t.owners = map[string]string{}
+	}
+
+	if _, ok := t.tenants[id]; ok {
+		return nil, fmt.Errorf("tenant %s already exists", id)
+	}
+
+	buffer := t.Buffer
+
+	if buffer <= 0 {
+		buffer = 100
+	}
+
+	client := t.Client.WithContext(t.Client.Context())
+	client.stats = &clientStats{}
+	client.Limits = limits
+
+	if t.RequestsPerMinute > 0 {
+		client.Middlewares = append([]Middleware{Throttle(t.RequestsPerMinute)}, t.Client.Middlewares...)
+	}
+
+	tenant := &Tenant{ID: id, Client: client, accounts: map[string]bool{}, events: make(chan ConfirmationMessage, buffer)}
+	t.tenants[id] = tenant
+
+	return tenant, nil
+}
+
+// Tenant returns a tenant,
+// id: the id of the tenant,
+// returns the tenant and true, or false if there is no tenant with the id.
+func (t *Tenancy) Tenant(id string) (*Tenant, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tenant, ok := t.tenants[id]
+
+	return tenant, ok
+}
+
+// Watch adds accounts to the event stream of a tenant, an account belongs to one tenant only,
+// id: the id of the tenant,
+// accounts: the wallet addresses,
+// returns an error.
+func (t *Tenancy) Watch(id string, accounts ...string) error {
+	t.mu.Lock()
+
+	tenant, ok := t.tenants[id]
+
+	if !ok {
+		t.mu.Unlock()
+		return fmt.Errorf("unknown tenant %s", id)
+	}
+
+	var add []string
+
+	for _, a := range accounts {
+		key := canonicalAddress(a)
+
+		if owner, ok := t.owners[key]; ok {
+			if owner != id {
+				t.mu.Unlock()
+				return fmt.Errorf("account %s belongs to tenant %s", a, owner)
+			}
+
+			continue
+		}
+
+		t.owners[key] = id
+		tenant.accounts[key] = true
+		add = append(add, key)
+	}
+
+	subscribe := t.connected && !t.subscribed && len(t.owners) > 0
+	update := t.subscribed && len(add) > 0
+	all := t.accountsLocked()
+
+	if subscribe {
+		t.subscribed = true
+	}
+
+	t.mu.Unlock()
+
+	switch {
+	case subscribe:
+		return t.WS.SubscribeConfirmations(all)
+	case update:
+		return t.WS.UpdateAccounts(add, nil)
+	}
+
+	return nil
+}
+
+// Remove removes a tenant, releases its accounts and closes its event stream,
+// id: the id of the tenant,
+// returns an error.
+func (t *Tenancy) Remove(id string) error {
+	t.mu.Lock()
+
+	tenant, ok := t.tenants[id]
+
+	if !ok {
+		t.mu.Unlock()
+		return fmt.Errorf("unknown tenant %s", id)
+	}
+
+	var del []string
+
+	for a := range tenant.accounts {
+		delete(t.owners, a)
+		del = append(del, a)
+	}
+
+	delete(t.tenants, id)
+	close(tenant.events)
+	update := t.subscribed && len(del) > 0
+	t.mu.Unlock()
+
+	if update {
+		return t.WS.UpdateAccounts(nil, del)
+	}
+
+	return nil
+}
+
+// Run connects the shared WebSocket client, subscribes to the accounts of all tenants and
+// routes the confirmations until the context is done,
+// ctx: the context stopping the routing,
+// returns the error of the connection or the context.
+func (t *Tenancy) Run(ctx context.Context) error {
+	if t.WS == nil {
+		return fmt.Errorf("no WebSocket client")
+	}
+
+	if err := t.WS.Connect(ctx); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.connected = true
+	accounts := t.accountsLocked()
+	subscribe := len(accounts) > 0
+	t.subscribed = subscribe
+	t.mu.Unlock()
+
+	// an empty account filter would subscribe to every confirmation of the network
+	if subscribe {
+		if err := t.WS.SubscribeConfirmations(accounts); err != nil {
+			return err
+		}
+	}
+
+	t.Route(ctx, t.WS.Confirmations())
+
+	return ctx.Err()
+}
+
+// Route delivers confirmations to the event streams of the tenants owning their
+// accounts until the context is done or the channel is closed, for event sources
+// other than the shared WebSocket client (e.g. an AccountWatcher),
+// ctx: the context stopping the routing,
+// in: the confirmations.
+func (t *Tenancy) Route(ctx context.Context, in <-chan ConfirmationMessage) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-in:
+			if !ok {
+				return
+			}
+
+			t.deliver(m)
+		}
+	}
+}
+
+func (t *Tenancy) deliver(m ConfirmationMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seen := map[string]bool{}
+
+	for _, a := range []string{m.Account, m.Block.LinkAsAccount} {
+		id, ok := t.owners[canonicalAddress(a)]
+
+		if !ok || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+
+		tenant := t.tenants[id]
+
+		select {
+		case tenant.events <- m:
+		default:
+			tenant.dropped.Add(1)
+		}
+	}
+}
+
+func (t *Tenancy) accountsLocked() []string {
+	accounts := make([]string, 0, len(t.owners))
+
+	for a := range t.owners {
+		accounts = append(accounts, a)
+	}
+
+	sort.Strings(accounts)
+
+	return accounts
+}
+
+// WriteMetrics writes the stats of every tenant in the Prometheus text format with a
+// tenant label,
+// w: the writer of the metrics,
+// returns an error.
+func (t *Tenancy) WriteMetrics(w io.Writer) error {
+	t.mu.Lock()
+	ids := make([]string, 0, len(t.tenants))
+
+	for id := range t.tenants {
+		ids = append(ids, id)
+	}
+
+	tenants := make([]*Tenant, len(ids))
+	sort.Strings(ids)
+
+	for i, id := range ids {
+		tenants[i] = t.tenants[id]
+	}
+
+	t.mu.Unlock()
+
+	metrics := []struct {
+		name  string
+		help  string
+		value func(t *Tenant, s ClientStats) int64
+	}{
+		{"nanogo_tenant_rpc_requests_total", "Count of RPC requests of the tenant.", func(t *Tenant, s ClientStats) int64 { return s.RPCRequests }},
+		{"nanogo_tenant_rpc_errors_total", "Count of failed RPC requests of the tenant.", func(t *Tenant, s ClientStats) int64 { return s.RPCErrors }},
+		{"nanogo_tenant_blocks_published_total", "Count of blocks published by the tenant.", func(t *Tenant, s ClientStats) int64 { return s.BlocksPublished }},
+		{"nanogo_tenant_events_dropped_total", "Count of events dropped because the tenant did not keep up.", func(t *Tenant, s ClientStats) int64 { return t.Dropped() }},
+	}
+
+	stats := make([]ClientStats, len(tenants))
+
+	for i, tenant := range tenants {
+		stats[i] = tenant.Client.Stats()
+	}
+
+	bw := bufio.NewWriter(w)
+
+	for _, m := range metrics {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s counter\n", m.name, m.help, m.name)
+
+		for i, tenant := range tenants {
+			fmt.Fprintf(bw, "%s{tenant=\"%s\"} %d\n", m.name, escapeLabel(tenant.ID), m.value(tenant, stats[i]))
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,157 @@
+package nanogo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Attempt is one HTTP attempt of an RPC request,
+// Action: the RPC action of the request,
+// Url: the url of the RPC server,
+// Retry: the count of the previous attempts of the request,
+// Latency: the duration of the attempt,
+// Error: the error of the attempt (empty on success).
+type Attempt struct {
+	Action  string        `json:"action"`
+	Url     string        `json:"url"`
+	Retry   int           `json:"retry"`
+	Latency time.Duration `json:"latency"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// Trace is the machine-readable report of the attempts of a high-level operation,
+// returned on success and on failure for SLO tracking and debugging,
+// Operation: the name of the operation,
+// Attempts: the HTTP attempts of the RPC requests in order,
+// Nodes: the urls of the RPC servers tried in order of first use,
+// Retries: the count of attempts that retried a failed one,
+// WorkProvider: the type of the WorkProvider used (empty if no work was generated),
+// WorkLatency: the duration of the work generation,
+// Latency: the duration of the whole operation,
+// Error: the error of the operation (empty on success).
+type Trace struct {
+	Operation    string        `json:"operation"`
+	Attempts     []Attempt     `json:"attempts"`
+	Nodes        []string      `json:"nodes"`
+	Retries      int           `json:"retries"`
+	WorkProvider string        `json:"work_provider,omitempty"`
+	WorkLatency  time.Duration `json:"work_latency"`
+	Latency      time.Duration `json:"latency"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// tracer records the trace of the operations of a derived client.
+type tracer struct {
+	mu    sync.Mutex
+	trace Trace
+}
+
+func (t *tracer) attempt(a Attempt) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.trace.Attempts = append(t.trace.Attempts, a)
+
+	if a.Retry > 0 {
+		t.trace.Retries++
+	}
+
+	for _, n := range t.trace.Nodes {
+		if n == a.Url {
+			return
+		}
+	}
+
+	t.trace.Nodes = append(t.trace.Nodes, a.Url)
+}
+
+func (t *tracer) work(provider WorkProvider, latency time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.trace.WorkProvider = fmt.Sprintf("%T", provider)
+	t.trace.WorkLatency += latency
+}
+
+// traced runs an operation on a derived client recording its trace.
+func (c *Client) traced(operation string, op func(c *Client) error) Trace {
+	tc := c.WithContext(c.Context())
+	tc.tracer = &tracer{trace: Trace{Operation: operation}}
+
+	start := time.Now()
+	err := op(tc)
+
+	tc.tracer.mu.Lock()
+	defer tc.tracer.mu.Unlock()
+
+	trace := tc.tracer.trace
+	trace.Latency = time.Since(start)
+
+	if err != nil {
+		trace.Error = err.Error()
+	}
+
+	return trace
+}
+
+// SendWithTrace sends a raw amount of Nano to a wallet like Send and reports its attempts,
+// toAddr: the destination wallet address (or a handle resolved with the AliasResolver),
+// raw: the amount to send in raw,
+// seed: the seed of the sending wallet,
+// index: the index of the sending wallet (usually 0),
+// returns the block hash, the trace of the send or an error.
+func (c *Client) SendWithTrace(toAddress, raw, seed string, index int) (string, Trace, error) {
+	var hash string
+	var err error
+
+	trace := c.traced("send", func(c *Client) error {
+		hash, err = c.Send(toAddress, raw, seed, index)
+		return err
+	})
+
+	return hash, trace, err
+}
+
+// ReceiveWithTrace receives a block like Receive and reports its attempts,
+// hash: the block hash to receive,
+// sourceAddress: the source wallet address,
+// raw: the amount to receive in raw,
+// seed: the seed of the receiving wallet,
+// index: the index of the receiving wallet (usually 0),
+// returns the block hash, the trace of the receive or an error.
+func (c *Client) ReceiveWithTrace(hash, sourceAddress, raw, seed string, index int) (string, Trace, error) {
+	var h string
+	var err error
+
+	trace := c.traced("receive", func(c *Client) error {
+		h, err = c.Receive(hash, sourceAddress, raw, seed, index)
+		return err
+	})
+
+	return h, trace, err
+}
+
+// ReceiveAllWithTrace receives all receivable blocks like ReceiveAll and reports its attempts,
+// seed: the seed of the receiving wallet,
+// index: the index of the receiving wallet (usually 0),
+// returns the block hashes, the trace of the receives or an error.
+func (c *Client) ReceiveAllWithTrace(seed string, index int) ([]string, Trace, error) {
+	var hashes []string
+	var err error
+
+	trace := c.traced("receive_all", func(c *Client) error {
+		hashes, err = c.ReceiveAll(seed, index)
+		return err
+	})
+
+	return hashes, trace, err
+}
@@ -3,8 +3,22 @@ package nanogo
 import (
 	"fmt"
 	"math/big"
+	"sort"
 )
 
+// sortedKeys returns the keys of a map in order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
 func revertBytes(in []byte) []byte {
 	for i := 0; i < len(in)/2; i++ {
 		in[i], in[len(in)-1-i] = in[len(in)-1-i], in[i]
@@ -0,0 +1,345 @@
+package nanogo
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/zenitria/nanogo/ed25519"
+	"os"
+	"strings"
+)
+
+// vectorsVersion is the version of the layout of the test vectors file.
+const vectorsVersion = 1
+
+// VectorMatrix is the matrix of inputs test vectors are generated for,
+// Seeds: the hex seeds derived with the Nano derivation,
+// Mnemonics: the BIP39 mnemonics derived with the BIP44 derivation (empty passphrase),
+// Indexes: the account indexes derived from every seed,
+// MnemonicIndexes: the account indexes derived from every mnemonic, at most 2^31-1 as
+// the BIP44 indexes are hardened,
+// Messages: the hex messages signed by every account derived from the seeds,
+// Blocks: the blocks hashed and signed by the first account of the first seed,
+// which is also their representative when they have none,
+// Amounts: the raw amounts converted to Nano,
+// Works: the works valued against the hash of every block.
+type VectorMatrix struct {
+	Seeds           []string
+	Mnemonics       []string
+	Indexes         []uint32
+	MnemonicIndexes []uint32
+	Messages        []string
+	Blocks          []Block
+	Amounts         []string
+	Works           []string
+}
+
+// DerivationVector is the derivation of an account,
+// Scheme: the identifier of the derivation scheme,
+// Seed: the seed in hex (Nano derivation),
+// Mnemonic: the mnemonic (BIP44 derivation),
+// Index: the index of the account,
+// PrivateKey: the private key in hex,
+// PublicKey: the public key in hex,
+// Address: the wallet address.
+type DerivationVector struct {
+	Scheme     string `json:"scheme"`
+	Seed       string `json:"seed,omitempty"`
+	Mnemonic   string `json:"mnemonic,omitempty"`
+	Index      uint32 `json:"index"`
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+	Address    string `json:"address"`
+}
+
+// SignatureVector is the signature of a message,
+// PrivateKey: the private key in hex,
+// PublicKey: the public key in hex,
+// Message: the message in hex,
+// Signature: the deterministic signature in hex.
+type SignatureVector struct {
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+	Message    string `json:"message"`
+	Signature  string `json:"signature"`
+}
+
+// BlockVector is a hashed and signed block,
+// PrivateKey: the private key the block is signed with in hex,
+// Block: the signed block,
+// Hash: the hash of the block.
+type BlockVector struct {
+	PrivateKey string `json:"private_key"`
+	Block      Block  `json:"block"`
+	Hash       string `json:"hash"`
+}
+
+// ConversionVector is a conversion of an amount,
+// Raw: the amount in raw,
+// Nano: the amount in Nano.
+type ConversionVector struct {
+	Raw  string `json:"raw"`
+	Nano string `json:"nano"`
+}
+
+// WorkVector is the value of a work,
+// Root: the block root the work is valued against,
+// Work: the work in hex,
+// Value: the difficulty value in hex.
+type WorkVector struct {
+	Root  string `json:"root"`
+	Work  string `json:"work"`
+	Value string `json:"value"`
+}
+
+// TestVectors are the outputs of the library for a VectorMatrix, consumed by
+// implementations in other languages to check they agree,
+// Version: the version of the layout of the vectors,
+// Derivations: the derivations of the seeds and mnemonics,
+// Signatures: the signatures of the messages,
+// Blocks: the hashed and signed blocks,
+// Conversions: the conversions of the amounts,
+// Works: the values of the works.
+type TestVectors struct {
+	Version     int                `json:"version"`
+	Derivations []DerivationVector `json:"derivations"`
+	Signatures  []SignatureVector  `json:"signatures"`
+	Blocks      []BlockVector      `json:"blocks"`
+	Conversions []ConversionVector `json:"conversions"`
+	Works       []WorkVector       `json:"works"`
+}
+
+// DefaultVectorMatrix returns the matrix of inputs covering the edge cases of
+// the derivations, signatures, blocks, conversions and works,
+// returns the matrix.
+func DefaultVectorMatrix() VectorMatrix {
+	return VectorMatrix{
+		Seeds: []string{
+			strings.Repeat("0", 64),
+			strings.Repeat("F", 64),
+			"9F1D53E732E48F25F94711D5B22086778278624F715D9B2BEC8FB81134E7C904",
+		},
+		Mnemonics: []string{
+			strings.Repeat("abandon ", 23) + "art",
+			strings.Repeat("abandon ", 11) + "about",
+		},
+		Indexes:         []uint32{0, 1, 255, 4294967295},
+		MnemonicIndexes: []uint32{0, 1, 255, maxHardenedIndex},
+		Messages:        []string{"", "00", hex.EncodeToString([]byte("nanogo")), strings.Repeat("FF", 64)},
+		Blocks: []Block{
+			{
+				Type:     "state",
+				Previous: strings.Repeat("0", 64),
+				Balance:  "1000000000000000000000000000000",
+				Link:     "991CF190094C00F0B68E2E5F75F6BEE95A2E0BD93CEAA4A6734DB9F19B728948",
+			},
+			{
+				Type:           "state",
+				Previous:       "991CF190094C00F0B68E2E5F75F6BEE95A2E0BD93CEAA4A6734DB9F19B728948",
+				Representative: "nano_1111111111111111111111111111111111111111111111111111hifc8npp",
+				Balance:        "0",
+				Link:           strings.Repeat("0", 64),
+			},
+		},
+		Amounts: []string{"0", "1", "1000000000000000000000000", "1000000000000000000000000000000", "340282366920938463463374607431768211455"},
+		Works:   []string{"0000000000000000", "2bf29ef00786a6bc", "ffffffffffffffff"},
+	}
+}
+
+// GenerateVectors generates the test vectors of a matrix of inputs,
+// matrix: the matrix of inputs,
+// returns the vectors or an error.
+func GenerateVectors(matrix VectorMatrix) (TestVectors, error) {
+	v := TestVectors{Version: vectorsVersion}
+	var nanoKeys [][32]byte
+
+	for _, seed := range matrix.Seeds {
+		w, err := NewWalletFromSeed(seed, nil)
+
+		if err != nil {
+			return TestVectors{}, fmt.Errorf("seed %s: %v", seed, err)
+		}
+
+		keys, err := v.derive(w, DerivationVector{Seed: strings.ToUpper(seed)}, matrix.Indexes)
+
+		if err != nil {
+			return TestVectors{}, err
+		}
+
+		nanoKeys = append(nanoKeys, keys...)
+	}
+
+	for _, mnemonic := range matrix.Mnemonics {
+		w, err := NewWalletFromMnemonic(mnemonic, "", nil)
+
+		if err != nil {
+			return TestVectors{}, err
+		}
+
+		if _, err := v.derive(w, DerivationVector{Mnemonic: mnemonic}, matrix.MnemonicIndexes); err != nil {
+			return TestVectors{}, err
+		}
+	}
+
+	for _, key := range nanoKeys {
+		pubKey, err := PrivateKeyToPublicKey(key)
+
+		if err != nil {
+			return TestVectors{}, err
+		}
+
+		for _, m := range matrix.Messages {
+			msg, err := hex.DecodeString(m)
+
+			if err != nil {
+				return TestVectors{}, fmt.Errorf("could not decode message (%s): %v", m, err)
+			}
+
+			sig, err := ed25519.Sign(pubKey, key, msg)
+
+			if err != nil {
+				return TestVectors{}, err
+			}
+
+			v.Signatures = append(v.Signatures, SignatureVector{
+				PrivateKey: fmt.Sprintf("%X", key),
+				PublicKey:  fmt.Sprintf("%X", pubKey),
+				Message:    strings.ToUpper(m),
+				Signature:  fmt.Sprintf("%X", sig),
+			})
+		}
+	}
+
+	if len(matrix.Blocks) > 0 && len(nanoKeys) == 0 {
+		return TestVectors{}, fmt.Errorf("blocks need at least one seed and index to be signed with")
+	}
+
+	for _, block := range matrix.Blocks {
+		key := nanoKeys[0]
+		pubKey, err := PrivateKeyToPublicKey(key)
+
+		if err != nil {
+			return TestVectors{}, err
+		}
+
+		if block.Account, err = PublicKeyToAddress(pubKey); err != nil {
+			return TestVectors{}, err
+		}
+
+		if block.Representative == "" {
+			block.Representative = block.Account
+		}
+
+		if err := block.Sign(key); err != nil {
+			return TestVectors{}, err
+		}
+
+		hash, err := block.Hash()
+
+		if err != nil {
+			return TestVectors{}, err
+		}
+
+		v.Blocks = append(v.Blocks, BlockVector{PrivateKey: fmt.Sprintf("%X", key), Block: block, Hash: hash})
+
+		for _, work := range matrix.Works {
+			value, err := WorkValue(hash, work)
+
+			if err != nil {
+				return TestVectors{}, err
+			}
+
+			v.Works = append(v.Works, WorkVector{Root: hash, Work: strings.ToLower(work), Value: fmt.Sprintf("%016x", value)})
+		}
+	}
+
+	for _, raw := range matrix.Amounts {
+		nano, err := RawToNano(raw)
+
+		if err != nil {
+			return TestVectors{}, fmt.Errorf("amount %s: %w", raw, err)
+		}
+
+		v.Conversions = append(v.Conversions, ConversionVector{Raw: raw, Nano: nano})
+	}
+
+	return v, nil
+}
+
+// derive appends the derivations of the indexes of a wallet to the vectors,
+// returns the derived private keys or an error.
+func (v *TestVectors) derive(w *Wallet, base DerivationVector, indexes []uint32) ([][32]byte, error) {
+	keys := make([][32]byte, 0, len(indexes))
+
+	for _, i := range indexes {
+		key, err := deriveIndex(w, i)
+
+		if err != nil {
+			return nil, err
+		}
+
+		pubKey, err := PrivateKeyToPublicKey(key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		addr, err := PublicKeyToAddress(pubKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		d := base
+		d.Scheme = w.derivation.String()
+		d.Index = i
+		d.PrivateKey = fmt.Sprintf("%X", key)
+		d.PublicKey = fmt.Sprintf("%X", pubKey)
+		d.Address = addr
+		v.Derivations = append(v.Derivations, d)
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// deriveIndex derives the private key at an index of a wallet like Wallet.derive, over
+// the uint32 range of the indexes on every platform,
+// returns the private key or an error if the index is out of the range of the derivation.
+func deriveIndex(w *Wallet, index uint32) ([32]byte, error) {
+	switch w.derivation {
+	case DerivationNano:
+		return deriveNanoKey(w.seed, index), nil
+	case DerivationBIP44:
+		return deriveBIP44(w.seed, uint64(index))
+	default:
+		return [32]byte{}, fmt.Errorf("unknown derivation (%d)", w.derivation)
+	}
+}
+
+// ExportVectors generates the test vectors of a matrix of inputs as JSON,
+// matrix: the matrix of inputs,
+// returns the JSON or an error.
+func ExportVectors(matrix VectorMatrix) ([]byte, error) {
+	v, err := GenerateVectors(matrix)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// WriteVectors generates the test vectors of a matrix of inputs into a JSON file,
+// path: the path of the file,
+// matrix: the matrix of inputs,
+// returns an error.
+func WriteVectors(path string, matrix VectorMatrix) error {
+	data, err := ExportVectors(matrix)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
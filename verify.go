@@ -0,0 +1,231 @@
+package nanogo
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/zenitria/nanogo/constants"
+	"github.com/zenitria/nanogo/ed25519"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// verifyBatchSize is the maximum number of signatures verified in one batch.
+const verifyBatchSize = 64
+
+// VerifyResult is the result of verifying a block,
+// Block: the verified block,
+// Hash: the hash of the block,
+// SignatureValid: true if the signature is valid for the account of the block,
+// WorkValue: the difficulty value of the work,
+// WorkThreshold: the work threshold of the block by its subtype and epoch,
+// WorkValid: true if the work meets WorkThreshold,
+// Err: the error if the block could not be verified (e.g. malformed fields).
+type VerifyResult struct {
+	Block          Block
+	Hash           string
+	SignatureValid bool
+	WorkValue      uint64
+	WorkThreshold  uint64
+	WorkValid      bool
+	Err            error
+}
+
+// VerifyOptions is the options of VerifyBlocksWithOptions,
+// Subtype: returns the subtype of a state block, e.g. from block_info, the subtype of
+// legacy, open and epoch blocks being read from the block (optional, the other state
+// blocks get the send threshold, the highest one, by default),
+// Version: returns the epoch version of the account of a block after it, e.g. from the
+// account_version of account_info, the version of legacy and epoch blocks being read from
+// the block (optional, epoch 2 by default).
+type VerifyOptions struct {
+	Subtype func(block Block) string // optional
+	Version func(block Block) int    // optional
+}
+
+// VerifyBlocks verifies the signatures and work of blocks across a worker pool like
+// VerifyBlocksWithOptions with the default options,
+// ctx: the context stopping the verification,
+// blocks: the blocks to verify,
+// returns the channel of results (not in input order), closed when blocks is closed or the context is done.
+func VerifyBlocks(ctx context.Context, blocks <-chan Block) <-chan VerifyResult {
+	return VerifyBlocksWithOptions(ctx, blocks, VerifyOptions{})
+}
+
+// VerifyBlocksWithOptions verifies the signatures and work of blocks across a worker pool,
+// signatures are checked in batches and individually only when a batch fails,
+// ctx: the context stopping the verification,
+// blocks: the blocks to verify,
+// options: the options of the verification,
+// returns the channel of results (not in input order), closed when blocks is closed or the context is done.
+func VerifyBlocksWithOptions(ctx context.Context, blocks <-chan Block, options VerifyOptions) <-chan VerifyResult {
+	results := make(chan VerifyResult)
+	var wg sync.WaitGroup
+
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				batch, ok := nextBatch(ctx, blocks)
+
+				for _, r := range verifyBatch(batch, options) {
+					select {
+					case results <- r:
+					case <-ctx.Done():
+						return
+					}
+				}
+
+				if !ok {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// nextBatch waits for one block and takes the blocks already queued up to the batch size,
+// returns false when there are no more blocks.
+func nextBatch(ctx context.Context, blocks <-chan Block) ([]Block, bool) {
+	var batch []Block
+
+	select {
+	case b, ok := <-blocks:
+		if !ok {
+			return nil, false
+		}
+
+		batch = append(batch, b)
+	case <-ctx.Done():
+		return nil, false
+	}
+
+	for len(batch) < verifyBatchSize {
+		select {
+		case b, ok := <-blocks:
+			if !ok {
+				return batch, false
+			}
+
+			batch = append(batch, b)
+		default:
+			return batch, true
+		}
+	}
+
+	return batch, true
+}
+
+func verifyBatch(batch []Block, options VerifyOptions) []VerifyResult {
+	results := make([]VerifyResult, len(batch))
+	var pubKeys [][32]byte
+	var msgs, sigs [][]byte
+	var idx []int
+
+	for i, b := range batch {
+		results[i].Block = b
+		hash, err := b.hashBytes()
+
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		results[i].Hash = fmt.Sprintf("%064X", hash)
+
+		if b.Work != "" {
+			root, err := WorkRoot(b)
+
+			if err == nil {
+				results[i].WorkValue, err = WorkValue(root, b.Work)
+			}
+
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+
+			results[i].WorkThreshold = options.workThreshold(b)
+			results[i].WorkValid = results[i].WorkValue >= results[i].WorkThreshold
+		}
+
+		pubKey, err := AddressToPublicKey(b.Account)
+
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		sig, err := hex.DecodeString(b.Signature)
+
+		if err != nil || len(sig) != 64 {
+			continue
+		}
+
+		pubKeys = append(pubKeys, pubKey)
+		msgs = append(msgs, hash)
+		sigs = append(sigs, sig)
+		idx = append(idx, i)
+	}
+
+	if ed25519.VerifyBatch(pubKeys, msgs, sigs) {
+		for _, i := range idx {
+			results[i].SignatureValid = true
+		}
+
+		return results
+	}
+
+	for j, i := range idx {
+		results[i].SignatureValid = ed25519.Verify(pubKeys[j], msgs[j], sigs[j])
+	}
+
+	return results
+}
+
+// workThreshold returns the work threshold of a block by its subtype and the epoch
+// version of its account.
+func (o VerifyOptions) workThreshold(b Block) uint64 {
+	subtype, version := "", 2
+
+	switch {
+	case b.Type != "" && b.Type != "state":
+		// legacy blocks predate the epochs
+		subtype, version = b.Type, 0
+	case strings.EqualFold(b.Link, constants.EpochV1Link):
+		subtype, version = "epoch", 1
+	case strings.EqualFold(b.Link, constants.EpochV2Link):
+		subtype, version = "epoch", 2
+	default:
+		if b.Previous == "0" || b.Previous == strings.Repeat("0", 64) {
+			subtype = "open"
+		} else if o.Subtype != nil {
+			subtype = o.Subtype(b)
+		}
+
+		if o.Version != nil {
+			version = o.Version(b)
+		}
+	}
+
+	if version < 2 {
+		return WorkThresholdEpoch1
+	}
+
+	if subtype == "" {
+		return WorkThresholdSend
+	}
+
+	return WorkThreshold(subtype)
+}
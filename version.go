@@ -0,0 +1,202 @@
+package nanogo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NodeVersion is a version of the Nano node,
+// Major: the major version,
+// Minor: the minor version,
+// Pre: the pre-release suffix (e.g. RC1 or DB2), empty for releases.
+type NodeVersion struct {
+	Major int
+	Minor int
+	Pre   string
+}
+
+var nodeVersion = regexp.MustCompile(`(?i)^(?:nano\s+)?v?(\d+)(?:\.(\d+))?(?:\.\d+)?\s*([a-z]+\d*)?$`)
+
+// ParseNodeVersion parses a node version, as reported in the node_vendor field of
+// the version RPC (Nano V25.1) or written alone (V25.1, 25.1, V26.0RC1),
+// version: the version to parse,
+// returns the version or an error.
+func ParseNodeVersion(version string) (NodeVersion, error) {
+	m := nodeVersion.FindStringSubmatch(strings.TrimSpace(version))
+
+	if m == nil {
+		return NodeVersion{}, fmt.Errorf("could not parse node version (%s)", version)
+	}
+
+	v := NodeVersion{Pre: strings.ToUpper(m[3])}
+	v.Major, _ = strconv.Atoi(m[1])
+
+	if m[2] != "" {
+		v.Minor, _ = strconv.Atoi(m[2])
+	}
+
+	return v, nil
+}
+
+// String returns the version as V<major>.<minor><pre>.
+func (v NodeVersion) String() string {
+	return fmt.Sprintf("V%d.%d%s", v.Major, v.Minor, v.Pre)
+}
+
+// Compare compares the version to another, pre-releases coming before their release,
+// o: the version to compare to,
+// returns -1, 0 or 1 if the version is older, the same or newer.
+func (v NodeVersion) Compare(o NodeVersion) int {
+	switch {
+	case v.Major < o.Major, v.Major == o.Major && v.Minor < o.Minor:
+		return -1
+	case v.Major > o.Major, v.Minor > o.Minor:
+		return 1
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	default:
+		return strings.Compare(v.Pre, o.Pre)
+	}
+}
+
+// NodeFeature is an RPC feature the library uses, available from a node version.
+type NodeFeature string
+
+const (
+	// FeatureWorkBlock is the block option of work_generate, used for epoch aware thresholds.
+	FeatureWorkBlock NodeFeature = "work_generate_block"
+	// FeatureTelemetry is the telemetry RPC.
+	FeatureTelemetry NodeFeature = "telemetry"
+	// FeatureProcessAsync is the async option of process.
+	FeatureProcessAsync NodeFeature = "process_async"
+	// FeatureIncludeConfirmed is the include_confirmed option of account_info and account_balance.
+	FeatureIncludeConfirmed NodeFeature = "include_confirmed"
+	// FeatureReceivableAction is the receivable family of actions replacing pending.
+	FeatureReceivableAction NodeFeature = "receivable_action"
+)
+
+// nodeFeatures are the first node versions supporting the features.
+var nodeFeatures = map[NodeFeature]NodeVersion{
+	FeatureWorkBlock:        {Major: 21},
+	FeatureTelemetry:        {Major: 21},
+	FeatureProcessAsync:     {Major: 22},
+	FeatureIncludeConfirmed: {Major: 22},
+	FeatureReceivableAction: {Major: 23},
+}
+
+// supportedNodeVersions are the major node versions the library is tested against.
+var supportedNodeVersions = []NodeVersion{{Major: 23}, {Major: 24}, {Major: 25}, {Major: 26}, {Major: 27}, {Major: 28}}
+
+// SupportedNodeVersions returns the major node versions the library is tested against,
+// oldest first,
+// returns the versions.
+func SupportedNodeVersions() []NodeVersion {
+	return append([]NodeVersion{}, supportedNodeVersions...)
+}
+
+// NodeFeatures returns the features the library uses with the first node version supporting them,
+// returns the features.
+func NodeFeatures() map[NodeFeature]NodeVersion {
+	features := make(map[NodeFeature]NodeVersion, len(nodeFeatures))
+
+	for f, v := range nodeFeatures {
+		features[f] = v
+	}
+
+	return features
+}
+
+// Supports checks if a node version supports a feature,
+// nodeVersion: the node version (e.g. Nano V25.1),
+// feature: the feature to check,
+// returns true if the version supports the feature, false otherwise or if the version
+// or the feature is unknown.
+func Supports(nodeVersion string, feature NodeFeature) bool {
+	v, err := ParseNodeVersion(nodeVersion)
+
+	if err != nil {
+		return false
+	}
+
+	since, ok := nodeFeatures[feature]
+
+	return ok && v.Compare(since) >= 0
+}
+
+// SupportsReceivableAction checks if a node version supports the receivable actions,
+// nodeVersion: the node version (e.g. Nano V25.1),
+// returns true if the version supports them, false otherwise.
+func SupportsReceivableAction(nodeVersion string) bool {
+	return Supports(nodeVersion, FeatureReceivableAction)
+}
+
+// CompatibilityReport is the compatibility of a node with the library,
+// Version: the version of the node,
+// Supported: whether the major version is one of SupportedNodeVersions,
+// Missing: the features the node does not support,
+// Warnings: actionable warnings to surface, empty if the node is fully compatible.
+type CompatibilityReport struct {
+	Version   NodeVersion
+	Supported bool
+	Missing   []NodeFeature
+	Warnings  []string
+}
+
+// CheckNodeVersion checks the compatibility of a node version with the library,
+// nodeVersion: the node version (e.g. Nano V25.1),
+// returns the report or an error if the version could not be parsed.
+func CheckNodeVersion(nodeVersion string) (CompatibilityReport, error) {
+	v, err := ParseNodeVersion(nodeVersion)
+
+	if err != nil {
+		return CompatibilityReport{}, err
+	}
+
+	r := CompatibilityReport{Version: v}
+	oldest, newest := supportedNodeVersions[0], supportedNodeVersions[len(supportedNodeVersions)-1]
+
+	for _, s := range supportedNodeVersions {
+		if s.Major == v.Major {
+			r.Supported = true
+		}
+	}
+
+	switch {
+	case v.Major < oldest.Major:
+		r.Warnings = append(r.Warnings, fmt.Sprintf("node %s is older than the oldest supported version %s, upgrade the node", v, oldest))
+	case v.Major > newest.Major:
+		r.Warnings = append(r.Warnings, fmt.Sprintf("node %s is newer than the newest tested version %s, check the release notes of the node", v, newest))
+	}
+
+	if v.Pre != "" {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("node %s is a pre-release", v))
+	}
+
+	for _, f := range []NodeFeature{FeatureWorkBlock, FeatureTelemetry, FeatureProcessAsync, FeatureIncludeConfirmed, FeatureReceivableAction} {
+		if since := nodeFeatures[f]; v.Compare(since) < 0 {
+			r.Missing = append(r.Missing, f)
+			r.Warnings = append(r.Warnings, fmt.Sprintf("node %s does not support %s (since %s)", v, f, since))
+		}
+	}
+
+	return r, nil
+}
+
+// CheckCompatibility checks the compatibility of the node with the library, to
+// surface warnings at startup,
+// returns the report or an error.
+func (c *Client) CheckCompatibility() (CompatibilityReport, error) {
+	info, err := c.GetVersion()
+
+	if err != nil {
+		return CompatibilityReport{}, err
+	}
+
+	return CheckNodeVersion(info.NodeVendor)
+}
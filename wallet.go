@@ -0,0 +1,354 @@
+package nanogo
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/tyler-smith/go-bip39"
+	"strings"
+	"sync"
+)
+
+// Derivation is the scheme private keys are derived from the seed of a wallet with.
+type Derivation int
+
+const (
+	// DerivationNano derives keys as blake2b(seed || index), like the Nano node wallet.
+	DerivationNano Derivation = iota
+	// DerivationBIP44 derives keys with SLIP-0010 from a BIP39 seed at m/44'/165'/index'.
+	DerivationBIP44
+)
+
+// maxHardenedIndex is the largest index of a hardened derivation, 2^31-1.
+const maxHardenedIndex = 1<<31 - 1
+
+// String returns the identifier of the derivation scheme.
+func (d Derivation) String() string {
+	switch d {
+	case DerivationNano:
+		return "nano-blake2b"
+	case DerivationBIP44:
+		return "bip44-slip10-m/44'/165'/index'"
+	default:
+		return fmt.Sprintf("unknown-%d", int(d))
+	}
+}
+
+// Wallet is a seed with lazily derived and cached accounts, so the seed never
+// has to be passed around,
+// Client: the client used by the accounts of the wallet,
+// Journal: the journal recording the payouts and receives of the accounts (optional).
+type Wallet struct {
+	Client  *Client
+	Journal *WalletJournal // optional
+
+	seed       []byte
+	derivation Derivation
+
+	mu       sync.Mutex
+	accounts map[int]*WalletAccount
+}
+
+// WalletAccount is an account of a Wallet,
+// Index: the index of the account in the wallet,
+// PublicKey: the public key of the account,
+// Address: the wallet address of the account.
+type WalletAccount struct {
+	Index     int
+	PublicKey [32]byte
+	Address   string
+
+	wallet     *Wallet
+	privateKey [32]byte
+}
+
+// NewWalletFromSeed creates a wallet from a hex seed with the Nano derivation,
+// seed: the seed in hex,
+// client: the client used by the accounts of the wallet,
+// returns the wallet or an error.
+func NewWalletFromSeed(seed string, client *Client) (*Wallet, error) {
+	seedBytes, err := hex.DecodeString(seed)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not decode seed: %v", err)
+	}
+
+	if len(seedBytes) != 32 {
+		return nil, fmt.Errorf("seed length is not 32 bytes")
+	}
+
+	return &Wallet{Client: client, seed: seedBytes, derivation: DerivationNano}, nil
+}
+
+// NewWalletFromMnemonic creates a wallet from a BIP39 mnemonic with the BIP44 derivation
+// used by hardware and most mobile wallets,
+// mnemonic: the BIP39 mnemonic,
+// passphrase: the BIP39 passphrase (usually empty),
+// client: the client used by the accounts of the wallet,
+// returns the wallet or an error.
+func NewWalletFromMnemonic(mnemonic, passphrase string, client *Client) (*Wallet, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(normalizeMnemonic(mnemonic), passphrase)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse mnemonic: %v", err)
+	}
+
+	return &Wallet{Client: client, seed: seed, derivation: DerivationBIP44}, nil
+}
+
+// NewWalletFromLegacyMnemonic creates a wallet from a 24 words mnemonic encoding a hex seed
+// (as shown by the Nano node wallet) with the Nano derivation,
+// mnemonic: the mnemonic of the seed,
+// client: the client used by the accounts of the wallet,
+// returns the wallet or an error.
+func NewWalletFromLegacyMnemonic(mnemonic string, client *Client) (*Wallet, error) {
+	seed, err := MnemonicToSeed(mnemonic)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWalletFromSeed(seed, client)
+}
+
+// NewMnemonic generates a new 24 words BIP39 mnemonic,
+// returns the mnemonic or an error.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(256)
+
+	if err != nil {
+		return "", err
+	}
+
+	return bip39.NewMnemonic(entropy)
+}
+
+// MnemonicIsValid checks if a BIP39 mnemonic is valid (words and checksum),
+// mnemonic: the mnemonic to check,
+// returns true if the mnemonic is valid, false otherwise.
+func MnemonicIsValid(mnemonic string) bool {
+	return bip39.IsMnemonicValid(normalizeMnemonic(mnemonic))
+}
+
+// SeedToMnemonic converts a hex seed to the 24 words mnemonic encoding it,
+// seed: the seed in hex,
+// returns the mnemonic or an error.
+func SeedToMnemonic(seed string) (string, error) {
+	seedBytes, err := hex.DecodeString(seed)
+
+	if err != nil {
+		return "", fmt.Errorf("could not decode seed: %v", err)
+	}
+
+	if len(seedBytes) != 32 {
+		return "", fmt.Errorf("seed length is not 32 bytes")
+	}
+
+	return bip39.NewMnemonic(seedBytes)
+}
+
+// MnemonicToSeed converts a 24 words mnemonic to the hex seed it encodes,
+// mnemonic: the mnemonic to convert,
+// returns the seed in hex or an error.
+func MnemonicToSeed(mnemonic string) (string, error) {
+	entropy, err := bip39.EntropyFromMnemonic(normalizeMnemonic(mnemonic))
+
+	if err != nil {
+		return "", fmt.Errorf("could not parse mnemonic: %v", err)
+	}
+
+	if len(entropy) != 32 {
+		return "", fmt.Errorf("mnemonic does not encode a 32 bytes seed")
+	}
+
+	return fmt.Sprintf("%064X", entropy), nil
+}
+
+// Derivation returns the derivation scheme of the wallet.
+func (w *Wallet) Derivation() Derivation {
+	return w.derivation
+}
+
+// Account returns the account at an index, deriving and caching it on first use,
+// index: the index of the account,
+// returns the account or an error.
+func (w *Wallet) Account(index int) (*WalletAccount, error) {
+	if index < 0 {
+		return nil, fmt.Errorf("index must not be negative")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if a, ok := w.accounts[index]; ok {
+		return a, nil
+	}
+
+	privKey, err := w.derive(index)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := PrivateKeyToPublicKey(privKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := PublicKeyToAddress(pubKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	a := &WalletAccount{
+		Index:      index,
+		PublicKey:  pubKey,
+		Address:    addr,
+		wallet:     w,
+		privateKey: privKey,
+	}
+
+	if w.accounts == nil {
+		w.accounts = map[int]*WalletAccount{}
+	}
+
+	w.accounts[index] = a
+
+	return a, nil
+}
+
+func (w *Wallet) derive(index int) ([32]byte, error) {
+	switch w.derivation {
+	case DerivationNano:
+		return SeedToPrivateKey(hex.EncodeToString(w.seed), index)
+	case DerivationBIP44:
+		if index < 0 {
+			return [32]byte{}, fmt.Errorf("BIP44 index %d is out of the range 0 to %d", index, maxHardenedIndex)
+		}
+
+		return deriveBIP44(w.seed, uint64(index))
+	default:
+		return [32]byte{}, fmt.Errorf("unknown derivation (%d)", w.derivation)
+	}
+}
+
+// deriveBIP44 derives the private key at the BIP44 path m/44'/165'/index' of a seed,
+// rejecting the indexes beyond the hardened range, which would wrap onto smaller ones,
+// seed: the seed,
+// index: the index of the account,
+// returns the private key or an error.
+func deriveBIP44(seed []byte, index uint64) ([32]byte, error) {
+	if index > maxHardenedIndex {
+		return [32]byte{}, fmt.Errorf("BIP44 index %d is out of the range 0 to %d", index, maxHardenedIndex)
+	}
+
+	return slip10Derive(seed, []uint32{44, 165, uint32(index)}), nil
+}
+
+// slip10Derive derives an ed25519 private key from a seed along a path of hardened indexes.
+func slip10Derive(seed []byte, path []uint32) [32]byte {
+	mac := hmac.New(sha512.New, []byte("ed25519 seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+
+	for _, i := range path {
+		data := make([]byte, 37)
+		copy(data[1:33], key)
+		binary.BigEndian.PutUint32(data[33:], i|0x80000000)
+
+		mac = hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum = mac.Sum(nil)
+		key, chainCode = sum[:32], sum[32:]
+	}
+
+	return [32]byte(key)
+}
+
+func normalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
+
+// PrivateKey returns the private key of the account.
+func (a *WalletAccount) PrivateKey() [32]byte {
+	return a.privateKey
+}
+
+// Signer returns a Signer signing blocks with the private key of the account.
+func (a *WalletAccount) Signer() Signer {
+	return PrivateKeySigner{PrivateKey: a.privateKey}
+}
+
+// Send sends a raw amount of Nano from the account, reusing the cached account
+// state like FastSend,
+// toAddress: the destination wallet address (or a handle resolved with the AliasResolver),
+// raw: the amount to send in raw,
+// returns the block hash or an error, with the block hash if the send was published but could not be journaled.
+func (a *WalletAccount) Send(toAddress, raw string) (string, error) {
+	hash, err := a.wallet.Client.fastSendKey(toAddress, raw, a.privateKey)
+
+	if err != nil {
+		return "", err
+	}
+
+	return hash, a.wallet.journal(JournalEvent{Kind: JournalPayout, Account: a.Address, Counterparty: toAddress, Amount: raw, Hash: hash})
+}
+
+// Receive receives a block to the account,
+// hash: the block hash to receive,
+// sourceAddress: the source wallet address,
+// raw: the amount to receive in raw,
+// returns the block hash or an error, with the block hash if the receive was published but could not be journaled.
+func (a *WalletAccount) Receive(hash, sourceAddress, raw string) (string, error) {
+	h, err := a.wallet.Client.receiveKey(hash, sourceAddress, raw, a.privateKey)
+
+	if err != nil {
+		return "", err
+	}
+
+	return h, a.wallet.journal(JournalEvent{Kind: JournalReceive, Account: a.Address, Counterparty: sourceAddress, Amount: raw, Hash: h, Source: hash})
+}
+
+// ReceiveAll receives all receivable blocks of the account,
+// returns the block hashes and an error joining the errors of the failed blocks and of the journal.
+func (a *WalletAccount) ReceiveAll() ([]string, error) {
+	result, err := a.ReceiveAllWithOptions(ReceiveOptions{})
+
+	return result.Hashes, errors.Join(result.Err(), err)
+}
+
+// ReceiveAllWithOptions receives all receivable blocks of the account like
+// Client.ReceiveAllWithOptions,
+// options: the confirmation, threshold and limit options,
+// returns the result or an error if the receivable blocks could not be listed
+// or the receives could not be journaled.
+func (a *WalletAccount) ReceiveAllWithOptions(options ReceiveOptions) (ReceiveResult, error) {
+	var journalErrs []error
+
+	result, err := a.wallet.Client.receiveAllKey(a.privateKey, options, func(b ReceiveFailure, block string) {
+		event := JournalEvent{Kind: JournalReceive, Account: a.Address, Counterparty: b.Source, Amount: b.Amount, Hash: block, Source: b.Hash}
+
+		if err := a.wallet.journal(event); err != nil {
+			journalErrs = append(journalErrs, err)
+		}
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	return result, errors.Join(journalErrs...)
+}
+
+// ChangeRepresentative changes the representative of the account,
+// representative: the new representative wallet address,
+// returns the block hash or an error.
+func (a *WalletAccount) ChangeRepresentative(representative string) (string, error) {
+	return a.wallet.Client.changeRepresentativeKey(representative, a.privateKey)
+}
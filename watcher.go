@@ -0,0 +1,394 @@
+package nanogo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccountWatcher delivers the confirmed blocks of the chains of wallets (every subtype,
+// in both modes), over WebSocket when the node supports it, falling back to adaptive
+// polling otherwise and upgrading back to push once the WebSocket server is reachable,
+// Client: the client polled in fallback,
+// WS: the WebSocket client (optional, polling only without it),
+// Accounts: the watched wallet addresses,
+// MinInterval: the polling interval after activity (default 2 seconds),
+// MaxInterval: the polling interval the watcher backs off to when idle (default 1 minute),
+// UpgradeInterval: the interval between WebSocket connection attempts while polling (default 5 minutes),
+// OnError: called with errors of the connection and the polls (optional),
+// Events: the sink persisting the delivered blocks (optional).
+type AccountWatcher struct {
+	Client          *Client
+	WS              *WSClient // optional
+	Accounts        []string
+	MinInterval     time.Duration
+	MaxInterval     time.Duration
+	UpgradeInterval time.Duration
+	OnError         func(err error) // optional
+	Events          EventSink       // optional
+
+	mu          sync.Mutex
+	push        bool
+	checkpoints map[string]string
+	seen        map[string][]string
+}
+
+// watcherSeenSize is the count of the last delivered hashes remembered per account to
+// deliver a block confirmed while switching between push and poll once.
+const watcherSeenSize = 256
+
+// Push reports whether the watcher currently receives the blocks over WebSocket.
+func (w *AccountWatcher) Push() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.push
+}
+
+// Watch watches the wallets until the context is done,
+// blocks confirmed before Watch are not delivered, the frontiers of the wallets being
+// read first, with retries backing off up to MaxInterval until the node answers,
+// ctx: the context stopping the watcher,
+// returns the channel of confirmed blocks, closed when the context is done.
+func (w *AccountWatcher) Watch(ctx context.Context) <-chan ConfirmationMessage {
+	out := make(chan ConfirmationMessage)
+
+	go func() {
+		defer close(out)
+
+		min, max, _ := w.intervals()
+		delay := min
+
+		for {
+			err := w.initCheckpoints()
+
+			if err == nil {
+				break
+			}
+
+			w.report(err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			if delay *= 2; delay > max {
+				delay = max
+			}
+		}
+
+		w.run(ctx, out)
+	}()
+
+	return out
+}
+
+// watch watches the wallets like Watch from the checkpoints initialized by the caller.
+func (w *AccountWatcher) watch(ctx context.Context) <-chan ConfirmationMessage {
+	out := make(chan ConfirmationMessage)
+
+	go func() {
+		defer close(out)
+
+		w.run(ctx, out)
+	}()
+
+	return out
+}
+
+// run delivers the confirmed blocks in push or poll mode until the context is done.
+func (w *AccountWatcher) run(ctx context.Context, out chan<- ConfirmationMessage) {
+	for ctx.Err() == nil {
+		if w.connect(ctx) {
+			w.forward(ctx, out)
+			continue
+		}
+
+		w.poll(ctx, out)
+	}
+}
+
+// initCheckpoints starts the checkpoints of the wallets at their frontiers, an empty
+// checkpoint delivering the whole history of the wallet.
+func (w *AccountWatcher) initCheckpoints() error {
+	frontiers, err := w.Client.GetAccountsFrontiers(w.Accounts)
+
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.checkpoints = map[string]string{}
+	w.seen = map[string][]string{}
+
+	for _, a := range w.Accounts {
+		w.checkpoints[a] = frontierOf(frontiers, a)
+	}
+
+	return nil
+}
+
+// intervals returns the polling intervals with their defaults.
+func (w *AccountWatcher) intervals() (min, max, upgrade time.Duration) {
+	min, max, upgrade = w.MinInterval, w.MaxInterval, w.UpgradeInterval
+
+	if min <= 0 {
+		min = 2 * time.Second
+	}
+
+	if max < min {
+		max = time.Minute
+
+		if max < min {
+			max = min
+		}
+	}
+
+	if upgrade <= 0 {
+		upgrade = 5 * time.Minute
+	}
+
+	return min, max, upgrade
+}
+
+// connect connects and subscribes the WebSocket client,
+// returns true if the watcher is in push mode.
+func (w *AccountWatcher) connect(ctx context.Context) bool {
+	if w.WS == nil {
+		return false
+	}
+
+	if err := w.WS.Connect(ctx); err != nil {
+		w.report(err)
+		return false
+	}
+
+	if err := w.WS.SubscribeConfirmations(w.Accounts); err != nil {
+		w.report(err)
+		w.WS.Close()
+		return false
+	}
+
+	w.mu.Lock()
+	w.push = true
+	w.mu.Unlock()
+
+	return true
+}
+
+// forward forwards the WebSocket confirmations, after catching up with a poll,
+// until the context is done or the WebSocket client is closed.
+func (w *AccountWatcher) forward(ctx context.Context, out chan<- ConfirmationMessage) {
+	defer func() {
+		w.mu.Lock()
+		w.push = false
+		w.mu.Unlock()
+	}()
+
+	confirmations := w.WS.Confirmations()
+
+	if _, err := w.pollOnce(ctx, out); err != nil {
+		w.report(err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-confirmations:
+			if !ok {
+				return
+			}
+
+			// the blocks of other accounts (sends to the wallets) are not on the
+			// chains of the wallets, which polling reads
+			account, watched := w.watched(m.Account)
+
+			if !watched {
+				continue
+			}
+
+			m.Account = account
+
+			if w.markSeen(account, m.Hash) {
+				w.advance(account, m.Hash)
+				w.persist(m)
+
+				select {
+				case out <- m:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
+
+// poll polls with an adaptive interval until the context is done or it is time to try
+// the WebSocket server again.
+func (w *AccountWatcher) poll(ctx context.Context, out chan<- ConfirmationMessage) {
+	min, max, upgrade := w.intervals()
+	interval := min
+	deadline := time.Now().Add(upgrade)
+
+	for {
+		active, err := w.pollOnce(ctx, out)
+
+		if err != nil {
+			w.report(err)
+		}
+
+		if active {
+			interval = min
+		} else if interval *= 2; interval > max {
+			interval = max
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if w.WS != nil && time.Now().After(deadline) {
+			return
+		}
+	}
+}
+
+// pollOnce delivers the confirmed blocks added after the checkpoint of every wallet,
+// returns true if there was activity, or an error.
+func (w *AccountWatcher) pollOnce(ctx context.Context, out chan<- ConfirmationMessage) (bool, error) {
+	active := false
+
+	for _, a := range w.Accounts {
+		w.mu.Lock()
+		checkpoint := w.checkpoints[a]
+		w.mu.Unlock()
+
+		// raw, the change and epoch blocks are delivered in push mode too
+		history, err := w.Client.historySince(a, checkpoint, true)
+
+		if errors.Is(err, ErrAccountNotFound) {
+			continue
+		}
+
+		if errors.Is(err, ErrCheckpointNotOnChain) {
+			// rolled back, resume from the current frontier
+			frontiers, err := w.Client.GetAccountsFrontiers([]string{a})
+
+			if err != nil {
+				return active, err
+			}
+
+			w.mu.Lock()
+			w.checkpoints[a] = frontierOf(frontiers, a)
+			w.mu.Unlock()
+
+			continue
+		}
+
+		if err != nil {
+			return active, err
+		}
+
+		// oldest first
+		for i := len(history.History) - 1; i >= 0; i-- {
+			h := history.History[i]
+			if h.Confirmed != "true" {
+				break
+			}
+
+			w.advance(a, h.Hash)
+
+			if !w.markSeen(a, h.Hash) {
+				continue
+			}
+
+			active = true
+			m := ConfirmationMessage{
+				Account: a,
+				Amount:  h.Amount,
+				Hash:    h.Hash,
+				Block:   WSBlock{Block: Block{Account: a, LinkAsAccount: h.Account}, Subtype: h.Type},
+				Time:    time.Now(),
+			}
+
+			w.persist(m)
+
+			select {
+			case out <- m:
+			case <-ctx.Done():
+				return active, ctx.Err()
+			}
+		}
+	}
+
+	return active, nil
+}
+
+func (w *AccountWatcher) advance(account, hash string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.checkpoints[account]; ok {
+		w.checkpoints[account] = hash
+	}
+}
+
+// watched returns the watched wallet address matching an address and true, or false if
+// the address is not watched.
+func (w *AccountWatcher) watched(address string) (string, bool) {
+	for _, a := range w.Accounts {
+		if canonicalAddress(a) == canonicalAddress(address) {
+			return a, true
+		}
+	}
+
+	return "", false
+}
+
+// markSeen records a delivered block among the last watcherSeenSize blocks of its account,
+// returns false if it was already delivered.
+func (w *AccountWatcher) markSeen(account, hash string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	seen := w.seen[account]
+
+	for _, h := range seen {
+		if strings.EqualFold(h, hash) {
+			return false
+		}
+	}
+
+	if len(seen) >= watcherSeenSize {
+		seen = seen[1:]
+	}
+
+	w.seen[account] = append(seen, hash)
+
+	return true
+}
+
+func (w *AccountWatcher) persist(m ConfirmationMessage) {
+	if w.Events == nil {
+		return
+	}
+
+	if err := w.Events.Append(m); err != nil {
+		w.report(err)
+	}
+}
+
+func (w *AccountWatcher) report(err error) {
+	if err != nil && w.OnError != nil {
+		w.OnError(err)
+	}
+}
@@ -0,0 +1,276 @@
+package nanogo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WebhookDigest is the summary of the confirmed blocks of an account over a digest window,
+// Account: the wallet address of the account,
+// Start: the time of the first block of the digest,
+// End: the time of the last block of the digest,
+// Count: the count of blocks,
+// Sends: the count of send blocks,
+// Receives: the count of receive and open blocks,
+// AmountOut: the total amount sent in raw,
+// AmountIn: the total amount received in raw,
+// Hashes: the hashes of the blocks in the order they were confirmed.
+type WebhookDigest struct {
+	Account   string    `json:"account"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Count     int       `json:"count"`
+	Sends     int       `json:"sends"`
+	Receives  int       `json:"receives"`
+	AmountOut Amount    `json:"amount_out"`
+	AmountIn  Amount    `json:"amount_in"`
+	Hashes    []string  `json:"hashes"`
+
+	timer *time.Timer
+}
+
+// add adds a confirmed block to the digest.
+func (d *WebhookDigest) add(m ConfirmationMessage, now time.Time) {
+	if d.Count == 0 {
+		d.Start = now
+	}
+
+	d.End = now
+	d.Count++
+	d.Hashes = append(d.Hashes, m.Hash)
+	amount, err := ParseAmount(m.Amount)
+
+	if err != nil {
+		return
+	}
+
+	switch m.Block.Subtype {
+	case "send":
+		d.Sends++
+		d.AmountOut = d.AmountOut.Add(amount)
+	case "receive", "open":
+		d.Receives++
+		d.AmountIn = d.AmountIn.Add(amount)
+	}
+}
+
+// WebhookDigestError is the error of a digest that could not be posted,
+// Digest: the digest, removed from the pending digests,
+// Err: the error of the post.
+type WebhookDigestError struct {
+	Digest WebhookDigest
+	Err    error
+}
+
+// Error returns the error of the post with the account of the digest.
+func (e *WebhookDigestError) Error() string {
+	return fmt.Sprintf("could not post the digest of %s: %v", e.Digest.Account, e.Err)
+}
+
+// Unwrap returns the error of the post.
+func (e *WebhookDigestError) Unwrap() error {
+	return e.Err
+}
+
+// WebhookDispatcher posts the confirmed blocks of accounts to an HTTP endpoint as JSON,
+// one POST per block, or in digest mode one WebhookDigest per account and window,
+// reducing the load of the endpoint for high-traffic accounts,
+// Url: the url of the endpoint,
+// Header: the HTTP headers of the requests, e.g. for authentication (optional),
+// HTTPClient: the HTTP client of the requests (optional, http.DefaultClient by default),
+// Timeout: the timeout of every request (optional, 10 seconds by default),
+// Digest: the window the blocks of an account are batched over (optional, a POST per block by default),
+// OnError: called with the errors of the posts of Run and of the digests, a failed digest
+// being passed as a *WebhookDigestError so it can be stored and posted again (optional).
+type WebhookDispatcher struct {
+	Url        string
+	Header     http.Header     // optional
+	HTTPClient *http.Client    // optional
+	Timeout    time.Duration   // optional
+	Digest     time.Duration   // optional
+	OnError    func(err error) // optional
+
+	mu      sync.Mutex
+	digests map[string]*WebhookDigest
+}
+
+// Dispatch dispatches a confirmed block, posting it or adding it to the digest of its
+// account, the first block of a digest scheduling its post at the end of the window,
+// m: the confirmed block,
+// returns the error of the post.
+func (d *WebhookDispatcher) Dispatch(m ConfirmationMessage) error {
+	if d.Digest <= 0 {
+		return d.post(m)
+	}
+
+	account := m.Account
+
+	if account == "" {
+		account = m.Block.Account
+	}
+
+	now := m.Time
+
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.digests == nil {
+		d.digests = map[string]*WebhookDigest{}
+	}
+
+	digest, ok := d.digests[account]
+
+	if !ok {
+		digest = &WebhookDigest{Account: account}
+		d.digests[account] = digest
+
+		digest.timer = time.AfterFunc(d.Digest, func() {
+			d.report(d.flush(account, digest))
+		})
+	}
+
+	digest.add(m, now)
+
+	return nil
+}
+
+// Flush posts the pending digests of every account,
+// returns the errors of the posts joined, a *WebhookDigestError for every failed digest.
+func (d *WebhookDispatcher) Flush() error {
+	d.mu.Lock()
+	accounts := make([]string, 0, len(d.digests))
+
+	for a := range d.digests {
+		accounts = append(accounts, a)
+	}
+
+	d.mu.Unlock()
+	sort.Strings(accounts)
+
+	var errs []error
+
+	for _, a := range accounts {
+		if err := d.flush(a, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// flush posts the pending digest of an account, if any, and stops the timer of its window,
+// expected: the digest of the timer calling flush, which only posts that digest so a
+// timer firing while the digest is flushed never posts the next one early (nil for any).
+func (d *WebhookDispatcher) flush(account string, expected *WebhookDigest) error {
+	d.mu.Lock()
+	digest, ok := d.digests[account]
+	ok = ok && (expected == nil || digest == expected)
+
+	if ok {
+		digest.timer.Stop()
+		delete(d.digests, account)
+	}
+
+	d.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if err := d.post(digest); err != nil {
+		return &WebhookDigestError{Digest: *digest, Err: err}
+	}
+
+	return nil
+}
+
+// Run dispatches the confirmed blocks of a channel, e.g. of AccountWatcher.Watch,
+// until the channel is closed or the context is done, flushing the pending digests
+// before returning,
+// ctx: the context stopping the dispatcher,
+// in: the confirmed blocks,
+// returns the error of the context.
+func (d *WebhookDispatcher) Run(ctx context.Context, in <-chan ConfirmationMessage) error {
+	defer func() {
+		d.report(d.Flush())
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case m, ok := <-in:
+			if !ok {
+				return ctx.Err()
+			}
+
+			d.report(d.Dispatch(m))
+		}
+	}
+}
+
+func (d *WebhookDispatcher) post(payload any) error {
+	data, err := json.Marshal(payload)
+
+	if err != nil {
+		return err
+	}
+
+	timeout := d.Timeout
+
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", d.Url, bytes.NewReader(data))
+
+	if err != nil {
+		return err
+	}
+
+	for k, v := range d.Header {
+		req.Header[k] = v
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := d.HTTPClient
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	res, err := httpClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", res.Status)
+	}
+
+	return nil
+}
+
+func (d *WebhookDispatcher) report(err error) {
+	if err != nil && d.OnError != nil {
+		d.OnError(err)
+	}
+}
@@ -0,0 +1,380 @@
+package nanogo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WSBlock is a block delivered over WebSocket,
+// Subtype: the subtype of the block (send, receive, open, change or epoch).
+type WSBlock struct {
+	Block   `protobuf:"1"`
+	Subtype string `json:"subtype" protobuf:"2"`
+}
+
+// ConfirmationMessage is a confirmed block delivered by the confirmation topic,
+// Account: the wallet address of the block,
+// Amount: the amount of the block in raw,
+// Hash: the hash of the block,
+// ConfirmationType: the type of the confirmation (e.g. active_quorum),
+// Block: the confirmed block,
+// Time: the time the node sent the message.
+type ConfirmationMessage struct {
+	Account          string  `json:"account" protobuf:"1"`
+	Amount           string  `json:"amount" protobuf:"2"`
+	Hash             string  `json:"hash" protobuf:"3"`
+	ConfirmationType string  `json:"confirmation_type" protobuf:"4"`
+	Block            WSBlock `json:"block" protobuf:"5"`
+
+	Time time.Time `json:"-" protobuf:"-"`
+}
+
+// WSClient is a client for the WebSocket server of a node, reconnecting
+// and re-subscribing automatically,
+// Url: the url of the WebSocket server (e.g. ws://localhost:7078),
+// Header: the HTTP headers of the handshake, e.g. for authentication (optional),
+// PingInterval: the interval between keepalive pings (default 30 seconds),
+// ReconnectDelay: the initial delay between reconnection attempts, doubled up to 30 seconds (default 1 second),
+// OnError: called with connection errors before reconnecting (optional).
+type WSClient struct {
+	Url            string
+	Header         http.Header // optional
+	PingInterval   time.Duration
+	ReconnectDelay time.Duration
+	OnError        func(err error)
+
+	mu            sync.Mutex
+	writeMu       sync.Mutex
+	conn          *websocket.Conn
+	subscribed    bool
+	accounts      map[string]bool
+	confirmations chan ConfirmationMessage
+	done          chan struct{}
+}
+
+// Connect connects to the WebSocket server and keeps the connection alive
+// until the context is done or Close is called,
+// ctx: the context closing the connection,
+// returns an error if the first connection failed.
+func (w *WSClient) Connect(ctx context.Context) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, w.Url, w.Header)
+
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.conn = conn
+	w.done = make(chan struct{})
+
+	if w.confirmations == nil {
+		w.confirmations = make(chan ConfirmationMessage, confirmationsBuffer)
+	}
+
+	w.mu.Unlock()
+
+	go w.run(ctx, conn)
+
+	return nil
+}
+
+// Confirmations returns the channel of confirmed blocks of the subscription,
+// closed when the client is closed.
+func (w *WSClient) Confirmations() <-chan ConfirmationMessage {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.confirmations == nil {
+		w.confirmations = make(chan ConfirmationMessage, confirmationsBuffer)
+	}
+
+	return w.confirmations
+}
+
+// SubscribeConfirmations subscribes to the confirmation topic,
+// accounts: the wallet addresses to filter the confirmations by (empty for all confirmations),
+// returns an error.
+func (w *WSClient) SubscribeConfirmations(accounts []string) error {
+	w.mu.Lock()
+	w.subscribed = true
+	w.accounts = map[string]bool{}
+
+	for _, a := range accounts {
+		w.accounts[a] = true
+	}
+
+	msg := w.subscribeMessage()
+	w.mu.Unlock()
+
+	return w.send(msg)
+}
+
+// UpdateAccounts updates the account filter of the confirmation subscription,
+// add: the wallet addresses to add to the filter,
+// del: the wallet addresses to remove from the filter,
+// returns an error.
+func (w *WSClient) UpdateAccounts(add, del []string) error {
+	w.mu.Lock()
+
+	if !w.subscribed {
+		w.mu.Unlock()
+		return fmt.Errorf("not subscribed to confirmations")
+	}
+
+	for _, a := range add {
+		w.accounts[a] = true
+	}
+
+	for _, a := range del {
+		delete(w.accounts, a)
+	}
+
+	w.mu.Unlock()
+
+	options := map[string]any{}
+
+	if len(add) > 0 {
+		options["accounts_add"] = add
+	}
+
+	if len(del) > 0 {
+		options["accounts_del"] = del
+	}
+
+	return w.send(map[string]any{
+		"action":  "update",
+		"topic":   "confirmation",
+		"options": options,
+	})
+}
+
+// Unsubscribe unsubscribes from the confirmation topic,
+// returns an error.
+func (w *WSClient) Unsubscribe() error {
+	w.mu.Lock()
+	w.subscribed = false
+	w.accounts = nil
+	w.mu.Unlock()
+
+	return w.send(map[string]any{
+		"action": "unsubscribe",
+		"topic":  "confirmation",
+	})
+}
+
+// Close closes the connection and the confirmations channel,
+// returns an error.
+func (w *WSClient) Close() error {
+	w.mu.Lock()
+	conn := w.conn
+	done := w.done
+	w.conn = nil
+	w.done = nil
+	w.mu.Unlock()
+
+	if done != nil {
+		close(done)
+	}
+
+	if conn == nil {
+		return nil
+	}
+
+	return conn.Close()
+}
+
+func (w *WSClient) subscribeMessage() map[string]any {
+	options := map[string]any{}
+
+	if len(w.accounts) > 0 {
+		accounts := make([]string, 0, len(w.accounts))
+
+		for a := range w.accounts {
+			accounts = append(accounts, a)
+		}
+
+		options["accounts"] = accounts
+	}
+
+	return map[string]any{
+		"action":  "subscribe",
+		"topic":   "confirmation",
+		"options": options,
+	}
+}
+
+func (w *WSClient) send(msg map[string]any) error {
+	w.mu.Lock()
+	conn := w.conn
+	w.mu.Unlock()
+
+	if conn == nil {
+		// sent on (re)connection
+		return nil
+	}
+
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	return conn.WriteJSON(msg)
+}
+
+func (w *WSClient) run(ctx context.Context, conn *websocket.Conn) {
+	w.mu.Lock()
+	done := w.done
+	confirmations := w.confirmations
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		w.confirmations = nil
+		w.mu.Unlock()
+
+		close(confirmations)
+	}()
+
+	for {
+		err := w.serve(ctx, done, conn)
+
+		select {
+		case <-ctx.Done():
+			w.Close()
+			return
+		case <-done:
+			return
+		default:
+		}
+
+		if w.OnError != nil {
+			w.OnError(err)
+		}
+
+		wait := w.ReconnectDelay
+
+		if wait <= 0 {
+			wait = time.Second
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				w.Close()
+				return
+			case <-done:
+				return
+			case <-time.After(wait):
+			}
+
+			conn, _, err = websocket.DefaultDialer.DialContext(ctx, w.Url, w.Header)
+
+			if err == nil {
+				break
+			}
+
+			if w.OnError != nil {
+				w.OnError(err)
+			}
+
+			if wait *= 2; wait > 30*time.Second {
+				wait = 30 * time.Second
+			}
+		}
+
+		w.mu.Lock()
+		w.conn = conn
+		resubscribe := w.subscribed
+		msg := w.subscribeMessage()
+		w.mu.Unlock()
+
+		if resubscribe {
+			w.send(msg)
+		}
+	}
+}
+
+// serve reads the messages of a connection and pings it until it fails or the client is closed.
+func (w *WSClient) serve(ctx context.Context, done chan struct{}, conn *websocket.Conn) error {
+	interval := w.PingInterval
+
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				conn.Close()
+				return
+			case <-done:
+				conn.Close()
+				return
+			case <-ticker.C:
+				w.writeMu.Lock()
+				err := conn.WriteJSON(map[string]any{"action": "ping"})
+				w.writeMu.Unlock()
+
+				if err != nil {
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(2 * interval))
+		_, data, err := conn.ReadMessage()
+
+		if err != nil {
+			conn.Close()
+			return err
+		}
+
+		var envelope struct {
+			Topic   string          `json:"topic"`
+			Time    string          `json:"time"`
+			Message json.RawMessage `json:"message"`
+		}
+
+		if err := json.Unmarshal(data, &envelope); err != nil || envelope.Topic != "confirmation" {
+			continue
+		}
+
+		var msg ConfirmationMessage
+
+		if err := json.Unmarshal(envelope.Message, &msg); err != nil {
+			continue
+		}
+
+		if ms, err := strconv.ParseInt(envelope.Time, 10, 64); err == nil {
+			msg.Time = time.UnixMilli(ms)
+		}
+
+		w.mu.Lock()
+		confirmations := w.confirmations
+		w.mu.Unlock()
+
+		select {
+		case confirmations <- msg:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-done:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package nanogo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/crypto/blake2b"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// WorkThresholdSend is the epoch 2 work threshold of send and change blocks.
+	WorkThresholdSend uint64 = 0xfffffff800000000
+	// WorkThresholdReceive is the epoch 2 work threshold of receive, open and epoch blocks.
+	WorkThresholdReceive uint64 = 0xfffffe0000000000
+	// WorkThresholdEpoch1 is the work threshold of every block before epoch 2.
+	WorkThresholdEpoch1 uint64 = 0xffffffc000000000
+)
+
+// WorkProvider generates work for a block root at a difficulty.
+type WorkProvider interface {
+	GenerateWork(hash string, difficulty uint64) (string, error)
+}
+
+// RPCWorkProvider is a WorkProvider using the work_generate RPC,
+// Client: the client of the node generating the work.
+type RPCWorkProvider struct {
+	Client WorkGenerator
+}
+
+// GenerateWork generates work with the work_generate RPC,
+// hash: the block root to generate work for,
+// difficulty: the difficulty of the work (0 for the node default),
+// returns the work or an error.
+func (p RPCWorkProvider) GenerateWork(hash string, difficulty uint64) (string, error) {
+	return p.Client.WorkGenerate(hash, difficulty)
+}
+
+// LocalWorkProvider is a WorkProvider computing work locally,
+// Threads: the number of goroutines computing work (default runtime.NumCPU()).
+type LocalWorkProvider struct {
+	Threads int
+}
+
+// GenerateWork generates work locally,
+// hash: the block root to generate work for,
+// difficulty: the difficulty of the work,
+// returns the work or an error.
+func (p LocalWorkProvider) GenerateWork(hash string, difficulty uint64) (string, error) {
+	return GenerateWorkLocalContext(context.Background(), hash, difficulty, p.Threads)
+}
+
+// WorkThreshold returns the epoch 2 work threshold of a block subtype,
+// subtype: the subtype of the block (send, receive, open, change or epoch),
+// returns the threshold.
+func WorkThreshold(subtype string) uint64 {
+	switch subtype {
+	case "receive", "open", "epoch":
+		return WorkThresholdReceive
+	default:
+		return WorkThresholdSend
+	}
+}
+
+// WorkRoot returns the root work is generated for, the previous block hash
+// or the public key of the account for open blocks,
+// block: the block to get the root of,
+// returns the root or an error.
+func WorkRoot(block Block) (string, error) {
+	if block.Previous == "0" || block.Previous == strings.Repeat("0", 64) {
+		pubKey, err := AddressToPublicKey(block.Account)
+
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%064X", pubKey), nil
+	}
+
+	return block.Previous, nil
+}
+
+// WorkValue computes the difficulty value of a work,
+// hash: the block root the work was generated for,
+// work: the work in hex,
+// returns the value or an error.
+func WorkValue(hash, work string) (uint64, error) {
+	root, err := decodeHash(hash)
+
+	if err != nil {
+		return 0, err
+	}
+
+	nonce, err := strconv.ParseUint(work, 16, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("could not parse work (%s)", work)
+	}
+
+	h, err := blake2b.New(8, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var buf [40]byte
+	binary.LittleEndian.PutUint64(buf[:8], nonce)
+	copy(buf[8:], root)
+	h.Write(buf[:])
+
+	return binary.LittleEndian.Uint64(h.Sum(nil)), nil
+}
+
+// ValidateWork checks if a work meets a difficulty,
+// hash: the block root the work was generated for,
+// work: the work in hex,
+// difficulty: the required difficulty,
+// returns true if the work is valid, false otherwise, or an error.
+func ValidateWork(hash, work string, difficulty uint64) (bool, error) {
+	value, err := WorkValue(hash, work)
+
+	if err != nil {
+		return false, err
+	}
+
+	return value >= difficulty, nil
+}
+
+// GenerateWorkLocal computes work locally on all CPU cores,
+// hash: the block root to generate work for,
+// difficulty: the difficulty of the work,
+// returns the work or an error.
+func GenerateWorkLocal(hash string, difficulty uint64) (string, error) {
+	return GenerateWorkLocalContext(context.Background(), hash, difficulty, 0)
+}
+
+// GenerateWorkLocalContext computes work locally until it is found or the context is done,
+// ctx: the context cancelling the computation,
+// hash: the block root to generate work for,
+// difficulty: the difficulty of the work,
+// threads: the number of goroutines computing work (0 for runtime.NumCPU()),
+// returns the work or an error.
+func GenerateWorkLocalContext(ctx context.Context, hash string, difficulty uint64, threads int) (string, error) {
+	root, err := decodeHash(hash)
+
+	if err != nil {
+		return "", err
+	}
+
+	if threads <= 0 {
+		threads = runtime.NumCPU()
+	}
+
+	var seed [8]byte
+
+	if _, err := rand.Read(seed[:]); err != nil {
+		return "", err
+	}
+
+	start := binary.LittleEndian.Uint64(seed[:])
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	found := make(chan uint64, threads)
+	var wg sync.WaitGroup
+
+	for t := 0; t < threads; t++ {
+		wg.Add(1)
+
+		go func(nonce uint64) {
+			defer wg.Done()
+
+			h, _ := blake2b.New(8, nil)
+			var buf [40]byte
+			var sum [8]byte
+			copy(buf[8:], root)
+
+			for i := 0; ; i++ {
+				if i%4096 == 0 && ctx.Err() != nil {
+					return
+				}
+
+				binary.LittleEndian.PutUint64(buf[:8], nonce)
+				h.Reset()
+				h.Write(buf[:])
+
+				if binary.LittleEndian.Uint64(h.Sum(sum[:0])) >= difficulty {
+					found <- nonce
+					return
+				}
+
+				nonce += uint64(threads)
+			}
+		}(start + uint64(t))
+	}
+
+	select {
+	case nonce := <-found:
+		cancel()
+		wg.Wait()
+
+		return fmt.Sprintf("%016x", nonce), nil
+	case <-ctx.Done():
+		wg.Wait()
+
+		return "", ctx.Err()
+	}
+}
+
+// WorkGenerate generates work for a block root with the work_generate RPC,
+// hash: the block root to generate work for,
+// difficulty: the difficulty of the work (0 for the node default),
+// returns the work or an error.
+func (c *Client) WorkGenerate(hash string, difficulty uint64) (string, error) {
+	data := map[string]any{
+		"action": "work_generate",
+		"hash":   hash,
+	}
+
+	if difficulty != 0 {
+		data["difficulty"] = fmt.Sprintf("%016x", difficulty)
+	}
+
+	res, err := c.RPC(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Work string `json:"work"`
+
+		Error any `json:"error"`
+	}
+	json.Unmarshal(res, &body)
+
+	if body.Error != nil {
+		return "", rpcError(body.Error)
+	}
+
+	return body.Work, nil
+}
+
+// generateWork generates work for a block of a subtype with the WorkProvider of the client,
+// at the threshold of the subtype and the epoch of the account or the DifficultyFloor if it is higher.
+func (c *Client) generateWork(block Block, subtype string) (string, error) {
+	root, err := WorkRoot(block)
+
+	if err != nil {
+		return "", err
+	}
+
+	difficulty := c.workThreshold(block.Account, subtype)
+
+	if c.Difficulty != nil {
+		floor := c.Difficulty.Send()
+
+		if subtype == "receive" || subtype == "open" || subtype == "epoch" {
+			floor = c.Difficulty.Receive()
+		}
+
+		if floor > difficulty {
+			difficulty = floor
+		}
+	}
+
+	var provider WorkProvider = RPCWorkProvider{Client: c}
+
+	if c.Work != nil {
+		provider = c.Work
+	}
+
+	stats := c.counters()
+	start := time.Now()
+	work, err := provider.GenerateWork(root, difficulty)
+	c.tracer.work(provider, time.Since(start))
+
+	if err != nil {
+		stats.workErrors.Add(1)
+		return "", err
+	}
+
+	stats.workGenerated.Add(1)
+	stats.workNanos.Add(int64(time.Since(start)))
+
+	return work, nil
+}
@@ -0,0 +1,150 @@
+package nanogo
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WorkStats is a WorkProvider recording the difficulty multipliers and solve times of
+// the work solved by a wrapped provider over a rolling window, for capacity planning
+// of local PoW deployments,
+// Provider: the wrapped provider,
+// Window: the rolling window of the samples (default 1 hour),
+// MaxSamples: the maximum count of samples kept (default 10000).
+type WorkStats struct {
+	Provider   WorkProvider
+	Window     time.Duration
+	MaxSamples int
+
+	mu      sync.Mutex
+	samples []workSample
+}
+
+type workSample struct {
+	at         time.Time
+	multiplier float64
+	duration   time.Duration
+}
+
+// WorkStatsSnapshot is the percentiles of the samples of a WorkStats,
+// Count: the count of samples in the window,
+// Multiplier: the difficulty multipliers of the solved work relative to the requested difficulty by percentile (50, 90, 99, 100),
+// SolveTime: the solve times by percentile (50, 90, 99, 100).
+type WorkStatsSnapshot struct {
+	Count      int                   `json:"count"`
+	Multiplier map[int]float64       `json:"multiplier"`
+	SolveTime  map[int]time.Duration `json:"solve_time"`
+}
+
+// GenerateWork generates work with the wrapped provider and records it,
+// hash: the block root to generate work for,
+// difficulty: the difficulty of the work,
+// returns the work or an error.
+func (s *WorkStats) GenerateWork(hash string, difficulty uint64) (string, error) {
+	start := time.Now()
+	work, err := s.Provider.GenerateWork(hash, difficulty)
+
+	if err != nil {
+		return "", err
+	}
+
+	if value, err := WorkValue(hash, work); err == nil {
+		s.Record(difficulty, value, time.Since(start))
+	}
+
+	return work, nil
+}
+
+// Record records solved work, for work not generated through the WorkStats,
+// difficulty: the requested difficulty,
+// value: the difficulty value of the work (WorkValue),
+// duration: the solve time.
+func (s *WorkStats) Record(difficulty, value uint64, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.samples = append(s.samples, workSample{at: now, multiplier: DifficultyMultiplier(value, difficulty), duration: duration})
+	s.prune(now)
+}
+
+// Snapshot returns the percentiles of the samples in the window.
+func (s *WorkStats) Snapshot() WorkStatsSnapshot {
+	s.mu.Lock()
+	s.prune(time.Now())
+	samples := append([]workSample{}, s.samples...)
+	s.mu.Unlock()
+
+	snapshot := WorkStatsSnapshot{Count: len(samples), Multiplier: map[int]float64{}, SolveTime: map[int]time.Duration{}}
+
+	if len(samples) == 0 {
+		return snapshot
+	}
+
+	multipliers := make([]float64, len(samples))
+	durations := make([]time.Duration, len(samples))
+
+	for i, sample := range samples {
+		multipliers[i] = sample.multiplier
+		durations[i] = sample.duration
+	}
+
+	sort.Float64s(multipliers)
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	for _, p := range []int{50, 90, 99, 100} {
+		i := percentileIndex(len(samples), p)
+		snapshot.Multiplier[p] = multipliers[i]
+		snapshot.SolveTime[p] = durations[i]
+	}
+
+	return snapshot
+}
+
+// prune drops the samples out of the window or above MaxSamples.
+func (s *WorkStats) prune(now time.Time) {
+	window := s.Window
+
+	if window <= 0 {
+		window = time.Hour
+	}
+
+	max := s.MaxSamples
+
+	if max <= 0 {
+		max = 10000
+	}
+
+	i := 0
+
+	for i < len(s.samples) && (now.Sub(s.samples[i].at) > window || len(s.samples)-i > max) {
+		i++
+	}
+
+	s.samples = s.samples[i:]
+}
+
+// percentileIndex returns the nearest-rank index of a percentile in n sorted samples.
+func percentileIndex(n, p int) int {
+	i := int(math.Ceil(float64(p)/100*float64(n))) - 1
+
+	if i < 0 {
+		return 0
+	}
+
+	return i
+}
+
+// DifficultyMultiplier returns the multiplier of a difficulty relative to a base difficulty,
+// value: the difficulty,
+// base: the base difficulty,
+// returns the multiplier.
+func DifficultyMultiplier(value, base uint64) float64 {
+	if value == math.MaxUint64 {
+		return math.Inf(1)
+	}
+
+	return float64(-base) / float64(-value)
+}